@@ -0,0 +1,111 @@
+package align
+
+import (
+	"io"
+	"strings"
+
+	"pgfp/data"
+)
+
+// GapState says whether an alignment column is part of a gap, and if so whether
+// it opens the gap or extends it.
+type GapState int
+
+const (
+	NoGap     GapState = iota // Both sequences have a base in this column
+	GapOpen                   // The first column of a run of gaps in one sequence
+	GapExtend                 // A further column of the same run
+)
+
+// GapStates classifies every column of an alignment as an opened gap, an extended
+// gap, or no gap. A run of gaps in one sequence is opened once, which is how
+// SmithWatermanAffine charges gapOpen, so the states show which columns paid the
+// opening cost. A gap in the query directly after a gap in the reference is a new gap.
+//
+// Returns:
+//   - ([]GapState): The state of each column, in alignment order. Empty for an
+//     empty alignment.
+func (r AlignmentResult) GapStates() []GapState {
+	length := min(len(r.AlignedQuery), len(r.AlignedRef))
+	states := make([]GapState, length)
+
+	for i := 0; i < length; i++ {
+		switch {
+		case r.AlignedQuery[i] == '-':
+			states[i] = gapState(i > 0 && r.AlignedQuery[i-1] == '-')
+		case r.AlignedRef[i] == '-':
+			states[i] = gapState(i > 0 && r.AlignedRef[i-1] == '-')
+		}
+	}
+
+	return states
+}
+
+// gapState returns GapExtend for a gap column that continues a gap, GapOpen otherwise.
+func gapState(extends bool) GapState {
+	if extends {
+		return GapExtend
+	}
+	return GapOpen
+}
+
+// A2MGapMarks are the characters written for gap columns in A2M output. Standard
+// A2M writes '-' for every column the query skips and '.' for every column the
+// reference skips; some conventions mark the first column of an affine gap apart
+// from its extensions. A zero field keeps the standard character, so the zero
+// value writes plain A2M.
+type A2MGapMarks struct {
+	DeleteOpen   byte // Query row, first column of a gap in the query
+	DeleteExtend byte // Query row, further columns of a gap in the query
+	InsertOpen   byte // Reference row, first column of a gap in the reference
+	InsertExtend byte // Reference row, further columns of a gap in the reference
+}
+
+// gapMark returns the character for a gap column, falling back to the standard one.
+func gapMark(state GapState, open, extend, standard byte) byte {
+	c := open
+	if state == GapExtend {
+		c = extend
+	}
+	if c == 0 {
+		return standard
+	}
+	return c
+}
+
+// WriteA2M writes an alignment as two A2M records with the reference as the master
+// sequence. Columns where the reference has a base are match columns: bases are
+// uppercase and a gap in the query is a deletion. Columns where the reference has
+// a gap are insert columns: the query base is lowercase and the reference gets an
+// insert mark. Only the aligned region is written, wrapped as by data.WriteFASTA.
+//
+// Parameters:
+//   - w (io.Writer): The destination for the A2M text.
+//   - queryID (string): The header ID of the query record.
+//   - refID (string): The header ID of the reference record.
+//   - result (AlignmentResult): The alignment to write.
+//   - marks (A2MGapMarks): The characters for opened and extended gaps.
+//
+// Returns:
+//   - (error): The first write error, if any.
+func WriteA2M(w io.Writer, queryID, refID string, result AlignmentResult, marks A2MGapMarks) error {
+	states := result.GapStates()
+	lowerQuery := strings.ToLower(result.AlignedQuery)
+	queryRow := []byte(strings.ToUpper(result.AlignedQuery)[:len(states)])
+	refRow := []byte(strings.ToUpper(result.AlignedRef)[:len(states)])
+
+	for i, state := range states {
+		switch {
+		case queryRow[i] == '-':
+			queryRow[i] = gapMark(state, marks.DeleteOpen, marks.DeleteExtend, '-')
+		case refRow[i] == '-':
+			queryRow[i] = lowerQuery[i]
+			refRow[i] = gapMark(state, marks.InsertOpen, marks.InsertExtend, '.')
+		}
+	}
+
+	return data.WriteFASTA(w, []data.Record{
+		{ID: refID, Sequence: string(refRow)},
+		{ID: queryID, Sequence: string(queryRow)},
+	})
+}
@@ -0,0 +1,52 @@
+package align
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWriteA2M checks the A2M rows for an affine alignment with a five-base gap,
+// with standard marks and with opened and extended gaps marked apart
+func TestWriteA2M(t *testing.T) {
+	short, long := "GATTACAGATTACA", "GATTACATTTTTGATTACA"
+
+	deletion := SmithWatermanAffine(short, long, -3, -1)
+	states := deletion.GapStates()
+	want := []GapState{NoGap, GapOpen, GapExtend, GapExtend, GapExtend, GapExtend, NoGap}
+	for i, state := range want {
+		if states[6+i] != state {
+			t.Errorf("Column %d: expected gap state %d, got %d", 6+i, state, states[6+i])
+		}
+	}
+
+	insertion := SmithWatermanAffine(long, short, -3, -1)
+	marks := A2MGapMarks{DeleteExtend: '~', InsertExtend: ','}
+
+	tests := []struct {
+		name   string
+		result AlignmentResult
+		marks  A2MGapMarks
+		want   string
+	}{
+		{"standard deletion", deletion, A2MGapMarks{}, ">ref\nGATTACATTTTTGATTACA\n>query\nGATTACA-----GATTACA\n"},
+		{"marked deletion", deletion, marks, ">ref\nGATTACATTTTTGATTACA\n>query\nGATTACA-~~~~GATTACA\n"},
+		{"standard insertion", insertion, A2MGapMarks{}, ">ref\nGATTACA.....GATTACA\n>query\nGATTACAtttttGATTACA\n"},
+		{"marked insertion", insertion, marks, ">ref\nGATTACA.,,,,GATTACA\n>query\nGATTACAtttttGATTACA\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteA2M(&buf, "query", "ref", tt.result, tt.marks); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, buf.String(), tt.want)
+		}
+	}
+
+	// Adjacent gaps in different sequences are separate gaps
+	adjacent := AlignmentResult{AlignedQuery: "AC-T", AlignedRef: "A-GT"}
+	if got := adjacent.GapStates(); got[1] != GapOpen || got[2] != GapOpen {
+		t.Errorf("Expected both gaps opened, got %v", got)
+	}
+}
@@ -0,0 +1,145 @@
+package align
+
+import "math"
+
+// negInf stands in for minus infinity in the gap matrices. It is far enough from
+// math.MinInt that adding penalties to it cannot overflow.
+const negInf = math.MinInt32
+
+// SmithWatermanAffine performs local alignment with affine gap penalties (Gotoh's
+// algorithm). A gap of length L scores gapOpen + L*gapExtend, so one long indel
+// costs less than several short ones, while SmithWaterman charges GapPenalty for
+// every gap column alike. Besides the main matrix it keeps the best score of
+// alignments ending in a gap in either sequence, so extending an open gap costs
+// only gapExtend. Bases score MatchScore and MismatchScore.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - gapOpen (int): The score added once per gap, usually negative.
+//   - gapExtend (int): The score added for every gap column, usually negative.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. ScoreMatrix holds the best score of
+//     any alignment ending at each cell.
+func SmithWatermanAffine(query, reference string, gapOpen, gapExtend int) AlignmentResult {
+	m, n := len(query), len(reference)
+	sc := defaultScorer()
+
+	// h is the best score ending at a cell, e the best ending in a gap in the
+	// query (a reference base against '-'), f the best ending in a gap in the
+	// reference
+	h := make([][]int, m+1)
+	e := make([][]int, m+1)
+	f := make([][]int, m+1)
+	for i := range h {
+		h[i] = make([]int, n+1)
+		e[i] = make([]int, n+1)
+		f[i] = make([]int, n+1)
+		for j := range e[i] {
+			e[i][j], f[i][j] = negInf, negInf
+		}
+	}
+
+	best := alignerCell{}
+
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			e[i][j] = max(h[i][j-1]+gapOpen+gapExtend, e[i][j-1]+gapExtend)
+			f[i][j] = max(h[i-1][j]+gapOpen+gapExtend, f[i-1][j]+gapExtend)
+			h[i][j] = smithMax(0, h[i-1][j-1]+sc.score(query, reference, i-1, j-1), e[i][j], f[i][j])
+
+			if cell := (alignerCell{score: h[i][j], row: i, col: j}); isBetterCell(cell, best) {
+				best = cell
+			}
+		}
+	}
+
+	alignedQuery, alignedRef, startRow, startCol := affineTraceback(h, e, f, query, reference, sc, gapOpen, gapExtend, best.row, best.col)
+
+	return AlignmentResult{
+		ScoreMatrix:   h,
+		MaxScore:      best.score,
+		MaxRow:        best.row,
+		MaxCol:        best.col,
+		AlignedQuery:  alignedQuery,
+		AlignedRef:    alignedRef,
+		QueryStart:    startRow,
+		QueryEnd:      best.row,
+		RefStart:      startCol,
+		RefEnd:        best.col,
+		ClippedPrefix: query[:startRow],
+		ClippedSuffix: query[best.row:],
+		NoAlignment:   best.score == 0,
+	}
+}
+
+// affineTraceback reconstructs an affine-gap alignment from its three matrices,
+// tracking which matrix the path is in so that a gap is opened exactly once.
+//
+// Parameters:
+//   - h, e, f ([][]int): The best scores ending at each cell, in a query gap, and in a reference gap.
+//   - query (string): The query DNA sequence.
+//   - reference (string): The reference DNA sequence.
+//   - sc (scorer): The substitution scores used to fill h.
+//   - gapOpen, gapExtend (int): The gap scores used to fill e and f.
+//   - row, col (int): The cell the alignment ends in.
+//
+// Returns:
+//   - (string, string, int, int): The aligned query and reference sequences, and the
+//     0-based start of the alignment in the query and the reference.
+func affineTraceback(h, e, f [][]int, query, reference string, sc scorer, gapOpen, gapExtend, row, col int) (string, string, int, int) {
+	// Build the alignment back to front and reverse it once at the end
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(row, col))
+	alignedRef := make([]byte, 0, MaxAlignmentLength(row, col))
+
+	// The matrix the path is currently in
+	const (
+		inH = iota
+		inE
+		inF
+	)
+	state := inH
+
+trace:
+	for row > 0 && col > 0 {
+		switch state {
+		case inH:
+			switch {
+			case h[row][col] == 0:
+				// The local alignment starts here
+				break trace
+			case h[row][col] == h[row-1][col-1]+sc.score(query, reference, row-1, col-1):
+				alignedQuery = append(alignedQuery, query[row-1])
+				alignedRef = append(alignedRef, reference[col-1])
+				row--
+				col--
+			case h[row][col] == e[row][col]:
+				state = inE
+			default:
+				state = inF
+			}
+		case inE:
+			// Gap in query; the gap was opened here if it came straight from h
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
+			if e[row][col] == h[row][col-1]+gapOpen+gapExtend {
+				state = inH
+			}
+			col--
+		case inF:
+			// Gap in reference
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
+			if f[row][col] == h[row-1][col]+gapOpen+gapExtend {
+				state = inH
+			}
+			row--
+		}
+	}
+
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return string(alignedQuery), string(alignedRef), row, col
+}
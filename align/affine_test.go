@@ -0,0 +1,38 @@
+package align
+
+import "testing"
+
+// TestSmithWatermanAffine checks that a five-base insertion is aligned as one gap,
+// which scores higher than the same gap under the linear GapPenalty
+func TestSmithWatermanAffine(t *testing.T) {
+	query, reference := "GATTACAGATTACA", "GATTACATTTTTGATTACA"
+
+	// 14 matches score 28, less 3 to open the gap and 1 for each of its 5 columns
+	result := SmithWatermanAffine(query, reference, -3, -1)
+	if result.AlignedQuery != "GATTACA-----GATTACA" || result.AlignedRef != reference {
+		t.Errorf("Unexpected alignment:\n%s\n%s", result.AlignedQuery, result.AlignedRef)
+	}
+	if result.MaxScore != 20 || result.QueryEnd != 14 || result.RefEnd != 19 {
+		t.Errorf("Expected score 20 ending at 14/19, got %d ending at %d/%d", result.MaxScore, result.QueryEnd, result.RefEnd)
+	}
+	if result.MaxRow != 14 || result.MaxCol != 19 || result.ScoreMatrix[result.MaxRow][result.MaxCol] != result.MaxScore {
+		t.Errorf("Expected the max score at [14,19], got [%d,%d]", result.MaxRow, result.MaxCol)
+	}
+
+	// A linear penalty charges the full GapPenalty for every column
+	if linear := SmithWaterman(query, reference); linear.MaxScore != 18 {
+		t.Errorf("Expected the linear score 18, got %d", linear.MaxScore)
+	}
+
+	// Query bases outside the alignment are reported as clipped
+	clipped := SmithWatermanAffine("CC"+query+"GG", reference, -3, -1)
+	if clipped.ClippedPrefix != "CC" || clipped.ClippedSuffix != "GG" || clipped.AlignedQuery != result.AlignedQuery {
+		t.Errorf("Expected CC and GG clipped around the same alignment, got %q, %q, and %s",
+			clipped.ClippedPrefix, clipped.ClippedSuffix, clipped.AlignedQuery)
+	}
+
+	// Unrelated sequences give an empty alignment
+	if none := SmithWatermanAffine("AAAA", "CCCC", -3, -1); none.MaxScore != 0 || none.AlignedQuery != "" {
+		t.Errorf("Expected no alignment, got %+v", none)
+	}
+}
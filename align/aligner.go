@@ -0,0 +1,58 @@
+package align
+
+// Aligner runs sequential Smith-Waterman alignments with a score matrix buffer
+// that is reused from one call to the next. Aligning many sequences of similar
+// length, as in a batch, then allocates the matrix once instead of once per
+// alignment. The buffer grows to fit the largest pair seen so far.
+//
+// An Aligner is not safe for concurrent use; give each goroutine its own.
+type Aligner struct {
+	cells []int   // Backing storage for every row of the matrix
+	rows  [][]int // Row slices into cells, sized for the current alignment
+}
+
+// NewAligner creates an aligner with an empty matrix buffer.
+//
+// Returns:
+//   - (*Aligner): A ready-to-use aligner.
+func NewAligner() *Aligner {
+	return &Aligner{}
+}
+
+// Align performs the same local alignment as SmithWaterman, filling the aligner's
+// reused matrix instead of allocating a new one.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. ScoreMatrix is nil, since the matrix
+//     is overwritten by the next call.
+func (a *Aligner) Align(query, reference string) AlignmentResult {
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), matrix: a.matrix(len(query), len(reference))})
+	result.ScoreMatrix = nil
+	return result
+}
+
+// matrix returns an (m+1)x(n+1) view of the buffer with its first row and column
+// zeroed, growing the buffer only when it is too small. The remaining cells keep
+// stale values, which is safe because the fill writes each of them before reading it.
+func (a *Aligner) matrix(m, n int) [][]int {
+	size := (m + 1) * (n + 1)
+	if cap(a.cells) < size {
+		a.cells = make([]int, size)
+	}
+	if cap(a.rows) < m+1 {
+		a.rows = make([][]int, m+1)
+	}
+
+	cells, rows := a.cells[:size], a.rows[:m+1]
+	for i := range rows {
+		rows[i] = cells[i*(n+1) : (i+1)*(n+1)]
+		rows[i][0] = 0
+	}
+	clear(rows[0])
+
+	return rows
+}
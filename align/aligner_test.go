@@ -0,0 +1,34 @@
+package align
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAlignerReuse checks that an Aligner matches SmithWaterman as its buffer
+// grows, shrinks, and changes shape between calls
+func TestAlignerReuse(t *testing.T) {
+	pairs := [][2]string{
+		{"GATTACA", "GATCACA"},
+		{generateRandomDNA(120), generateRandomDNA(80)},
+		{"ACGT", "TTTTACGTTTTT"},
+		{generateRandomDNA(60), generateRandomDNA(150)},
+		{"AAAA", "CCCC"},
+		{"", "GATTACA"},
+		{"GATTACAGATTACA", "GATTACAGATTACA"},
+	}
+
+	aligner := NewAligner()
+	for _, pair := range pairs {
+		want := SmithWaterman(pair[0], pair[1])
+		got := aligner.Align(pair[0], pair[1])
+
+		if got.ScoreMatrix != nil {
+			t.Errorf("Expected no ScoreMatrix from a reused buffer")
+		}
+		want.ScoreMatrix = nil
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Aligner on %q/%q = %+v, want %+v", pair[0], pair[1], got, want)
+		}
+	}
+}
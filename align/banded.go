@@ -0,0 +1,102 @@
+package align
+
+// SmithWatermanBanded performs local alignment computing only the cells within band
+// of the main diagonal (|i-j| <= band); cells outside the band are treated as zero.
+// For high-identity reads of about the same length as the reference this needs
+// O(m×band) time and memory instead of O(m×n).
+//
+// A band that is too small misses alignments that leave it: an indel longer than
+// band, or a query whose match lies more than band bases away from the diagonal,
+// is clipped or missed, and the result can score lower than SmithWaterman. Within
+// the band, ties are broken as in SmithWaterman.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - band (int): The maximum distance from the diagonal to compute. Negative values
+//     are treated as zero.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. ScoreMatrix is nil, since only the
+//     band of the matrix is ever stored.
+func SmithWatermanBanded(query, reference string, band int) AlignmentResult {
+	m, n := len(query), len(reference)
+	band = max(band, 0)
+	sc := defaultScorer()
+
+	// rows[i] holds columns first(i)..last(i) of row i
+	first := func(i int) int { return max(0, i-band) }
+	last := func(i int) int { return min(n, i+band) }
+	rows := make([][]int, m+1)
+	for i := range rows {
+		if width := last(i) - first(i) + 1; width > 0 {
+			rows[i] = make([]int, width)
+		}
+	}
+	cell := func(i, j int) int {
+		if j < first(i) || j > last(i) {
+			return 0
+		}
+		return rows[i][j-first(i)]
+	}
+
+	best := alignerCell{}
+
+	// Fill the band
+	for i := 1; i <= m; i++ {
+		for j := max(1, first(i)); j <= last(i); j++ {
+			match := sc.score(query, reference, i-1, j-1)
+			score := smithMax(0, cell(i-1, j-1)+match, cell(i-1, j)+sc.gap, cell(i, j-1)+sc.gap)
+			rows[i][j-first(i)] = score
+
+			if candidate := (alignerCell{score: score, row: i, col: j}); isBetterCell(candidate, best) {
+				best = candidate
+			}
+		}
+	}
+
+	// Trace back from the maximum, preferring the diagonal as SmithWaterman does
+	row, col := best.row, best.col
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(row, col))
+	alignedRef := make([]byte, 0, MaxAlignmentLength(row, col))
+	for row > 0 && col > 0 && cell(row, col) > 0 {
+		current := cell(row, col)
+		match := sc.score(query, reference, row-1, col-1)
+
+		if current == cell(row-1, col-1)+match {
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, reference[col-1])
+			row--
+			col--
+		} else if current == cell(row-1, col)+sc.gap {
+			// Gap in reference
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
+			row--
+		} else if current == cell(row, col-1)+sc.gap {
+			// Gap in query
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
+			col--
+		} else {
+			break
+		}
+	}
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return AlignmentResult{
+		MaxScore:      best.score,
+		MaxRow:        best.row,
+		MaxCol:        best.col,
+		AlignedQuery:  string(alignedQuery),
+		AlignedRef:    string(alignedRef),
+		QueryStart:    row,
+		QueryEnd:      best.row,
+		RefStart:      col,
+		RefEnd:        best.col,
+		NoAlignment:   best.score == 0,
+		ClippedPrefix: query[:row],
+		ClippedSuffix: query[best.row:],
+	}
+}
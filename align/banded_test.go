@@ -0,0 +1,47 @@
+package align
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanBanded checks that a band wide enough for the indels reproduces the full alignment
+func TestSmithWatermanBanded(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	reference := make([]byte, 500)
+	for i := range reference {
+		reference[i] = "ACGT"[r.Intn(4)]
+	}
+
+	// A read with a few substitutions, a 2-base deletion, and a 3-base insertion
+	read := string(reference[:100]) + "A" + string(reference[101:200]) + string(reference[202:350]) +
+		"GGG" + string(reference[350:])
+
+	full := SmithWaterman(read, string(reference))
+	banded := SmithWatermanBanded(read, string(reference), 10)
+
+	if banded.MaxScore != full.MaxScore || banded.AlignedQuery != full.AlignedQuery || banded.AlignedRef != full.AlignedRef {
+		t.Errorf("Banded alignment (score %d) differs from the full alignment (score %d)", banded.MaxScore, full.MaxScore)
+	}
+	if banded.QueryStart != full.QueryStart || banded.QueryEnd != full.QueryEnd ||
+		banded.RefStart != full.RefStart || banded.RefEnd != full.RefEnd {
+		t.Errorf("Banded coordinates [%d,%d)/[%d,%d), want [%d,%d)/[%d,%d)",
+			banded.QueryStart, banded.QueryEnd, banded.RefStart, banded.RefEnd,
+			full.QueryStart, full.QueryEnd, full.RefStart, full.RefEnd)
+	}
+	if banded.ScoreMatrix != nil {
+		t.Error("Expected no score matrix from the banded aligner")
+	}
+
+	// A deletion longer than the band cannot be crossed, so the score drops
+	gapped := string(reference[:200]) + string(reference[230:])
+	if narrow := SmithWatermanBanded(gapped, string(reference), 10); narrow.MaxScore >= SmithWaterman(gapped, string(reference)).MaxScore {
+		t.Errorf("Expected a 10-base band to miss a 30-base deletion, got score %d", narrow.MaxScore)
+	}
+
+	// A zero band allows only the diagonal
+	if result := SmithWatermanBanded("GATTACA", "GATTACA"+strings.Repeat("C", 5), 0); result.AlignedQuery != "GATTACA" {
+		t.Errorf("Expected the diagonal alignment with a zero band, got %s", result.AlignedQuery)
+	}
+}
@@ -0,0 +1,46 @@
+package align
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// bedMaxScore is the largest score the BED format allows
+const bedMaxScore = 1000
+
+// WriteBED writes the reference interval each query aligned to as one BED line:
+// chrom, start, end, name, and score, tab-separated. Coordinates are 0-based and
+// half-open, as in AlignmentResult and the BED convention, so they can be loaded
+// into a genome browser directly. Results without an alignment are skipped.
+//
+// Parameters:
+//   - w (io.Writer): The destination for the BED text.
+//   - refName (string): The chrom name for every line.
+//   - results ([]AlignmentResult): The alignments, one per query.
+//   - queryNames ([]string): The name of each query, in the same order as results.
+//
+// Returns:
+//   - (error): An error if the number of names does not match the number of results,
+//     or if the write fails.
+func WriteBED(w io.Writer, refName string, results []AlignmentResult, queryNames []string) error {
+	if len(queryNames) != len(results) {
+		return fmt.Errorf("got %d query names for %d results", len(queryNames), len(results))
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for i, result := range results {
+		if result.NoAlignment {
+			continue
+		}
+
+		// BED scores run from 0 to 1000
+		score := min(max(result.MaxScore, 0), bedMaxScore)
+		if _, err := fmt.Fprintf(bw, "%s\t%d\t%d\t%s\t%d\n", refName, result.RefStart, result.RefEnd, queryNames[i], score); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
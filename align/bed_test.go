@@ -0,0 +1,44 @@
+package align
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestWriteBED checks that each line carries the alignment's reference start and end
+func TestWriteBED(t *testing.T) {
+	reference := "CCCCCGATTACACCCCCTTGGAACCCC"
+	queries := []string{"GATTACA", "TTGGAA", "NNNN"}
+	names := []string{"read1", "read2", "read3"}
+
+	results := make([]AlignmentResult, len(queries))
+	for i, query := range queries {
+		results[i] = SmithWaterman(query, reference)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBED(&buf, "chr1", results, names); err != nil {
+		t.Fatalf("WriteBED returned an error: %v", err)
+	}
+
+	// read3 shares no positive-scoring region with the reference and is skipped
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 BED lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("chr1\t%d\t%d\t%s\t%d", results[i].RefStart, results[i].RefEnd, names[i], results[i].MaxScore)
+		if line != want {
+			t.Errorf("Line %d = %q, want %q", i, line, want)
+		}
+	}
+	if lines[0] != "chr1\t5\t12\tread1\t14" {
+		t.Errorf("Expected GATTACA at [5,12), got %q", lines[0])
+	}
+
+	if err := WriteBED(&bytes.Buffer{}, "chr1", results, names[:2]); err == nil {
+		t.Error("Expected an error when names and results differ in length")
+	}
+}
@@ -3,6 +3,7 @@ package align
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -64,6 +65,74 @@ func BenchmarkParallelSmithWaterman(b *testing.B) {
 	}
 }
 
+// BenchmarkTiledParallelSmithWaterman compares the block-tiled fill with the
+// per-cell wave-front implementation on the same inputs.
+func BenchmarkTiledParallelSmithWaterman(b *testing.B) {
+	sequenceLengths := []int{500, 1000, 2000}
+	workers := runtime.GOMAXPROCS(0)
+
+	for _, length := range sequenceLengths {
+		query := generateRandomDNA(length)
+		reference := generateRandomDNA(length)
+
+		b.Run(fmt.Sprintf("Length-%d/WaveFront", length), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = ParallelSmithWaterman(query, reference, workers).MaxScore
+			}
+		})
+
+		for _, blockSize := range []int{32, 64, 128} {
+			b.Run(fmt.Sprintf("Length-%d/Tiled-%d", length, blockSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = TiledParallelSmithWaterman(query, reference, blockSize, workers).MaxScore
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkTraceback benchmarks the traceback alone on a long self-alignment,
+// where every column of the alignment is reconstructed from the score matrix.
+func BenchmarkTraceback(b *testing.B) {
+	for _, length := range []int{1000, 5000} {
+		b.Run(fmt.Sprintf("Length-%d", length), func(b *testing.B) {
+			sequence := generateRandomDNA(length)
+			result := SmithWaterman(sequence, sequence)
+			sc := defaultScorer()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				alignedQuery, _, _, _, _ := traceback(result.ScoreMatrix, sequence, sequence, sc,
+					result.QueryEnd, result.RefEnd, 0, false, nil)
+				_ = alignedQuery
+			}
+		})
+	}
+}
+
+// BenchmarkBandedSmithWaterman compares the banded aligner with the full matrix on
+// a high-identity read, the case banding is meant for.
+func BenchmarkBandedSmithWaterman(b *testing.B) {
+	for _, length := range []int{1000, 5000} {
+		reference := generateRandomDNA(length)
+		read := reference[:length/2] + "A" + reference[length/2+1:]
+
+		b.Run(fmt.Sprintf("Length-%d/Full", length), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = SmithWaterman(read, reference).MaxScore
+			}
+		})
+		b.Run(fmt.Sprintf("Length-%d/Band-16", length), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = SmithWatermanBanded(read, reference, 16).MaxScore
+			}
+		})
+	}
+}
+
 // BenchmarkBatchSequentialSmithWaterman benchmarks running multiple alignments sequentially.
 func BenchmarkBatchSequentialSmithWaterman(b *testing.B) {
 	sequenceLength := 500
@@ -168,7 +237,7 @@ func TestSequentialVsParallel(t *testing.T) {
 				}
 
 				// Compare aligned sequences (allowing for equivalent alignments)
-				if !areAlignmentsEquivalent(seqResult.AlignedQuery, seqResult.AlignedRef,
+				if !AlignmentsEquivalent(seqResult.AlignedQuery, seqResult.AlignedRef,
 					parResult.AlignedQuery, parResult.AlignedRef) {
 					t.Logf("Alignment mismatch with %d workers", workers)
 					t.Logf("Sequential: \nQuery: %s\nRef:   %s",
@@ -186,39 +255,80 @@ func TestSequentialVsParallel(t *testing.T) {
 	}
 }
 
-// areAlignmentsEquivalent checks if two alignments are functionally equivalent.
-// Sometimes different but equally valid alignments can be produced.
-func areAlignmentsEquivalent(query1, ref1, query2, ref2 string) bool {
-	// First check exact match
-	if query1 == query2 && ref1 == ref2 {
-		return true
-	}
+// BenchmarkRepeatedParallelAlignment compares repeated parallel alignments that
+// spawn goroutines per call with a reusable ParallelAligner pool.
+func BenchmarkRepeatedParallelAlignment(b *testing.B) {
+	query := generateRandomDNA(500)
+	reference := generateRandomDNA(500)
+	workers := runtime.GOMAXPROCS(0)
+
+	b.Run("PerCall", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result := ParallelSmithWaterman(query, reference, workers)
+			_ = result.MaxScore
+		}
+	})
+
+	b.Run("Pool", func(b *testing.B) {
+		aligner := NewParallelAligner(workers)
+		defer aligner.Close()
 
-	// If lengths differ, they're not equivalent
-	if len(query1) != len(query2) || len(ref1) != len(ref2) {
-		return false
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			result := aligner.Align(query, reference)
+			_ = result.MaxScore
+		}
+	})
+}
+
+// BenchmarkBatchMatrixReuse compares aligning a batch of same-length references
+// with a fresh matrix per call against one reused Aligner.
+func BenchmarkBatchMatrixReuse(b *testing.B) {
+	query := generateRandomDNA(500)
+	references := make([]string, 16)
+	for i := range references {
+		references[i] = generateRandomDNA(500)
 	}
 
-	// Check if the alignments represent the same matching bases
-	// This allows for differences in how gaps are distributed
-	score1 := calculateAlignmentScore(query1, ref1)
-	score2 := calculateAlignmentScore(query2, ref2)
+	b.Run("Allocate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, reference := range references {
+				_ = SmithWaterman(query, reference).MaxScore
+			}
+		}
+	})
+
+	b.Run("Reuse", func(b *testing.B) {
+		aligner := NewAligner()
 
-	// If scores are the same, consider the alignments equivalent
-	return score1 == score2
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, reference := range references {
+				_ = aligner.Align(query, reference).MaxScore
+			}
+		}
+	})
 }
 
-// calculateAlignmentScore computes the score of an alignment.
-func calculateAlignmentScore(query, reference string) int {
-	score := 0
-	for i := 0; i < len(query); i++ {
-		if query[i] == '-' || reference[i] == '-' {
-			score += GapPenalty
-		} else if query[i] == reference[i] {
-			score += MatchScore
-		} else {
-			score += MismatchScore
+// BenchmarkEarlyExit compares a full alignment of unrelated sequences with one
+// that gives up once the minimum score is out of reach.
+func BenchmarkEarlyExit(b *testing.B) {
+	query := strings.Repeat("AC", 500)
+	reference := strings.Repeat("GT", 500)
+
+	b.Run("Full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = SmithWaterman(query, reference).MaxScore
 		}
-	}
-	return score
+	})
+
+	b.Run("EarlyExit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = SmithWatermanEarlyExit(query, reference, 100)
+		}
+	})
 }
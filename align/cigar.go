@@ -0,0 +1,73 @@
+package align
+
+import "strconv"
+
+// CIGAR returns the alignment as a SAM CIGAR string, such as "2S5M1I3M2D4M".
+// Match and mismatch columns are both reported as M, gaps in the reference as I
+// (bases only in the query), and gaps in the query as D (bases only in the
+// reference). Query bases outside the local alignment, ClippedPrefix and
+// ClippedSuffix, are reported as leading and trailing soft clips (S), so the
+// operations consume the whole query as SAM requires. Both clips are taken from
+// those fields, so a hand-built result without them gets no soft clips.
+//
+// Returns:
+//   - (string): The CIGAR string, or "*" (SAM's placeholder) for an empty alignment.
+func (r AlignmentResult) CIGAR() string {
+	return r.cigar(false)
+}
+
+// ExtendedCIGAR returns the alignment as a CIGAR string that distinguishes
+// matches (=) from mismatches (X) instead of reporting both as M.
+//
+// Returns:
+//   - (string): The extended CIGAR string, or "*" for an empty alignment.
+func (r AlignmentResult) ExtendedCIGAR() string {
+	return r.cigar(true)
+}
+
+// cigar builds the CIGAR string, using =/X for aligned columns when extended is set.
+func (r AlignmentResult) cigar(extended bool) string {
+	if len(r.AlignedQuery) == 0 {
+		return "*"
+	}
+
+	var cigar []byte
+	appendOp := func(length int, op byte) {
+		if length > 0 {
+			cigar = strconv.AppendInt(cigar, int64(length), 10)
+			cigar = append(cigar, op)
+		}
+	}
+
+	appendOp(len(r.ClippedPrefix), 'S')
+
+	var runOp byte
+	runLength := 0
+	for i := 0; i < len(r.AlignedQuery); i++ {
+		var op byte
+		switch q, ref := r.AlignedQuery[i], r.AlignedRef[i]; {
+		case q == '-':
+			op = 'D'
+		case ref == '-':
+			op = 'I'
+		case !extended:
+			op = 'M'
+		case q == ref:
+			op = '='
+		default:
+			op = 'X'
+		}
+
+		// Collapse consecutive columns with the same operation
+		if op != runOp {
+			appendOp(runLength, runOp)
+			runOp, runLength = op, 0
+		}
+		runLength++
+	}
+	appendOp(runLength, runOp)
+
+	appendOp(len(r.ClippedSuffix), 'S')
+
+	return string(cigar)
+}
@@ -0,0 +1,78 @@
+package align
+
+import "testing"
+
+// TestCIGAR checks CIGAR strings for alignments with clips, indels, and mismatches
+func TestCIGAR(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   AlignmentResult
+		cigar    string
+		extended string
+	}{
+		{
+			name:     "exact match",
+			result:   SmithWaterman("GATTACA", "GATTACA"),
+			cigar:    "7M",
+			extended: "7=",
+		},
+		{
+			name: "indels and a mismatch with soft clips",
+			result: AlignmentResult{
+				AlignedQuery:  "GATTAC-AGATCAGTTAGA",
+				AlignedRef:    "GATTACCAG--CAGATAGA",
+				QueryStart:    2,
+				ClippedPrefix: "TT",
+				ClippedSuffix: "C",
+			},
+			cigar:    "2S6M1D2M2I8M1S",
+			extended: "2S6=1D2=2I3=1X4=1S",
+		},
+		{
+			name: "trailing clip only",
+			result: AlignmentResult{
+				AlignedQuery:  "GATT",
+				AlignedRef:    "GATT",
+				QueryEnd:      4,
+				ClippedSuffix: "ACA",
+			},
+			cigar:    "4M3S",
+			extended: "4=3S",
+		},
+		{
+			name: "clips taken from the clipped bases, not the coordinates",
+			result: AlignmentResult{
+				AlignedQuery: "TACA",
+				AlignedRef:   "TACA",
+				QueryStart:   3,
+				QueryEnd:     7,
+			},
+			cigar:    "4M",
+			extended: "4=",
+		},
+		{
+			name:     "empty alignment",
+			result:   SmithWaterman("AAAA", "CCCC"),
+			cigar:    "*",
+			extended: "*",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.result.CIGAR(); got != tt.cigar {
+			t.Errorf("%s: CIGAR() = %q, want %q", tt.name, got, tt.cigar)
+		}
+		if got := tt.result.ExtendedCIGAR(); got != tt.extended {
+			t.Errorf("%s: ExtendedCIGAR() = %q, want %q", tt.name, got, tt.extended)
+		}
+	}
+
+	// Soft clips come from the bases the aligner left out
+	result := SmithWaterman("TTGATTACACC", "GATTACA")
+	if got := result.CIGAR(); got != "2S7M2S" {
+		t.Errorf("Expected soft clips around the local alignment, got %q", got)
+	}
+	if got := SmithWaterman("GATTACACC", "GATTACA").CIGAR(); got != "7M2S" {
+		t.Errorf("Expected a trailing soft clip only, got %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package align
+
+// Block is an exact match shared by two sequences: a[AStart:AStart+Length] equals
+// b[BStart:BStart+Length].
+type Block struct {
+	AStart int `json:"aStart"`
+	BStart int `json:"bStart"`
+	Length int `json:"length"`
+}
+
+// ColinearBlocks finds the longest chain of exact-match anchors that appear in the
+// same order in both sequences, as used for synteny and dot-plot views.
+//
+// Anchors are found by seeding: every minLength-mer of a is looked up in an index of
+// b's minLength-mers, and each hit is extended along its diagonal into a maximal
+// exact match. The anchors are then chained so that each one starts after the
+// previous one ends in both sequences, choosing the chain that covers the most bases.
+// Anchors that cross the chain (rearrangements) are left out.
+//
+// Parameters:
+//   - a (string): The first sequence.
+//   - b (string): The second sequence.
+//   - minLength (int): The minimum length of an anchor.
+//
+// Returns:
+//   - ([]Block): The chained anchors, ordered by position. Nil if minLength is not positive.
+func ColinearBlocks(a, b string, minLength int) []Block {
+	if minLength <= 0 {
+		return nil
+	}
+
+	return chainBlocks(findAnchors(a, b, minLength))
+}
+
+// findAnchors returns every maximal exact match of at least minLength bases,
+// ordered by position in a and then in b.
+func findAnchors(a, b string, minLength int) []Block {
+	if len(a) < minLength || len(b) < minLength {
+		return nil
+	}
+
+	// Index the seeds of b
+	seeds := make(map[string][]int)
+	for j := 0; j+minLength <= len(b); j++ {
+		seed := b[j : j+minLength]
+		seeds[seed] = append(seeds[seed], j)
+	}
+
+	// diagonalEnd records where the last anchor on each diagonal (j-i) ends in a,
+	// so hits inside an anchor that was already extended are skipped
+	diagonalEnd := make(map[int]int)
+
+	var anchors []Block
+	for i := 0; i+minLength <= len(a); i++ {
+		for _, j := range seeds[a[i:i+minLength]] {
+			if end, ok := diagonalEnd[j-i]; ok && i < end {
+				continue
+			}
+
+			// The hit is left-maximal, since a match one base earlier would have
+			// produced its own hit; extend it to the right
+			length := minLength
+			for i+length < len(a) && j+length < len(b) && a[i+length] == b[j+length] {
+				length++
+			}
+
+			anchors = append(anchors, Block{AStart: i, BStart: j, Length: length})
+			diagonalEnd[j-i] = i + length
+		}
+	}
+
+	return anchors
+}
+
+// chainBlocks selects the colinear, non-overlapping subset of anchors that covers the
+// most bases. Anchors must be ordered by AStart.
+func chainBlocks(anchors []Block) []Block {
+	if len(anchors) == 0 {
+		return nil
+	}
+
+	// best[k] is the largest coverage of a chain ending with anchors[k]
+	best := make([]int, len(anchors))
+	previous := make([]int, len(anchors))
+	last := 0
+
+	for k, anchor := range anchors {
+		best[k], previous[k] = anchor.Length, -1
+		for p := 0; p < k; p++ {
+			before := anchors[p]
+			if before.AStart+before.Length <= anchor.AStart && before.BStart+before.Length <= anchor.BStart &&
+				best[p]+anchor.Length > best[k] {
+				best[k], previous[k] = best[p]+anchor.Length, p
+			}
+		}
+
+		if best[k] > best[last] {
+			last = k
+		}
+	}
+
+	// Walk the chain back from its last anchor
+	var chain []Block
+	for k := last; k >= 0; k = previous[k] {
+		chain = append(chain, anchors[k])
+	}
+	for l, r := 0, len(chain)-1; l < r; l, r = l+1, r-1 {
+		chain[l], chain[r] = chain[r], chain[l]
+	}
+
+	return chain
+}
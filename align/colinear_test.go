@@ -0,0 +1,47 @@
+package align
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestColinearBlocks checks that two shared blocks are found in order and a crossing block is dropped
+func TestColinearBlocks(t *testing.T) {
+	block1 := "GATTACAGATCAGATAG"
+	block2 := "TGCATGCGTACGTTGACGGT"
+
+	// Fillers that share nothing with each other or with the blocks
+	a := strings.Repeat("A", 10) + block1 + strings.Repeat("A", 25) + block2 + strings.Repeat("A", 5)
+	b := strings.Repeat("C", 4) + block1 + strings.Repeat("C", 8) + block2 + strings.Repeat("C", 12)
+
+	expected := []Block{
+		{AStart: 10, BStart: 4, Length: len(block1)},
+		{AStart: 10 + len(block1) + 25, BStart: 4 + len(block1) + 8, Length: len(block2)},
+	}
+
+	blocks := ColinearBlocks(a, b, 10)
+	if !reflect.DeepEqual(blocks, expected) {
+		t.Fatalf("Blocks were %+v, expected %+v", blocks, expected)
+	}
+	for _, block := range blocks {
+		if a[block.AStart:block.AStart+block.Length] != b[block.BStart:block.BStart+block.Length] {
+			t.Errorf("Block %+v is not an exact match", block)
+		}
+	}
+
+	// With the blocks in opposite orders, only the longer one can be kept
+	swapped := strings.Repeat("C", 4) + block2 + strings.Repeat("C", 8) + block1
+	blocks = ColinearBlocks(a, swapped, 10)
+	if len(blocks) != 1 || blocks[0].Length != len(block2) {
+		t.Errorf("Expected only the longer crossing block to be kept, got %+v", blocks)
+	}
+
+	// Anchors shorter than the minimum are ignored
+	if blocks := ColinearBlocks(a, b, 25); len(blocks) != 0 {
+		t.Errorf("Expected no blocks of at least 25 bases, got %+v", blocks)
+	}
+	if blocks := ColinearBlocks(a, b, 0); blocks != nil {
+		t.Errorf("Expected nil for a non-positive minimum length, got %+v", blocks)
+	}
+}
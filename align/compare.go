@@ -0,0 +1,61 @@
+package align
+
+// AlignmentsEquivalent checks if two alignments are functionally equivalent.
+// Different but equally valid alignments of the same sequences are common: the
+// same gaps can often be placed at several positions without changing the score.
+//
+// Two alignments are considered equivalent when they are identical, or when
+// both have the same number of columns and the same score as recomputed by
+// AlignmentScore. An alignment whose query and reference rows differ in length
+// is malformed and is never equivalent to anything.
+//
+// Parameters:
+//   - query1, ref1 (string): The aligned query and reference of the first alignment.
+//   - query2, ref2 (string): The aligned query and reference of the second alignment.
+//
+// Returns:
+//   - (bool): true if the two alignments are equivalent.
+func AlignmentsEquivalent(query1, ref1, query2, ref2 string) bool {
+	// Malformed alignments cannot be rescored
+	if len(query1) != len(ref1) || len(query2) != len(ref2) {
+		return false
+	}
+
+	// First check exact match
+	if query1 == query2 && ref1 == ref2 {
+		return true
+	}
+
+	// If lengths differ, they're not equivalent
+	if len(query1) != len(query2) {
+		return false
+	}
+
+	// Check if the alignments represent the same matching bases
+	// This allows for differences in how gaps are distributed
+	return AlignmentScore(query1, ref1) == AlignmentScore(query2, ref2)
+}
+
+// AlignmentScore recomputes the score of an alignment column by column using the
+// package scoring constants: MatchScore for identical bases, MismatchScore for
+// different bases, and GapPenalty for any column containing a gap.
+//
+// Parameters:
+//   - alignedQuery (string): The aligned query sequence, with '-' for gaps.
+//   - alignedRef (string): The aligned reference sequence, with '-' for gaps.
+//
+// Returns:
+//   - (int): The score of the alignment. Only the columns present in both rows are scored.
+func AlignmentScore(alignedQuery, alignedRef string) int {
+	score := 0
+	for i := 0; i < len(alignedQuery) && i < len(alignedRef); i++ {
+		if alignedQuery[i] == '-' || alignedRef[i] == '-' {
+			score += GapPenalty
+		} else if alignedQuery[i] == alignedRef[i] {
+			score += MatchScore
+		} else {
+			score += MismatchScore
+		}
+	}
+	return score
+}
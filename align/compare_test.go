@@ -0,0 +1,50 @@
+package align
+
+import "testing"
+
+// TestAlignmentScore checks rescoring of hand-built alignments
+func TestAlignmentScore(t *testing.T) {
+	testCases := []struct {
+		query, reference string
+		expected         int
+	}{
+		{"GATTACA", "GATTACA", 7 * MatchScore},
+		{"GATTACA", "GATTTCA", 6*MatchScore + MismatchScore},
+		{"GATT-ACA", "GATTTACA", 7*MatchScore + GapPenalty},
+		{"", "", 0},
+	}
+
+	for _, tc := range testCases {
+		if score := AlignmentScore(tc.query, tc.reference); score != tc.expected {
+			t.Errorf("AlignmentScore(%s, %s) = %d, expected %d", tc.query, tc.reference, score, tc.expected)
+		}
+	}
+}
+
+// TestAlignmentsEquivalent covers identical, equivalent, and non-equivalent alignments
+func TestAlignmentsEquivalent(t *testing.T) {
+	// Identical alignments
+	if !AlignmentsEquivalent("GATTACA", "GATTACA", "GATTACA", "GATTACA") {
+		t.Error("Identical alignments should be equivalent")
+	}
+
+	// Same gap, different placement within a homopolymer run
+	if !AlignmentsEquivalent("GATT-ACA", "GATTTACA", "GA-TTACA", "GATTTACA") {
+		t.Error("Different placements of the same gap should be equivalent")
+	}
+
+	// Same length but a different score
+	if AlignmentsEquivalent("GATTACA", "GATTACA", "GATTACA", "GATCACA") {
+		t.Error("Alignments with different scores should not be equivalent")
+	}
+
+	// Different lengths
+	if AlignmentsEquivalent("GATTACA", "GATTACA", "GATTAC", "GATTAC") {
+		t.Error("Alignments with different lengths should not be equivalent")
+	}
+
+	// Malformed alignment with rows of different lengths
+	if AlignmentsEquivalent("GATTACA", "GATT", "GATTACA", "GATT") {
+		t.Error("Malformed alignments should never be equivalent")
+	}
+}
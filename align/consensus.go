@@ -0,0 +1,236 @@
+package align
+
+import "sort"
+
+// PolishConsensus iteratively refines a consensus by realigning its members to it.
+//
+// Simple column voting assumes the members are already in the same coordinate
+// frame, so an indel in the draft consensus shifts every later column. Polishing
+// instead aligns each member to the current consensus and, for every consensus
+// position, collects what the members say: the same base, a different base, a
+// deletion, or extra bases inserted before it. A change is applied only when a
+// strict majority of the members covering that position agree on it.
+//
+// Parameters:
+//   - consensus (string): The draft consensus sequence.
+//   - members ([]string): The sequences the consensus was built from.
+//   - rounds (int): The maximum number of realign-and-update rounds. Polishing stops
+//     early once a round makes no changes.
+//
+// Returns:
+//   - (string): The polished consensus.
+func PolishConsensus(consensus string, members []string, rounds int) string {
+	for round := 0; round < rounds && len(members) > 0; round++ {
+		polished := polishRound(consensus, members)
+		if polished == consensus {
+			break
+		}
+		consensus = polished
+	}
+
+	return consensus
+}
+
+// polishRound realigns every member to the consensus once and applies the majority corrections.
+func polishRound(consensus string, members []string) string {
+	n := len(consensus)
+
+	// votes[pos] counts the base (or '-' for a deletion) each member aligns to consensus[pos]
+	votes := make([]map[byte]int, n)
+	// insertions[pos] counts bases inserted immediately before consensus[pos]
+	insertions := make([]map[string]int, n+1)
+	coverage := make([]int, n+1)
+
+	for _, member := range members {
+		result := SmithWaterman(member, consensus)
+		pos := result.RefStart
+		inserted := ""
+
+		for i := 0; i < len(result.AlignedQuery); i++ {
+			q, r := result.AlignedQuery[i], result.AlignedRef[i]
+			if r == '-' {
+				// Extra base in the member before consensus[pos]
+				inserted += string(q)
+				continue
+			}
+
+			if inserted != "" {
+				if insertions[pos] == nil {
+					insertions[pos] = make(map[string]int)
+				}
+				insertions[pos][inserted]++
+				inserted = ""
+			}
+
+			if votes[pos] == nil {
+				votes[pos] = make(map[byte]int)
+			}
+			votes[pos][q]++
+			coverage[pos]++
+			pos++
+		}
+	}
+
+	polished := make([]byte, 0, n)
+	for pos := 0; pos <= n; pos++ {
+		// Insert bases that a majority of the members covering this position carry
+		if ins, count := majorityKey(insertions[pos]); count > 0 && count*2 > coverage[pos] {
+			polished = append(polished, ins...)
+		}
+		if pos == n {
+			break
+		}
+
+		// Replace or delete the base if a majority disagrees with it
+		base := consensus[pos]
+		if winner, count := majorityByte(votes[pos]); count*2 > coverage[pos] {
+			base = winner
+		}
+		if base != '-' {
+			polished = append(polished, base)
+		}
+	}
+
+	return string(polished)
+}
+
+// WeightedBatchConsensus builds a consensus of several references in the query's
+// coordinate frame, weighting each reference by its abundance (for example a
+// haplotype frequency).
+//
+// The query is aligned against every reference concurrently. For each query
+// position, every reference whose alignment covers it votes with its weight for the
+// base (or deletion) it aligns there, and the heaviest vote wins. Bases a reference
+// inserts before a query position are added when they carry more than half the
+// weight covering that position. Positions no reference covers keep the query base.
+//
+// Parameters:
+//   - query (string): The sequence whose frame the consensus is built in.
+//   - references ([]string): The references to combine.
+//   - weights ([]float64): The abundance of each reference. References without a
+//     weight, or with a negative one, do not vote.
+//   - numWorkers (int): Maximum number of concurrent alignments (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - (string): The weighted consensus sequence.
+func WeightedBatchConsensus(query string, references []string, weights []float64, numWorkers int) string {
+	n := len(query)
+	results := ConcurrentSmithWatermanBatchReuse(query, references, numWorkers)
+
+	// votes[pos] sums the weight behind each base (or '-') aligned to query[pos]
+	votes := make([]map[byte]float64, n)
+	// insertions[pos] sums the weight behind bases inserted immediately before query[pos]
+	insertions := make([]map[string]float64, n+1)
+	coverage := make([]float64, n+1)
+
+	for i, result := range results {
+		if i >= len(weights) || weights[i] <= 0 {
+			continue
+		}
+		weight := weights[i]
+		pos := result.QueryStart
+		inserted := ""
+
+		for col := 0; col < len(result.AlignedQuery); col++ {
+			q, r := result.AlignedQuery[col], result.AlignedRef[col]
+			if q == '-' {
+				// Extra base in the reference before query[pos]
+				inserted += string(r)
+				continue
+			}
+
+			if inserted != "" {
+				if insertions[pos] == nil {
+					insertions[pos] = make(map[string]float64)
+				}
+				insertions[pos][inserted] += weight
+				inserted = ""
+			}
+
+			if votes[pos] == nil {
+				votes[pos] = make(map[byte]float64)
+			}
+			votes[pos][r] += weight
+			coverage[pos] += weight
+			pos++
+		}
+	}
+
+	consensus := make([]byte, 0, n)
+	for pos := 0; pos <= n; pos++ {
+		if ins, weight := heaviestKey(insertions[pos]); weight > 0 && weight*2 > coverage[pos] {
+			consensus = append(consensus, ins...)
+		}
+		if pos == n {
+			break
+		}
+
+		base := query[pos]
+		if winner, weight := heaviestByte(votes[pos]); weight > 0 {
+			base = winner
+		}
+		if base != '-' {
+			consensus = append(consensus, base)
+		}
+	}
+
+	return string(consensus)
+}
+
+// heaviestKey returns the string with the largest weight, breaking ties alphabetically.
+func heaviestKey(weights map[string]float64) (string, float64) {
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best, bestWeight := "", 0.0
+	for _, key := range keys {
+		if weights[key] > bestWeight {
+			best, bestWeight = key, weights[key]
+		}
+	}
+	return best, bestWeight
+}
+
+// heaviestByte returns the byte with the largest weight, breaking ties by byte value.
+func heaviestByte(weights map[byte]float64) (byte, float64) {
+	var best byte
+	bestWeight := 0.0
+	for b := 0; b < 256; b++ {
+		if weight := weights[byte(b)]; weight > bestWeight {
+			best, bestWeight = byte(b), weight
+		}
+	}
+	return best, bestWeight
+}
+
+// majorityKey returns the most frequent string in counts, breaking ties alphabetically.
+func majorityKey(counts map[string]int) (string, int) {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best, bestCount := "", 0
+	for _, key := range keys {
+		if counts[key] > bestCount {
+			best, bestCount = key, counts[key]
+		}
+	}
+	return best, bestCount
+}
+
+// majorityByte returns the most frequent byte in counts, breaking ties by byte value.
+func majorityByte(counts map[byte]int) (byte, int) {
+	var best byte
+	bestCount := 0
+	for b := 0; b < 256; b++ {
+		if count := counts[byte(b)]; count > bestCount {
+			best, bestCount = byte(b), count
+		}
+	}
+	return best, bestCount
+}
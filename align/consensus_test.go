@@ -0,0 +1,61 @@
+package align
+
+import "testing"
+
+// TestPolishConsensus checks that polishing removes and restores bases around an indel error
+func TestPolishConsensus(t *testing.T) {
+	truth := "GATTACAGATCAGATAGATACAGATAGACCAGGTACCATG"
+	members := []string{truth, truth, truth, truth}
+
+	// A spurious base inserted into the draft consensus
+	withInsertion := truth[:20] + "T" + truth[20:]
+	if polished := PolishConsensus(withInsertion, members, 3); polished != truth {
+		t.Errorf("Expected the spurious insertion to be removed:\nGot:      %s\nExpected: %s", polished, truth)
+	}
+
+	// A base missing from the draft consensus
+	withDeletion := truth[:15] + truth[16:]
+	if polished := PolishConsensus(withDeletion, members, 3); polished != truth {
+		t.Errorf("Expected the missing base to be restored:\nGot:      %s\nExpected: %s", polished, truth)
+	}
+
+	// A single dissenting member cannot change the consensus
+	dissenter := truth[:10] + "C" + truth[11:]
+	mixed := []string{truth, truth, dissenter}
+	if polished := PolishConsensus(truth, mixed, 3); polished != truth {
+		t.Errorf("A minority member should not change the consensus, got %s", polished)
+	}
+
+	// No rounds leaves the consensus unchanged
+	if polished := PolishConsensus(withInsertion, members, 0); polished != withInsertion {
+		t.Errorf("Expected no change with zero rounds, got %s", polished)
+	}
+}
+
+// TestWeightedBatchConsensus checks that a high-weight reference outvotes more numerous low-weight ones
+func TestWeightedBatchConsensus(t *testing.T) {
+	query := "ATGGCCTACGATCGTAGCTAGGCTACGATC"
+
+	// The abundant haplotype has a substitution and an insertion; two rare ones
+	// agree with each other on a different base at the substituted position
+	abundant := "ATGGCCTACGAGCGTAGCTATTGGCTACGATC"
+	rare := "ATGGCCTACGACCGTAGCTAGGCTACGATC"
+	references := []string{rare, abundant, rare}
+
+	got := WeightedBatchConsensus(query, references, []float64{0.1, 0.8, 0.1}, 2)
+	if got != abundant {
+		t.Errorf("Expected the abundant haplotype to dominate:\ngot  %s\nwant %s", got, abundant)
+	}
+
+	// With equal weights the two rare references win the substitution, and the
+	// insertion no longer carries more than half the weight
+	got = WeightedBatchConsensus(query, references, []float64{1, 1, 1}, 2)
+	if got != rare {
+		t.Errorf("Expected the rare haplotypes to win with equal weights:\ngot  %s\nwant %s", got, rare)
+	}
+
+	// Without any votes the consensus is the query itself
+	if got := WeightedBatchConsensus(query, references, nil, 2); got != query {
+		t.Errorf("Expected the query without weights, got %s", got)
+	}
+}
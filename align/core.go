@@ -0,0 +1,45 @@
+package align
+
+import "runtime"
+
+// CommonCore finds the region of the query that aligns well to every reference.
+//
+// The query is aligned against each reference, and the query span of each local
+// alignment (QueryStart to QueryEnd) is intersected. Only alignments with at least
+// minIdentity identity count as covering their span; if any reference fails that
+// bar, no region is shared by all of them.
+//
+// Parameters:
+//   - query (string): The sequence in whose coordinates the core is reported.
+//   - references ([]string): The sequences the core must align to.
+//   - minIdentity (float64): The minimum identity (0 to 1) for an alignment to count.
+//
+// Returns:
+//   - (int, int): The 0-based start and exclusive end of the core in the query.
+//     When there is no common core, start == end.
+func CommonCore(query string, references []string, minIdentity float64) (start, end int) {
+	if len(references) == 0 {
+		return 0, 0
+	}
+
+	results := ConcurrentSmithWatermanBatchReuse(query, references, runtime.GOMAXPROCS(0))
+
+	start, end = 0, len(query)
+	for _, result := range results {
+		if result.MaxScore == 0 || result.Identity() < minIdentity {
+			return 0, 0
+		}
+
+		if result.QueryStart > start {
+			start = result.QueryStart
+		}
+		if result.QueryEnd < end {
+			end = result.QueryEnd
+		}
+	}
+
+	if end <= start {
+		return 0, 0
+	}
+	return start, end
+}
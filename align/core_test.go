@@ -0,0 +1,39 @@
+package align
+
+import "testing"
+
+// TestCommonCore checks that only the central region shared by every reference is reported
+func TestCommonCore(t *testing.T) {
+	left := "CCCCCCCCCCCCCCCCCCCC"
+	core := "GATTACAGATCAGATAGATACAGATAGACC"
+	right := "TTTTTTTTTTTTTTTTTTTT"
+	query := left + core + right
+
+	// Each reference shares the core plus some of one flank
+	references := []string{
+		left[10:] + core,
+		core + right[:10],
+		"GG" + core + "GG",
+	}
+
+	start, end := CommonCore(query, references, 0.9)
+	if start != len(left) || end != len(left)+len(core) {
+		t.Errorf("Core was [%d,%d), expected [%d,%d)", start, end, len(left), len(left)+len(core))
+	}
+
+	// A reference unrelated to the query leaves no common core
+	unrelated := append(references, "AAAAAAAAAAAAAAAAAAAA")
+	if start, end := CommonCore(query, unrelated, 0.9); start != end {
+		t.Errorf("Expected no core with an unrelated reference, got [%d,%d)", start, end)
+	}
+
+	// References that align to disjoint parts of the query share nothing
+	disjoint := []string{left, right}
+	if start, end := CommonCore(query, disjoint, 0.9); start != end {
+		t.Errorf("Expected no core for disjoint references, got [%d,%d)", start, end)
+	}
+
+	if start, end := CommonCore(query, nil, 0.9); start != end {
+		t.Errorf("Expected no core without references, got [%d,%d)", start, end)
+	}
+}
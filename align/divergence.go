@@ -0,0 +1,62 @@
+package align
+
+import "runtime"
+
+// MostDivergent finds the sequence that aligns worst to the rest of a set.
+//
+// Every pair of sequences is aligned once, and each sequence's scores against
+// all the others are averaged. The sequence with the lowest average is the
+// likeliest contaminant or mislabeled member of the family. Ties go to the
+// lower index.
+//
+// Parameters:
+//   - sequences ([]string): The sequences to compare.
+//
+// Returns:
+//   - index (int): The index of the most divergent sequence, or -1 if there are
+//     fewer than two sequences.
+//   - avgScore (float64): Its average alignment score against the other sequences.
+func MostDivergent(sequences []string) (index int, avgScore float64) {
+	if len(sequences) < 2 {
+		return -1, 0
+	}
+
+	scores := pairwiseScores(sequences)
+
+	index = -1
+	for i := range sequences {
+		total := 0
+		for j := range sequences {
+			if i != j {
+				total += scores[i][j]
+			}
+		}
+
+		avg := float64(total) / float64(len(sequences)-1)
+		if index < 0 || avg < avgScore {
+			index, avgScore = i, avg
+		}
+	}
+
+	return index, avgScore
+}
+
+// pairwiseScores aligns every pair of sequences once and returns the symmetric
+// matrix of alignment scores. The diagonal is left at zero.
+func pairwiseScores(sequences []string) [][]int {
+	scores := make([][]int, len(sequences))
+	for i := range scores {
+		scores[i] = make([]int, len(sequences))
+	}
+
+	for i := 0; i < len(sequences)-1; i++ {
+		results := ConcurrentSmithWatermanBatchReuse(sequences[i], sequences[i+1:], runtime.GOMAXPROCS(0))
+		for k, result := range results {
+			j := i + 1 + k
+			scores[i][j] = result.MaxScore
+			scores[j][i] = result.MaxScore
+		}
+	}
+
+	return scores
+}
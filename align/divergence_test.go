@@ -0,0 +1,44 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMostDivergent checks that an unrelated sequence in a family is flagged as the outlier
+func TestMostDivergent(t *testing.T) {
+	family := "GATTACAGATCAGATAGATACAGATAGACCAGGTACCATG"
+	sequences := []string{
+		family,
+		family[:20] + "T" + family[21:],
+		strings.Repeat("C", 20) + strings.Repeat("G", 20), // Unrelated
+		family[5:],
+		family[:30] + "GGTTCCAAGG",
+	}
+
+	index, avgScore := MostDivergent(sequences)
+	if index != 2 {
+		t.Errorf("Expected sequence 2 to be the most divergent, got %d", index)
+	}
+
+	// The outlier's average must be below every other sequence's
+	scores := pairwiseScores(sequences)
+	for i := range sequences {
+		if i == index {
+			continue
+		}
+		total := 0
+		for j := range sequences {
+			if j != i {
+				total += scores[i][j]
+			}
+		}
+		if avg := float64(total) / float64(len(sequences)-1); avg <= avgScore {
+			t.Errorf("Sequence %d averages %f, not above the outlier's %f", i, avg, avgScore)
+		}
+	}
+
+	if index, _ := MostDivergent([]string{family}); index != -1 {
+		t.Errorf("Expected -1 for a single sequence, got %d", index)
+	}
+}
@@ -0,0 +1,76 @@
+package align
+
+import (
+	"sort"
+
+	"pgfp/data"
+)
+
+// DotPlotPoint is one dot of a dot plot: a length-k window of the query that
+// matches a length-k window of the reference.
+type DotPlotPoint struct {
+	QueryPos int  `json:"queryPos"`          // Start of the query window (0-based)
+	RefPos   int  `json:"refPos"`            // Start of the reference window (0-based)
+	Reverse  bool `json:"reverse,omitempty"` // The query window matches the reverse complement of the reference window
+}
+
+// DotPlot finds every pair of matching length-k windows between two sequences, for
+// drawing a dot plot. Runs of dots along a diagonal show shared segments, parallel
+// diagonals show repeats, and reverse dots running against the diagonal show
+// inversions, none of which a single best alignment reveals.
+//
+// The dots are returned as a sparse list rather than a len(query) x len(reference)
+// grid, so memory grows with the number of matches instead of the product of the
+// lengths. Highly repetitive sequences can still produce many dots; a larger k
+// thins them out.
+//
+// Parameters:
+//   - query (string): The query sequence, plotted along one axis.
+//   - reference (string): The reference sequence, plotted along the other.
+//   - k (int): The window length. Values below 1 return no dots.
+//
+// Returns:
+//   - ([]DotPlotPoint): The matching windows in both orientations, sorted by query
+//     position, then reference position, with forward dots before reverse ones.
+//     A reverse dot at (i, j) means query[i:i+k] equals the reverse complement of
+//     reference[j:j+k].
+func DotPlot(query, reference string, k int) []DotPlotPoint {
+	if k < 1 || k > len(query) || k > len(reference) {
+		return nil
+	}
+
+	// Index the start of every reference window, forward and reverse complemented
+	forward := make(map[string][]int)
+	reverse := make(map[string][]int)
+	for j := 0; j+k <= len(reference); j++ {
+		window := reference[j : j+k]
+		forward[window] = append(forward[window], j)
+		rc := data.ReverseComplement(window)
+		reverse[rc] = append(reverse[rc], j)
+	}
+
+	var points []DotPlotPoint
+	for i := 0; i+k <= len(query); i++ {
+		window := query[i : i+k]
+		for _, j := range forward[window] {
+			points = append(points, DotPlotPoint{QueryPos: i, RefPos: j})
+		}
+		for _, j := range reverse[window] {
+			points = append(points, DotPlotPoint{QueryPos: i, RefPos: j, Reverse: true})
+		}
+	}
+
+	// Windows are visited in query order and indexed in reference order, so only the
+	// interleaving of forward and reverse dots within a query position needs sorting
+	sort.SliceStable(points, func(a, b int) bool {
+		if points[a].QueryPos != points[b].QueryPos {
+			return points[a].QueryPos < points[b].QueryPos
+		}
+		if points[a].RefPos != points[b].RefPos {
+			return points[a].RefPos < points[b].RefPos
+		}
+		return !points[a].Reverse && points[b].Reverse
+	})
+
+	return points
+}
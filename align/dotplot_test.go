@@ -0,0 +1,45 @@
+package align
+
+import (
+	"reflect"
+	"testing"
+
+	"pgfp/data"
+)
+
+// TestDotPlot checks forward dots along the diagonal, repeat dots off it, and
+// reverse dots from an inversion
+func TestDotPlot(t *testing.T) {
+	want := []DotPlotPoint{{0, 0, false}, {1, 1, false}, {2, 2, false}, {3, 3, false}}
+	if got := DotPlot("GATTACA", "GATTACA", 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("Identical sequences: got %v, want %v", got, want)
+	}
+
+	// A tandem repeat in the reference puts the query window on two diagonals
+	got := DotPlot("GATT", "GATTGATT", 4)
+	if !reflect.DeepEqual(got, []DotPlotPoint{{0, 0, false}, {0, 4, false}}) {
+		t.Errorf("Repeat: got %v", got)
+	}
+
+	// An inverted reference only produces reverse dots
+	query := "GATTACCAGG"
+	reference := data.ReverseComplement(query)
+	points := DotPlot(query, reference, 5)
+	if len(points) != len(query)-4 {
+		t.Errorf("Expected %d reverse dots, got %v", len(query)-4, points)
+	}
+	for _, point := range points {
+		if !point.Reverse {
+			t.Errorf("Expected only reverse dots against the reverse complement, got %v", point)
+		}
+		if query[point.QueryPos:point.QueryPos+5] != data.ReverseComplement(reference[point.RefPos:point.RefPos+5]) {
+			t.Errorf("Dot %v does not mark a reverse-complement match", point)
+		}
+	}
+
+	for _, k := range []int{0, -1, 20} {
+		if got := DotPlot("GATTACA", "GATTACA", k); got != nil {
+			t.Errorf("k=%d: expected no dots, got %v", k, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package align
+
+// AlignedBlock is a gap-free stretch of an alignment, such as one exon of a spliced
+// transcript aligned to genomic DNA. Coordinates refer to the original sequences
+// (0-based, end exclusive).
+type AlignedBlock struct {
+	QueryStart int `json:"queryStart"`
+	QueryEnd   int `json:"queryEnd"`
+	RefStart   int `json:"refStart"`
+	RefEnd     int `json:"refEnd"`
+}
+
+// AlignmentBlocks splits an alignment into blocks separated by long runs of
+// reference bases that are missing from the query, such as the introns skipped
+// when a transcript is aligned to its gene. Shorter gaps, and all insertions in
+// the query, stay inside their block.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to split.
+//   - minGapForSplit (int): A run of query gaps splits the alignment when it is
+//     longer than this many columns.
+//
+// Returns:
+//   - ([]AlignedBlock): The blocks in alignment order. Empty for an empty alignment.
+func AlignmentBlocks(result AlignmentResult, minGapForSplit int) []AlignedBlock {
+	if len(result.AlignedQuery) == 0 {
+		return nil
+	}
+
+	var blocks []AlignedBlock
+	queryPos, refPos := result.QueryStart, result.RefStart
+	current := AlignedBlock{QueryStart: queryPos, RefStart: refPos}
+
+	for i := 0; i < len(result.AlignedQuery); {
+		// Measure a run of gaps in the query, which skips reference bases
+		if result.AlignedQuery[i] == '-' {
+			run := 0
+			for i+run < len(result.AlignedQuery) && result.AlignedQuery[i+run] == '-' {
+				run++
+			}
+
+			if run > minGapForSplit {
+				current.QueryEnd, current.RefEnd = queryPos, refPos
+				blocks = append(blocks, current)
+				current = AlignedBlock{QueryStart: queryPos, RefStart: refPos + run}
+			}
+
+			refPos += run
+			i += run
+			continue
+		}
+
+		queryPos++
+		if result.AlignedRef[i] != '-' {
+			refPos++
+		}
+		i++
+	}
+
+	current.QueryEnd, current.RefEnd = queryPos, refPos
+	blocks = append(blocks, current)
+
+	return blocks
+}
@@ -0,0 +1,55 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAlignmentBlocks checks that a long intronic gap splits the alignment into two exons
+func TestAlignmentBlocks(t *testing.T) {
+	exon1 := "ATGGCCTACGATCGTAGCTAGGCTACGATC"
+	intron := "GTAAGTTTTTCCCAG"
+	exon2 := "TTGACCGATGGTACCAGTTCAGGATCCTGA"
+
+	// A spliced alignment as an aligner with affine gaps would report it, starting
+	// two bases into the reference
+	result := AlignmentResult{
+		AlignedQuery: exon1 + strings.Repeat("-", len(intron)) + exon2,
+		AlignedRef:   exon1 + intron + exon2,
+		QueryStart:   0,
+		QueryEnd:     60,
+		RefStart:     2,
+		RefEnd:       77,
+	}
+
+	blocks := AlignmentBlocks(result, 10)
+	want := []AlignedBlock{
+		{QueryStart: 0, QueryEnd: 30, RefStart: 2, RefEnd: 32},
+		{QueryStart: 30, QueryEnd: 60, RefStart: 47, RefEnd: 77},
+	}
+	if len(blocks) != len(want) {
+		t.Fatalf("Expected %d blocks, got %d: %+v", len(want), len(blocks), blocks)
+	}
+	for i := range want {
+		if blocks[i] != want[i] {
+			t.Errorf("Block %d = %+v, want %+v", i, blocks[i], want[i])
+		}
+	}
+
+	// A gap no longer than the threshold does not split the alignment
+	blocks = AlignmentBlocks(result, len(intron))
+	if len(blocks) != 1 || blocks[0] != (AlignedBlock{QueryStart: 0, QueryEnd: 60, RefStart: 2, RefEnd: 77}) {
+		t.Errorf("Expected a single block spanning the alignment, got %+v", blocks)
+	}
+
+	// Short gaps on either side stay inside the block
+	result = AlignmentResult{AlignedQuery: "ACG-TACGT", AlignedRef: "ACGGTA-GT", QueryStart: 5, RefStart: 9}
+	blocks = AlignmentBlocks(result, 2)
+	if len(blocks) != 1 || blocks[0] != (AlignedBlock{QueryStart: 5, QueryEnd: 13, RefStart: 9, RefEnd: 17}) {
+		t.Errorf("Expected short gaps not to split the alignment, got %+v", blocks)
+	}
+
+	if blocks := AlignmentBlocks(AlignmentResult{}, 10); blocks != nil {
+		t.Errorf("Expected no blocks for an empty alignment, got %+v", blocks)
+	}
+}
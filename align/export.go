@@ -0,0 +1,133 @@
+package align
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ExportSchemaVersion is the version of the AlignmentExport JSON format written by
+// this package. It is bumped whenever a field changes meaning or is removed, so
+// readers can reject files they would misinterpret.
+const ExportSchemaVersion = 1
+
+// AlignmentExport is the stable JSON representation of an alignment, for saving
+// results and rendering them again later without re-aligning. Besides the aligned
+// rows it carries the summaries most consumers would otherwise recompute.
+//
+// Example:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "alignedQuery": "GATTACA",
+//	  "alignedRef": "GATCACA",
+//	  "score": 11,
+//	  "queryStart": 0, "queryEnd": 7, "refStart": 0, "refEnd": 7,
+//	  "cigar": "7M",
+//	  "mutations": [{"type": "snp", "position": 3, "length": 1, "original": "C", "mutated": "T"}],
+//	  "stats": {"matches": 6, "mismatches": 1, ...}
+//	}
+type AlignmentExport struct {
+	SchemaVersion int         `json:"schemaVersion"`           // Always ExportSchemaVersion when written by this package
+	AlignedQuery  string      `json:"alignedQuery"`            // The aligned query, with gaps
+	AlignedRef    string      `json:"alignedRef"`              // The aligned reference, with gaps
+	Score         int         `json:"score"`                   // The alignment score
+	QueryStart    int         `json:"queryStart"`              // Start of the aligned region in the query (0-based, inclusive)
+	QueryEnd      int         `json:"queryEnd"`                // End of the aligned region in the query (exclusive)
+	RefStart      int         `json:"refStart"`                // Start of the aligned region in the reference (0-based, inclusive)
+	RefEnd        int         `json:"refEnd"`                  // End of the aligned region in the reference (exclusive)
+	ClippedPrefix string      `json:"clippedPrefix,omitempty"` // Query bases before queryStart, left out of a local alignment
+	ClippedSuffix string      `json:"clippedSuffix,omitempty"` // Query bases from queryEnd on, left out of a local alignment
+	CIGAR         string      `json:"cigar"`                   // The SAM CIGAR string, with soft clips
+	Mutations     []Mutation  `json:"mutations"`               // The SNPs and indels in the alignment
+	Stats         Stats       `json:"stats"`                   // Match, mismatch, and gap counts
+	Provenance    *Provenance `json:"provenance,omitempty"`    // How the result was produced, if recorded
+}
+
+// NewAlignmentExport builds the exported form of an alignment result.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to export.
+//
+// Returns:
+//   - (AlignmentExport): The export, stamped with ExportSchemaVersion. Mutations is
+//     an empty list rather than nil, so it always serializes as an array.
+func NewAlignmentExport(result AlignmentResult) AlignmentExport {
+	mutations := DetectMutations(result.AlignedQuery, result.AlignedRef)
+	if mutations == nil {
+		mutations = []Mutation{}
+	}
+
+	return AlignmentExport{
+		SchemaVersion: ExportSchemaVersion,
+		AlignedQuery:  result.AlignedQuery,
+		AlignedRef:    result.AlignedRef,
+		Score:         result.MaxScore,
+		QueryStart:    result.QueryStart,
+		QueryEnd:      result.QueryEnd,
+		RefStart:      result.RefStart,
+		RefEnd:        result.RefEnd,
+		ClippedPrefix: result.ClippedPrefix,
+		ClippedSuffix: result.ClippedSuffix,
+		CIGAR:         result.CIGAR(),
+		Mutations:     mutations,
+		Stats:         AlignmentStats(result),
+		Provenance:    result.Provenance,
+	}
+}
+
+// Result converts the export back into an alignment result, so it can be passed to
+// the formatting and analysis functions. The clipped query bases are restored, so
+// CIGAR() and AlignmentStats give the same soft clips and coverage as before the
+// export. The alignment ends at its best cell, so MaxRow and MaxCol are QueryEnd and
+// RefEnd. The score matrix is not part of the export and is left nil.
+//
+// Returns:
+//   - (AlignmentResult): The alignment described by the export.
+func (e AlignmentExport) Result() AlignmentResult {
+	return AlignmentResult{
+		MaxScore:      e.Score,
+		MaxRow:        e.QueryEnd,
+		MaxCol:        e.RefEnd,
+		AlignedQuery:  e.AlignedQuery,
+		AlignedRef:    e.AlignedRef,
+		QueryStart:    e.QueryStart,
+		QueryEnd:      e.QueryEnd,
+		RefStart:      e.RefStart,
+		RefEnd:        e.RefEnd,
+		ClippedPrefix: e.ClippedPrefix,
+		ClippedSuffix: e.ClippedSuffix,
+		NoAlignment:   e.AlignedQuery == "",
+		Provenance:    e.Provenance,
+	}
+}
+
+// ParseAlignmentJSON reads an alignment written in the AlignmentExport format.
+//
+// Parameters:
+//   - data ([]byte): The JSON document.
+//
+// Returns:
+//   - (AlignmentExport): The parsed export.
+//   - (error): An error if the JSON is malformed, the schema version is missing or
+//     newer than ExportSchemaVersion, or the aligned rows are not a valid alignment.
+func ParseAlignmentJSON(data []byte) (AlignmentExport, error) {
+	var export AlignmentExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return AlignmentExport{}, err
+	}
+
+	switch {
+	case export.SchemaVersion == 0:
+		return AlignmentExport{}, errors.New("missing schemaVersion")
+	case export.SchemaVersion > ExportSchemaVersion:
+		return AlignmentExport{}, fmt.Errorf("unsupported schemaVersion %d (newest supported is %d)",
+			export.SchemaVersion, ExportSchemaVersion)
+	}
+
+	if err := export.Result().Validate(); err != nil {
+		return AlignmentExport{}, err
+	}
+
+	return export, nil
+}
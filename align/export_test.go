@@ -0,0 +1,78 @@
+package align
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAlignmentExportRoundTrip checks that an exported alignment parses back to the
+// same export and result, with its summaries filled in
+func TestAlignmentExportRoundTrip(t *testing.T) {
+	result := SmithWaterman("TTGATTACA", "GATCACA").WithProvenance(AlgorithmSmithWaterman, DefaultScoring)
+	export := NewAlignmentExport(result)
+
+	if export.SchemaVersion != ExportSchemaVersion || export.CIGAR != result.CIGAR() || export.Score != result.MaxScore {
+		t.Errorf("Unexpected export header: %+v", export)
+	}
+	if len(export.Mutations) != 1 || export.Mutations[0].Type != MutationSNP {
+		t.Errorf("Expected one SNP, got %+v", export.Mutations)
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, field := range []string{`"schemaVersion":1`, `"alignedQuery"`, `"cigar"`, `"mutations"`, `"stats"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("Expected %s in %s", field, data)
+		}
+	}
+
+	parsed, err := ParseAlignmentJSON(data)
+	if err != nil {
+		t.Fatalf("ParseAlignmentJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, export) {
+		t.Errorf("Round trip changed the export:\n got %+v\nwant %+v", parsed, export)
+	}
+
+	back := parsed.Result()
+	if back.AlignedQuery != result.AlignedQuery || back.RefStart != result.RefStart || back.MaxScore != result.MaxScore {
+		t.Errorf("Expected Result to restore the alignment, got %+v", back)
+	}
+	if back.MaxRow != result.MaxRow || back.MaxCol != result.MaxCol {
+		t.Errorf("Result ends at [%d,%d], want [%d,%d]", back.MaxRow, back.MaxCol, result.MaxRow, result.MaxCol)
+	}
+
+	// The leading TT is clipped, and the clip survives the round trip
+	if got := back.CIGAR(); got != export.CIGAR || got != "2S7M" {
+		t.Errorf("CIGAR after the round trip = %q, want %q (exported as %q)", got, "2S7M", export.CIGAR)
+	}
+	if got := AlignmentStats(back).Coverage; got != export.Stats.Coverage || got == 1 {
+		t.Errorf("Coverage after the round trip = %f, want %f", got, export.Stats.Coverage)
+	}
+
+	// An empty alignment still exports mutations as an array
+	if data, _ := json.Marshal(NewAlignmentExport(SmithWaterman("AAAA", "CCCC"))); !strings.Contains(string(data), `"mutations":[]`) {
+		t.Errorf("Expected an empty mutations array, got %s", data)
+	}
+}
+
+// TestParseAlignmentJSONErrors checks that unversioned, future, and malformed
+// documents are rejected
+func TestParseAlignmentJSONErrors(t *testing.T) {
+	tests := []struct{ name, doc, message string }{
+		{"missing version", `{"alignedQuery": "GATT", "alignedRef": "GATT"}`, "missing schemaVersion"},
+		{"future version", `{"schemaVersion": 99}`, "unsupported schemaVersion 99"},
+		{"length mismatch", `{"schemaVersion": 1, "alignedQuery": "GATT", "alignedRef": "GAT"}`, "4 columns"},
+		{"malformed", `{"schemaVersion": `, "unexpected end"},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseAlignmentJSON([]byte(tt.doc)); err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.message, err)
+		}
+	}
+}
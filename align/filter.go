@@ -0,0 +1,58 @@
+package align
+
+import "pgfp/data"
+
+// SmithWatermanBatchFiltered aligns a query against many references, running the
+// full alignment only for references that share at least minShared distinct k-mers
+// with the query. Building and comparing k-mer sets is linear in the sequence
+// lengths, so when most references are unrelated this is far faster than
+// ConcurrentSmithWatermanBatch.
+//
+// The filter trades sensitivity for speed: a divergent reference whose matches are
+// too short or too scattered to form shared k-mers is skipped even if it would have
+// a positive local alignment score. Smaller k and minShared keep more candidates;
+// larger values skip more references.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - references ([]string): The reference DNA sequences.
+//   - k (int): The k-mer length used for screening.
+//   - minShared (int): The minimum number of distinct shared k-mers for a reference
+//     to be aligned. Values of zero or less align every reference.
+//
+// Returns:
+//   - ([]AlignmentResult): One result per reference, in input order. Skipped
+//     references get a zero-score result with NoAlignment set.
+func SmithWatermanBatchFiltered(query string, references []string, k, minShared int) []AlignmentResult {
+	results := make([]AlignmentResult, len(references))
+	queryKmers := data.KmerSet(query, k)
+
+	// Collect the references that pass the screen, remembering their positions
+	var candidates []string
+	var positions []int
+	for i, ref := range references {
+		if minShared <= 0 || sharedKmers(queryKmers, ref, k) >= minShared {
+			candidates = append(candidates, ref)
+			positions = append(positions, i)
+			continue
+		}
+		results[i] = AlignmentResult{NoAlignment: true, ClippedSuffix: query}
+	}
+
+	ConcurrentSmithWatermanBatchFunc(query, candidates, 0, func(index int, result AlignmentResult) {
+		results[positions[index]] = result
+	})
+
+	return results
+}
+
+// sharedKmers counts the distinct k-mers of seq that are also in kmers.
+func sharedKmers(kmers map[string]struct{}, seq string, k int) int {
+	shared := 0
+	for kmer := range data.KmerSet(seq, k) {
+		if _, ok := kmers[kmer]; ok {
+			shared++
+		}
+	}
+	return shared
+}
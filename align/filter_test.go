@@ -0,0 +1,41 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanBatchFiltered checks that references without shared k-mers are skipped
+// and the rest match a full alignment
+func TestSmithWatermanBatchFiltered(t *testing.T) {
+	query := "GATTACAGATTACAGGCT"
+	references := []string{
+		"CCCGATTACAGATTACAGGCTCCC", // Contains the query
+		strings.Repeat("AC", 12),   // Shares no 6-mer with the query
+		"GATTACAGATCACAGGCT",       // One mismatch
+	}
+
+	results := SmithWatermanBatchFiltered(query, references, 6, 2)
+	if len(results) != len(references) {
+		t.Fatalf("Expected %d results, got %d", len(references), len(results))
+	}
+
+	if !results[1].NoAlignment || results[1].MaxScore != 0 || results[1].ScoreMatrix != nil {
+		t.Errorf("Expected the unrelated reference to be skipped, got score %d", results[1].MaxScore)
+	}
+	if full := SmithWaterman(query, references[1]); full.MaxScore == 0 {
+		t.Fatalf("Test reference should have a positive full alignment score to show the filter skipped it")
+	}
+
+	for _, i := range []int{0, 2} {
+		want := SmithWaterman(query, references[i])
+		if results[i].MaxScore != want.MaxScore || results[i].AlignedQuery != want.AlignedQuery {
+			t.Errorf("Reference %d: score %d, want %d", i, results[i].MaxScore, want.MaxScore)
+		}
+	}
+
+	// minShared of zero disables the filter
+	if results := SmithWatermanBatchFiltered(query, references, 6, 0); results[1].NoAlignment {
+		t.Error("Expected every reference to be aligned with the filter disabled")
+	}
+}
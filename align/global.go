@@ -0,0 +1,103 @@
+package align
+
+// NeedlemanWunsch performs global alignment of two sequences using the
+// Needleman-Wunsch algorithm. Unlike SmithWaterman, every base of both sequences
+// takes part in the alignment: the first row and column carry cumulative gap
+// penalties, cells may go negative, and the traceback runs from the bottom-right
+// corner back to the top-left.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment matrix and results. MaxScore
+//     holds the global alignment score, and the aligned regions span both sequences.
+func NeedlemanWunsch(query, reference string) AlignmentResult {
+	m, n := len(query), len(reference)
+	sc := defaultScorer()
+
+	// Initialize score matrix with cumulative gap penalties along the edges
+	matrix := make([][]int, m+1)
+	for i := range matrix {
+		matrix[i] = make([]int, n+1)
+		matrix[i][0] = i * sc.gap
+	}
+	for j := 1; j <= n; j++ {
+		matrix[0][j] = j * sc.gap
+	}
+
+	// Fill the score matrix
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			match := sc.score(query, reference, i-1, j-1)
+
+			scoreDiag := matrix[i-1][j-1] + match
+			scoreUp := matrix[i-1][j] + sc.gap
+			scoreLeft := matrix[i][j-1] + sc.gap
+
+			// Negative scores are kept, since the alignment may not restart
+			matrix[i][j] = smithMax(scoreDiag, scoreUp, scoreLeft)
+		}
+	}
+
+	alignedQuery, alignedRef := globalTraceback(matrix, query, reference, sc)
+
+	return AlignmentResult{
+		ScoreMatrix:  matrix,
+		MaxScore:     matrix[m][n],
+		MaxRow:       m,
+		MaxCol:       n,
+		AlignedQuery: alignedQuery,
+		AlignedRef:   alignedRef,
+		QueryStart:   0,
+		QueryEnd:     m,
+		RefStart:     0,
+		RefEnd:       n,
+		NoAlignment:  m == 0 && n == 0,
+	}
+}
+
+// globalTraceback reconstructs the global alignment from the bottom-right corner
+// of a Needleman-Wunsch matrix. Once either sequence is exhausted, the remaining
+// bases of the other are aligned against gaps.
+//
+// Parameters:
+//   - matrix ([][]int): The alignment score matrix.
+//   - query (string): The query DNA sequence.
+//   - reference (string): The reference DNA sequence.
+//   - sc (scorer): The scores used to fill the matrix.
+//
+// Returns:
+//   - (string, string): The aligned query and reference sequences.
+func globalTraceback(matrix [][]int, query, reference string, sc scorer) (string, string) {
+	row, col := len(query), len(reference)
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(row, col))
+	alignedRef := make([]byte, 0, MaxAlignmentLength(row, col))
+
+	for row > 0 || col > 0 {
+		switch {
+		case row > 0 && col > 0 && matrix[row][col] == matrix[row-1][col-1]+sc.score(query, reference, row-1, col-1):
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, reference[col-1])
+			row--
+			col--
+		case row > 0 && matrix[row][col] == matrix[row-1][col]+sc.gap:
+			// Gap in reference
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
+			row--
+		default:
+			// Gap in query
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
+			col--
+		}
+	}
+
+	// The alignment was built back to front
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return string(alignedQuery), string(alignedRef)
+}
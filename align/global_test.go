@@ -0,0 +1,36 @@
+package align
+
+import "testing"
+
+// TestNeedlemanWunsch checks global alignments against hand-computed results
+func TestNeedlemanWunsch(t *testing.T) {
+	tests := []struct {
+		query, reference   string
+		score              int
+		alignedQ, alignedR string
+	}{
+		// Three matches and four mismatches beat any gapped alignment: 3*2 - 4 = 2
+		{"GCATGCU", "GATTACA", 2, "GCATGCU", "GATTACA"},
+		// One deletion: 3*2 - 2 = 4
+		{"ACGT", "AGT", 4, "ACGT", "A-GT"},
+		{"ACGT", "ACGT", 8, "ACGT", "ACGT"},
+		// Unlike a local alignment, the unmatched ends are still aligned against gaps
+		{"AAACGTAAA", "CGT", -6, "AAACGTAAA", "---CGT---"},
+		{"ACG", "", -6, "ACG", "---"},
+	}
+
+	for _, tt := range tests {
+		result := NeedlemanWunsch(tt.query, tt.reference)
+
+		if result.MaxScore != tt.score {
+			t.Errorf("NeedlemanWunsch(%q, %q) score = %d, want %d", tt.query, tt.reference, result.MaxScore, tt.score)
+		}
+		if result.AlignedQuery != tt.alignedQ || result.AlignedRef != tt.alignedR {
+			t.Errorf("NeedlemanWunsch(%q, %q) = %q/%q, want %q/%q",
+				tt.query, tt.reference, result.AlignedQuery, result.AlignedRef, tt.alignedQ, tt.alignedR)
+		}
+		if result.QueryStart != 0 || result.QueryEnd != len(tt.query) || result.RefStart != 0 || result.RefEnd != len(tt.reference) {
+			t.Errorf("NeedlemanWunsch(%q, %q) should span both sequences", tt.query, tt.reference)
+		}
+	}
+}
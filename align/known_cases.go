@@ -0,0 +1,106 @@
+package align
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KnownCase is an alignment with a hand-computed expected score, used by the
+// package tests and by cmd/selftest to check an installation.
+type KnownCase struct {
+	Name          string // Short description of what the case exercises
+	Query         string
+	Reference     string
+	ExpectedScore int
+	// Substrings that must appear in both aligned sequences once gaps are removed;
+	// ties can make several alignments optimal, so the exact rows are not checked
+	RequiredMatches []string
+}
+
+// KnownCases returns small alignments whose scores can be worked out by hand with
+// the default scoring constants. Each call returns a fresh slice, so callers
+// cannot change the cases seen by others.
+//
+// Returns:
+//   - ([]KnownCase): The known cases.
+func KnownCases() []KnownCase {
+	return []KnownCase{
+		// Score = 7*2 = 14
+		{
+			Name:            "perfect match",
+			Query:           "GATTACA",
+			Reference:       "GATTACA",
+			ExpectedScore:   14,
+			RequiredMatches: []string{"GATTACA"},
+		},
+		// Score = (6*2) - 1 = 11
+		{
+			Name:            "one mismatch",
+			Query:           "GATTACA",
+			Reference:       "GATTTCA",
+			ExpectedScore:   11,
+			RequiredMatches: []string{"GAT", "CA"}, // Before and after the mismatch
+		},
+		// Score = (6*2) - 2 = 10
+		{
+			Name:            "deletion",
+			Query:           "GATTACA",
+			Reference:       "GATACA",
+			ExpectedScore:   10,
+			RequiredMatches: []string{"GAT", "ACA"}, // Before and after the indel
+		},
+		// Score = (6*2) - 2 = 10
+		{
+			Name:            "deletion near the end",
+			Query:           "GATTACA",
+			Reference:       "GATTCA",
+			ExpectedScore:   10,
+			RequiredMatches: []string{"GATT", "CA"}, // Before and after the indel
+		},
+		{
+			Name:            "partial match at beginning",
+			Query:           "GATTACA",
+			Reference:       "GATXXXX",
+			ExpectedScore:   6, // 3 matches * 2
+			RequiredMatches: []string{"GAT"},
+		},
+		{
+			Name:            "partial match at end",
+			Query:           "GATTACA",
+			Reference:       "XXXXACA",
+			ExpectedScore:   6, // 3 matches * 2
+			RequiredMatches: []string{"ACA"},
+		},
+		{
+			Name:            "best local subsection",
+			Query:           "XXGATTACAXX",
+			Reference:       "YYGATTACAYY",
+			ExpectedScore:   14, // Should find the GATTACA substring
+			RequiredMatches: []string{"GATTACA"},
+		},
+	}
+}
+
+// Check compares an alignment of the case's sequences with the expected results.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment of Query against Reference.
+//
+// Returns:
+//   - (error): nil if the score matches and every required match appears in both
+//     aligned sequences, otherwise an error describing the first difference.
+func (c KnownCase) Check(result AlignmentResult) error {
+	if result.MaxScore != c.ExpectedScore {
+		return fmt.Errorf("expected score %d, got %d", c.ExpectedScore, result.MaxScore)
+	}
+
+	alignedQuery := strings.ReplaceAll(result.AlignedQuery, "-", "")
+	alignedRef := strings.ReplaceAll(result.AlignedRef, "-", "")
+	for _, match := range c.RequiredMatches {
+		if !strings.Contains(alignedQuery, match) || !strings.Contains(alignedRef, match) {
+			return fmt.Errorf("alignment %s/%s does not contain %s", result.AlignedQuery, result.AlignedRef, match)
+		}
+	}
+
+	return nil
+}
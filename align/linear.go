@@ -0,0 +1,208 @@
+package align
+
+// SmithWatermanLinear performs the same local alignment as SmithWaterman in
+// memory linear in the length of the shorter sequence, so that long sequences can
+// be aligned without materializing the (m+1)×(n+1) score matrix.
+//
+// The alignment is found in three linear-space passes: a forward pass keeps only
+// two matrix rows to find the maximum score and where it ends, a reverse pass from
+// that cell finds where an optimal alignment starts, and Hirschberg's
+// divide-and-conquer algorithm recovers the global alignment of the region between
+// the two, which scores the same as the local alignment. Time is still O(m×n).
+//
+// When several alignments are optimal, the one returned may differ from the
+// SmithWaterman traceback, but it has the same score.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. ScoreMatrix is always nil, since
+//     materializing it would defeat the purpose.
+func SmithWatermanLinear(query, reference string) AlignmentResult {
+	return smithWatermanLinear(query, reference, false)
+}
+
+// smithWatermanLinear implements SmithWatermanLinear. transposed is set when query
+// and reference have been swapped, so that ties are still broken in the caller's
+// row-major order.
+func smithWatermanLinear(query, reference string, transposed bool) AlignmentResult {
+	// Keep the rows over the shorter sequence; local alignment scores are symmetric
+	if len(reference) > len(query) {
+		swapped := smithWatermanLinear(reference, query, true)
+		result := AlignmentResult{
+			MaxScore:     swapped.MaxScore,
+			MaxRow:       swapped.MaxCol,
+			MaxCol:       swapped.MaxRow,
+			AlignedQuery: swapped.AlignedRef,
+			AlignedRef:   swapped.AlignedQuery,
+			QueryStart:   swapped.RefStart,
+			QueryEnd:     swapped.RefEnd,
+			RefStart:     swapped.QueryStart,
+			RefEnd:       swapped.QueryEnd,
+			NoAlignment:  swapped.NoAlignment,
+		}
+		result.ClippedPrefix = query[:result.QueryStart]
+		result.ClippedSuffix = query[result.QueryEnd:]
+		return result
+	}
+
+	sc := defaultScorer()
+	maxScore, endRow, endCol := localEnd(query, reference, sc, transposed)
+	if maxScore == 0 {
+		return AlignmentResult{NoAlignment: true, ClippedSuffix: query}
+	}
+
+	startRow, startCol := localStart(query[:endRow], reference[:endCol], maxScore, sc)
+
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(endRow-startRow, endCol-startCol))
+	alignedRef := make([]byte, 0, cap(alignedQuery))
+	alignedQuery, alignedRef = hirschberg(query[startRow:endRow], reference[startCol:endCol], sc, alignedQuery, alignedRef)
+
+	return AlignmentResult{
+		MaxScore:      maxScore,
+		MaxRow:        endRow,
+		MaxCol:        endCol,
+		AlignedQuery:  string(alignedQuery),
+		AlignedRef:    string(alignedRef),
+		QueryStart:    startRow,
+		QueryEnd:      endRow,
+		RefStart:      startCol,
+		RefEnd:        endCol,
+		ClippedPrefix: query[:startRow],
+		ClippedSuffix: query[endRow:],
+	}
+}
+
+// localEnd fills the Smith-Waterman matrix two rows at a time and returns the
+// maximum score with the row and column where it first occurs in row-major order,
+// or in column-major order when transposed is set.
+func localEnd(query, reference string, sc scorer, transposed bool) (maxScore, maxRow, maxCol int) {
+	prev := make([]int, len(reference)+1)
+	curr := make([]int, len(reference)+1)
+	best := alignerCell{}
+
+	for i := 1; i <= len(query); i++ {
+		for j := 1; j <= len(reference); j++ {
+			curr[j] = smithMax(0,
+				prev[j-1]+sc.score(query, reference, i-1, j-1),
+				prev[j]+sc.gap,
+				curr[j-1]+sc.gap)
+
+			cell := alignerCell{score: curr[j], row: i, col: j}
+			if transposed {
+				cell.row, cell.col = j, i
+			}
+			if isBetterCell(cell, best) {
+				best = cell
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	if transposed {
+		return best.score, best.col, best.row
+	}
+	return best.score, best.row, best.col
+}
+
+// localStart finds where an optimal local alignment ending at the end of both
+// query and reference begins. It scores global alignments of ever longer suffixes
+// of the two, two rows at a time, and stops at the first pair whose score reaches
+// maxScore.
+func localStart(query, reference string, maxScore int, sc scorer) (startRow, startCol int) {
+	m, n := len(query), len(reference)
+	prev := make([]int, n+1)
+	curr := make([]int, n+1)
+
+	// Row 0 aligns an empty query suffix against reference suffixes
+	for j := 1; j <= n; j++ {
+		prev[j] = prev[j-1] + sc.gap
+	}
+
+	for i := 1; i <= m; i++ {
+		curr[0] = prev[0] + sc.gap
+		for j := 1; j <= n; j++ {
+			// Suffixes of length i and j end at the alignment's end cell
+			curr[j] = smithMax(
+				prev[j-1]+sc.score(query, reference, m-i, n-j),
+				prev[j]+sc.gap,
+				curr[j-1]+sc.gap)
+
+			if curr[j] == maxScore {
+				return m - i, n - j
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return 0, 0
+}
+
+// hirschberg appends an optimal global alignment of query and reference to the
+// aligned buffers using Hirschberg's algorithm: the query is split in half, the
+// reference is split where the forward and reverse scores of the two halves sum
+// to the best total, and each half is aligned recursively.
+func hirschberg(query, reference string, sc scorer, alignedQuery, alignedRef []byte) ([]byte, []byte) {
+	switch {
+	case len(query) == 0:
+		for i := 0; i < len(reference); i++ {
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[i])
+		}
+		return alignedQuery, alignedRef
+
+	case len(query) == 1 || len(reference) == 0:
+		// Small enough to align with the full matrix
+		result := NeedlemanWunsch(query, reference)
+		return append(alignedQuery, result.AlignedQuery...), append(alignedRef, result.AlignedRef...)
+	}
+
+	mid := len(query) / 2
+	forward := globalLastRow(query[:mid], reference, sc, false)
+	reverse := globalLastRow(query[mid:], reference, sc, true)
+
+	// Split the reference where the two halves score best together
+	split, best := 0, forward[0]+reverse[len(reference)]
+	for j := 1; j <= len(reference); j++ {
+		if score := forward[j] + reverse[len(reference)-j]; score > best {
+			split, best = j, score
+		}
+	}
+
+	alignedQuery, alignedRef = hirschberg(query[:mid], reference[:split], sc, alignedQuery, alignedRef)
+	return hirschberg(query[mid:], reference[split:], sc, alignedQuery, alignedRef)
+}
+
+// globalLastRow returns the last row of the Needleman-Wunsch matrix for query
+// against reference, keeping only two rows. Entry j is the global score of query
+// against the first j bases of reference, or against the last j bases when
+// reversed is set (both sequences are then read back to front).
+func globalLastRow(query, reference string, sc scorer, reversed bool) []int {
+	m, n := len(query), len(reference)
+	prev := make([]int, n+1)
+	curr := make([]int, n+1)
+
+	for j := 1; j <= n; j++ {
+		prev[j] = prev[j-1] + sc.gap
+	}
+
+	for i := 1; i <= m; i++ {
+		curr[0] = prev[0] + sc.gap
+		for j := 1; j <= n; j++ {
+			qi, rj := i-1, j-1
+			if reversed {
+				qi, rj = m-i, n-j
+			}
+
+			curr[j] = smithMax(
+				prev[j-1]+sc.score(query, reference, qi, rj),
+				prev[j]+sc.gap,
+				curr[j-1]+sc.gap)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev
+}
@@ -0,0 +1,89 @@
+package align
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanLinear checks that the linear-memory alignment matches SmithWaterman
+func TestSmithWatermanLinear(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	bases := "ACGT"
+	randomDNA := func(length int) string {
+		seq := make([]byte, length)
+		for i := range seq {
+			seq[i] = bases[r.Intn(len(bases))]
+		}
+		return string(seq)
+	}
+
+	// mutate substitutes, inserts, and deletes bases at roughly the given rate
+	mutate := func(seq string, rate float64) string {
+		var b strings.Builder
+		for i := 0; i < len(seq); i++ {
+			switch p := r.Float64(); {
+			case p < rate/3:
+				b.WriteByte(bases[r.Intn(len(bases))])
+			case p < 2*rate/3:
+				b.WriteByte(seq[i])
+				b.WriteByte(bases[r.Intn(len(bases))])
+			case p < rate:
+				// Deleted
+			default:
+				b.WriteByte(seq[i])
+			}
+		}
+		return b.String()
+	}
+
+	core := randomDNA(400)
+	cases := [][2]string{
+		{"GATTACA", "GATTACA"},
+		{"ACGTACGT", "TTACGTAA"},
+		{"AAAA", "CCCC"},
+		{"", "GATTACA"},
+		{randomDNA(50) + core + randomDNA(30), randomDNA(20) + mutate(core, 0.1) + randomDNA(60)},
+		{mutate(core, 0.2), core},
+		{randomDNA(300), randomDNA(250)},
+	}
+
+	for i, c := range cases {
+		query, reference := c[0], c[1]
+		want := SmithWaterman(query, reference)
+		got := SmithWatermanLinear(query, reference)
+
+		if got.ScoreMatrix != nil {
+			t.Errorf("Case %d: expected a nil score matrix", i)
+		}
+		if got.MaxScore != want.MaxScore {
+			t.Errorf("Case %d: score = %d, want %d", i, got.MaxScore, want.MaxScore)
+		}
+		if got.NoAlignment != want.NoAlignment {
+			t.Errorf("Case %d: NoAlignment = %t, want %t", i, got.NoAlignment, want.NoAlignment)
+		}
+		if score := AlignmentScore(got.AlignedQuery, got.AlignedRef); score != got.MaxScore {
+			t.Errorf("Case %d: alignment rescores to %d, want %d", i, score, got.MaxScore)
+		}
+		if got.QueryEnd != want.QueryEnd || got.RefEnd != want.RefEnd {
+			t.Errorf("Case %d: alignment ends at (%d,%d), want (%d,%d)", i, got.QueryEnd, got.RefEnd, want.QueryEnd, want.RefEnd)
+		}
+
+		// Equal-scoring alignments can differ in gap placement and even length (two
+		// mismatches score the same as two gaps and a match), so only short inputs with
+		// a unique optimum are compared column for column
+		if len(query) < 50 && !AlignmentsEquivalent(got.AlignedQuery, got.AlignedRef, want.AlignedQuery, want.AlignedRef) {
+			t.Errorf("Case %d: alignment not equivalent to SmithWaterman:\n%s\n%s\nwant\n%s\n%s",
+				i, got.AlignedQuery, got.AlignedRef, want.AlignedQuery, want.AlignedRef)
+		}
+
+		// The aligned rows must be exactly the bases between the reported coordinates
+		if strings.ReplaceAll(got.AlignedQuery, "-", "") != query[got.QueryStart:got.QueryEnd] ||
+			strings.ReplaceAll(got.AlignedRef, "-", "") != reference[got.RefStart:got.RefEnd] {
+			t.Errorf("Case %d: aligned rows do not match coordinates", i)
+		}
+		if got.ClippedPrefix+query[got.QueryStart:got.QueryEnd]+got.ClippedSuffix != query {
+			t.Errorf("Case %d: clipped bases do not reassemble the query", i)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package align
+
+import "sort"
+
+// ProgressiveMSA builds a multiple sequence alignment by star alignment: the
+// sequence with the highest total global alignment score against all the others is
+// chosen as the centre, and the rest are aligned to it one at a time, most similar
+// first. Each new pairwise alignment is merged into the growing alignment through
+// the centre row, so a gap opened for one sequence becomes a gap column in every
+// row ("once a gap, always a gap").
+//
+// Unlike comparing the raw inputs position by position, the rows share one
+// coordinate frame even when the sequences differ by indels, so they can be passed
+// straight to data.GenerateGappedConsensus. Star alignment is a heuristic: its
+// columns are only as good as each sequence's alignment to the centre.
+//
+// Parameters:
+//   - sequences ([]string): The DNA sequences to align.
+//
+// Returns:
+//   - ([]string): The aligned rows, in input order, padded with '-' to a common
+//     length. Removing the gaps from a row gives back its input sequence. Nil if
+//     sequences is empty.
+func ProgressiveMSA(sequences []string) []string {
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	// Score every pair once; global scores are symmetric
+	total := make([]int, len(sequences))
+	scores := make([][]int, len(sequences))
+	for i := range scores {
+		scores[i] = make([]int, len(sequences))
+	}
+	for i := range sequences {
+		for j := i + 1; j < len(sequences); j++ {
+			score := NeedlemanWunsch(sequences[i], sequences[j]).MaxScore
+			scores[i][j], scores[j][i] = score, score
+			total[i] += score
+			total[j] += score
+		}
+	}
+
+	// The centre is the sequence closest to all others; ties go to the first
+	center := 0
+	for i := range total {
+		if total[i] > total[center] {
+			center = i
+		}
+	}
+
+	// Add the other sequences in order of decreasing similarity to the centre
+	order := make([]int, 0, len(sequences)-1)
+	for i := range sequences {
+		if i != center {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[center][order[a]] > scores[center][order[b]]
+	})
+
+	rows := make([][]byte, len(sequences))
+	rows[center] = []byte(sequences[center])
+	for _, i := range order {
+		pair := NeedlemanWunsch(sequences[center], sequences[i])
+		rows[i] = mergeIntoStar(rows, center, pair.AlignedQuery, pair.AlignedRef)
+	}
+
+	aligned := make([]string, len(rows))
+	for i, row := range rows {
+		aligned[i] = string(row)
+	}
+	return aligned
+}
+
+// mergeIntoStar adds a sequence to a star alignment given its pairwise alignment to
+// the centre. Gap columns the pairwise alignment opens in the centre are inserted
+// into every existing row, and the new row is padded for gap columns it lacks.
+//
+// Parameters:
+//   - rows ([][]byte): The current alignment rows, indexed like the input sequences.
+//     Rows not yet added are nil. Existing rows are replaced in place.
+//   - center (int): The index of the centre row.
+//   - pairCenter (string): The centre's row in the pairwise alignment.
+//   - pairSeq (string): The new sequence's row in the pairwise alignment.
+//
+// Returns:
+//   - ([]byte): The new sequence's row in the merged alignment.
+func mergeIntoStar(rows [][]byte, center int, pairCenter, pairSeq string) []byte {
+	centerRow := rows[center]
+	width := len(centerRow) + len(pairCenter)
+
+	merged := make([][]byte, len(rows))
+	for r, row := range rows {
+		if row != nil {
+			merged[r] = make([]byte, 0, width)
+		}
+	}
+	added := make([]byte, 0, width)
+
+	i, j := 0, 0
+	for i < len(centerRow) || j < len(pairCenter) {
+		switch {
+		case i < len(centerRow) && j < len(pairCenter) && (centerRow[i] == '-') == (pairCenter[j] == '-'):
+			// The same centre base, or a gap column both alignments already share
+			for r := range merged {
+				if merged[r] != nil {
+					merged[r] = append(merged[r], rows[r][i])
+				}
+			}
+			added = append(added, pairSeq[j])
+			i++
+			j++
+		case i < len(centerRow) && centerRow[i] == '-':
+			// A gap column from an earlier sequence; the new one has nothing here
+			for r := range merged {
+				if merged[r] != nil {
+					merged[r] = append(merged[r], rows[r][i])
+				}
+			}
+			added = append(added, '-')
+			i++
+		default:
+			// The new sequence has bases against a gap in the centre: open a new column
+			for r := range merged {
+				if merged[r] != nil {
+					merged[r] = append(merged[r], '-')
+				}
+			}
+			added = append(added, pairSeq[j])
+			j++
+		}
+	}
+
+	copy(rows, merged)
+	return added
+}
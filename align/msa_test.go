@@ -0,0 +1,57 @@
+package align
+
+import (
+	"strings"
+	"testing"
+
+	"pgfp/data"
+)
+
+// TestProgressiveMSA checks that the rows share a common length, keep their input
+// bases, never form an all-gap column, and line indels up so the consensus is right
+func TestProgressiveMSA(t *testing.T) {
+	original := "GATTACAGGCATCCGATTACA"
+	sequences := []string{
+		data.CreateInsertion(original, 8, "TT"),
+		original,
+		data.CreateDeletion(original, 4, 2),
+		data.CreateSNP(original, 15),
+		original,
+	}
+
+	rows := ProgressiveMSA(sequences)
+	if len(rows) != len(sequences) {
+		t.Fatalf("Expected %d rows, got %d", len(sequences), len(rows))
+	}
+
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			t.Errorf("Row %d has length %d, want %d", i, len(row), len(rows[0]))
+		}
+		if got := strings.ReplaceAll(row, "-", ""); got != sequences[i] {
+			t.Errorf("Row %d without gaps is %s, want %s", i, got, sequences[i])
+		}
+	}
+
+	for col := range rows[0] {
+		allGaps := true
+		for _, row := range rows {
+			allGaps = allGaps && row[col] == '-'
+		}
+		if allGaps {
+			t.Errorf("Column %d is a gap in every row", col)
+		}
+	}
+
+	// Voting on the aligned rows recovers the original once gap columns are dropped
+	if consensus := strings.ReplaceAll(data.GenerateGappedConsensus(rows, 0.5), "-", ""); consensus != original {
+		t.Errorf("Consensus of the alignment is %s, want %s", consensus, original)
+	}
+
+	if ProgressiveMSA(nil) != nil {
+		t.Error("Expected nil for no sequences")
+	}
+	if single := ProgressiveMSA([]string{"GATTACA"}); len(single) != 1 || single[0] != "GATTACA" {
+		t.Errorf("Expected a single sequence unchanged, got %v", single)
+	}
+}
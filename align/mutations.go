@@ -0,0 +1,117 @@
+package align
+
+// Mutation types reported by DetectMutations
+const (
+	MutationSNP       = "snp"
+	MutationInsertion = "insertion"
+	MutationDeletion  = "deletion"
+)
+
+// Mutation is one difference between the query and the reference in an alignment.
+type Mutation struct {
+	Type     string `json:"type"`     // MutationSNP, MutationInsertion, or MutationDeletion
+	Position int    `json:"position"` // Reference position, counted from the first aligned reference base
+	Length   int    `json:"length"`   // Number of bases substituted, inserted, or deleted
+	Original string `json:"original"` // Reference bases ("-" for an insertion)
+	Mutated  string `json:"mutated"`  // Query bases ("-" for a deletion)
+}
+
+// DetectMutations lists the SNPs, insertions, and deletions in an alignment. Each
+// mismatched column is one SNP, and each run of consecutive gaps on the same side
+// is one insertion (bases only in the query) or deletion (bases only in the
+// reference), however many bases it spans.
+//
+// Every position is in reference coordinates, counted from the first aligned
+// reference base (add RefStart for a position in the whole reference). An SNP or
+// deletion is at the first reference base it affects; an insertion is at the
+// reference base it precedes. To report indels in a repeat at a canonical position,
+// use DetectMutationsNormalized.
+//
+// Parameters:
+//   - alignedQuery (string): The aligned query sequence, with '-' for gaps.
+//   - alignedRef (string): The aligned reference sequence, with '-' for gaps.
+//
+// Returns:
+//   - ([]Mutation): The mutations in alignment order; empty if the rows are identical.
+func DetectMutations(alignedQuery, alignedRef string) []Mutation {
+	mutations := []Mutation{}
+
+	// Position in the reference, which every mutation is reported against
+	refPos := 0
+
+	// Type of the gap run being extended, or "" after a match or SNP
+	current := ""
+
+	for i := 0; i < len(alignedQuery) && i < len(alignedRef); i++ {
+		switch {
+		case alignedQuery[i] == '-':
+			// Gap in query = deletion
+			if current == MutationDeletion {
+				last := &mutations[len(mutations)-1]
+				last.Original += string(alignedRef[i])
+				last.Length++
+			} else {
+				mutations = append(mutations, Mutation{
+					Type:     MutationDeletion,
+					Position: refPos,
+					Length:   1,
+					Original: string(alignedRef[i]),
+					Mutated:  "-",
+				})
+				current = MutationDeletion
+			}
+			refPos++
+
+		case alignedRef[i] == '-':
+			// Gap in reference = insertion before the next reference base
+			if current == MutationInsertion {
+				last := &mutations[len(mutations)-1]
+				last.Mutated += string(alignedQuery[i])
+				last.Length++
+			} else {
+				mutations = append(mutations, Mutation{
+					Type:     MutationInsertion,
+					Position: refPos,
+					Length:   1,
+					Original: "-",
+					Mutated:  string(alignedQuery[i]),
+				})
+				current = MutationInsertion
+			}
+
+		case alignedQuery[i] != alignedRef[i]:
+			// Mismatch = SNP
+			mutations = append(mutations, Mutation{
+				Type:     MutationSNP,
+				Position: refPos,
+				Length:   1,
+				Original: string(alignedRef[i]),
+				Mutated:  string(alignedQuery[i]),
+			})
+			refPos++
+			current = ""
+
+		default:
+			// Match = no mutation
+			refPos++
+			current = ""
+		}
+	}
+
+	return mutations
+}
+
+// DetectMutationsNormalized lists the mutations in an alignment like DetectMutations,
+// but left-aligns the indels first with LeftAlignIndels. An indel inside a repeat is
+// then reported at the same position however the aligner placed its gap, so results
+// are reproducible across runs and algorithms.
+//
+// Parameters:
+//   - alignedQuery (string): The aligned query sequence, with '-' for gaps.
+//   - alignedRef (string): The aligned reference sequence, with '-' for gaps.
+//
+// Returns:
+//   - ([]Mutation): The mutations of the normalized alignment, in alignment order.
+func DetectMutationsNormalized(alignedQuery, alignedRef string) []Mutation {
+	return DetectMutations(LeftAlignIndels(alignedQuery, alignedRef))
+}
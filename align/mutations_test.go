@@ -0,0 +1,53 @@
+package align
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDetectMutations checks that every mutation type is reported in reference coordinates
+func TestDetectMutations(t *testing.T) {
+	// Query:     GATCA--CAGTTTA
+	// Reference: GATTACACAG--TA
+	// The SNP at column 3 is at reference position 3; the deletion of AC starts at
+	// reference position 5, and the insertion of TT precedes reference position 10
+	mutations := DetectMutations("GATCA--CAGTTTA", "GATTACACAG--TA")
+
+	want := []Mutation{
+		{Type: MutationSNP, Position: 3, Length: 1, Original: "T", Mutated: "C"},
+		{Type: MutationDeletion, Position: 5, Length: 2, Original: "CA", Mutated: "-"},
+		{Type: MutationInsertion, Position: 10, Length: 2, Original: "-", Mutated: "TT"},
+	}
+	if !reflect.DeepEqual(mutations, want) {
+		t.Errorf("DetectMutations() = %+v, want %+v", mutations, want)
+	}
+
+	// An SNP right after an insertion shares its reference position
+	mutations = DetectMutations("AGGCT", "A--GT")
+	if mutations[0].Position != 1 || mutations[1].Position != 1 {
+		t.Errorf("Expected the insertion and SNP both at reference position 1, got %+v", mutations)
+	}
+
+	if mutations := DetectMutations("GATTACA", "GATTACA"); len(mutations) != 0 {
+		t.Errorf("Expected no mutations for identical rows, got %+v", mutations)
+	}
+}
+
+// TestDetectMutationsNormalized checks that a deletion in a repeat is reported at one position
+// regardless of where the gap was placed
+func TestDetectMutationsNormalized(t *testing.T) {
+	placements := []string{"GATT-ACA", "GAT-TACA", "GA-TTACA"}
+	reference := "GATTTACA"
+
+	want := []Mutation{{Type: MutationDeletion, Position: 2, Length: 1, Original: "T", Mutated: "-"}}
+	for _, alignedQuery := range placements {
+		if mutations := DetectMutationsNormalized(alignedQuery, reference); !reflect.DeepEqual(mutations, want) {
+			t.Errorf("DetectMutationsNormalized(%s, %s) = %+v, want %+v", alignedQuery, reference, mutations, want)
+		}
+	}
+
+	// Without normalization the position follows the gap
+	if mutations := DetectMutations(placements[0], reference); mutations[0].Position != 4 {
+		t.Errorf("Expected the unnormalized deletion at position 4, got %d", mutations[0].Position)
+	}
+}
@@ -0,0 +1,54 @@
+package align
+
+// LeftAlignIndels shifts every gap run in an alignment as far left as it can go
+// without changing the aligned sequences or the score, the normalization VCF uses
+// for indels. Inside a repeat an indel can be placed at several equally good
+// positions; after left-alignment every placement yields the same columns, so
+// indel positions are reproducible across runs and algorithms.
+//
+// A gap run moves one column left when the base before it on the gapped side's
+// partner sequence equals the last base the run covers: the two base pairs swap
+// places, so the pair of columns scores the same.
+//
+// Parameters:
+//   - alignedQuery (string): The aligned query, with '-' for gaps.
+//   - alignedRef (string): The aligned reference, with '-' for gaps.
+//
+// Returns:
+//   - (string, string): The normalized aligned query and reference.
+func LeftAlignIndels(alignedQuery, alignedRef string) (string, string) {
+	query, ref := []byte(alignedQuery), []byte(alignedRef)
+	length := min(len(query), len(ref))
+
+	// Shifting one run can let a later one move further, so repeat until stable
+	for changed := true; changed; {
+		changed = false
+		for i := 1; i < length; i++ {
+			if shiftGapLeft(query, ref, i, length) || shiftGapLeft(ref, query, i, length) {
+				changed = true
+			}
+		}
+	}
+
+	return string(query), string(ref)
+}
+
+// shiftGapLeft moves the gap run in gapped that starts at column start one column
+// left, if the column before it is ungapped and the move preserves the score.
+func shiftGapLeft(gapped, other []byte, start, length int) bool {
+	if gapped[start] != '-' || gapped[start-1] == '-' || other[start-1] == '-' {
+		return false
+	}
+
+	// Find the last column of the run
+	end := start
+	for end+1 < length && gapped[end+1] == '-' && other[end+1] != '-' {
+		end++
+	}
+	if other[start] == '-' || other[start-1] != other[end] {
+		return false
+	}
+
+	gapped[end], gapped[start-1] = gapped[start-1], '-'
+	return true
+}
@@ -0,0 +1,38 @@
+package align
+
+import "testing"
+
+// TestLeftAlignIndels checks that equivalent gap placements in a repeat normalize to the same columns
+func TestLeftAlignIndels(t *testing.T) {
+	testCases := []struct {
+		alignedQuery, alignedRef   string
+		expectedQuery, expectedRef string
+	}{
+		// A deleted T in a run of Ts, placed at each possible position
+		{"GATT-ACA", "GATTTACA", "GA-TTACA", "GATTTACA"},
+		{"GAT-TACA", "GATTTACA", "GA-TTACA", "GATTTACA"},
+		{"GA-TTACA", "GATTTACA", "GA-TTACA", "GATTTACA"},
+		// An inserted dinucleotide repeat unit
+		{"CACACAG", "CACA--G", "CACACAG", "--CACAG"},
+		// A deletion that cannot move because the neighbouring base differs
+		{"GAC-GT", "GACAGT", "GAC-GT", "GACAGT"},
+		// No gaps
+		{"GATTACA", "GATCACA", "GATTACA", "GATCACA"},
+	}
+
+	for _, tc := range testCases {
+		query, ref := LeftAlignIndels(tc.alignedQuery, tc.alignedRef)
+		if query != tc.expectedQuery || ref != tc.expectedRef {
+			t.Errorf("LeftAlignIndels(%s, %s) = %s, %s; expected %s, %s",
+				tc.alignedQuery, tc.alignedRef, query, ref, tc.expectedQuery, tc.expectedRef)
+		}
+
+		// Normalization preserves the sequences and the score
+		if stripGaps(query) != stripGaps(tc.alignedQuery) || stripGaps(ref) != stripGaps(tc.alignedRef) {
+			t.Errorf("Normalizing %s/%s changed the sequences", tc.alignedQuery, tc.alignedRef)
+		}
+		if AlignmentScore(query, ref) != AlignmentScore(tc.alignedQuery, tc.alignedRef) {
+			t.Errorf("Normalizing %s/%s changed the score", tc.alignedQuery, tc.alignedRef)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package align
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelAligner runs wave-front Smith-Waterman alignments on a fixed pool of
+// worker goroutines. The pool is created once and reused for every alignment,
+// which avoids spawning goroutines for each diagonal wave and keeps the number of
+// threads touching the matrix constant. This is most useful when many alignments
+// are run one after another. Go does not expose CPU affinity, but a stable pool
+// sized to the available cores is the closest practical equivalent.
+//
+// A ParallelAligner is safe for concurrent use. Call Close when it is no longer needed.
+type ParallelAligner struct {
+	numWorkers int
+	tasks      chan func()
+	closeOnce  sync.Once
+}
+
+// NewParallelAligner creates an aligner backed by a pool of worker goroutines.
+//
+// Parameters:
+//   - numWorkers (int): Number of goroutines in the pool (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - (*ParallelAligner): A ready-to-use aligner.
+func NewParallelAligner(numWorkers int) *ParallelAligner {
+	// If the number of workers is not specified, use the number of CPUs
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	a := &ParallelAligner{
+		numWorkers: numWorkers,
+		tasks:      make(chan func()),
+	}
+
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			for task := range a.tasks {
+				task()
+			}
+		}()
+	}
+
+	return a
+}
+
+// Close stops the worker goroutines. The aligner must not be used afterwards.
+func (a *ParallelAligner) Close() {
+	a.closeOnce.Do(func() {
+		close(a.tasks)
+	})
+}
+
+// Align performs a local alignment of query against reference using the pool.
+// Each anti-diagonal wave is split into one chunk per worker, and a wave only
+// starts once the previous one is complete, so every cell's dependencies are
+// filled before it is computed.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment matrix and results.
+func (a *ParallelAligner) Align(query, reference string) AlignmentResult {
+	m, n := len(query), len(reference)
+
+	// For very small sequences, just use sequential algorithm
+	if m < 50 || n < 50 {
+		return SmithWaterman(query, reference)
+	}
+
+	// Initialize score matrix
+	matrix := make([][]int, m+1)
+	for i := range matrix {
+		matrix[i] = make([]int, n+1)
+	}
+
+	best := alignerCell{}
+	chunkBest := make([]alignerCell, a.numWorkers)
+	var wg sync.WaitGroup
+
+	// Process the matrix one anti-diagonal wave at a time
+	for wave := 2; wave <= m+n; wave++ {
+		// Rows of this wave that fall inside the matrix
+		firstRow := max(1, wave-n)
+		lastRow := min(m, wave-1)
+		cells := lastRow - firstRow + 1
+
+		chunks := min(a.numWorkers, cells)
+		chunkSize := (cells + chunks - 1) / chunks
+
+		for c := 0; c < chunks; c++ {
+			start := firstRow + c*chunkSize
+			end := min(start+chunkSize-1, lastRow)
+			chunkBest[c] = alignerCell{}
+
+			wg.Add(1)
+			a.tasks <- func() {
+				defer wg.Done()
+				chunkBest[c] = fillWaveChunk(matrix, query, reference, wave, start, end)
+			}
+		}
+
+		// Wait for the whole wave before starting the next one
+		wg.Wait()
+
+		// Keep the highest score, preferring the first cell in row-major order on ties
+		for c := 0; c < chunks; c++ {
+			if isBetterCell(chunkBest[c], best) {
+				best = chunkBest[c]
+			}
+		}
+	}
+
+	// Perform traceback to reconstruct the alignment
+	alignedQuery, alignedRef, startRow, startCol := parallelTraceback(matrix, query, reference, best.row, best.col)
+
+	return AlignmentResult{
+		ScoreMatrix:   matrix,
+		MaxScore:      best.score,
+		MaxRow:        best.row,
+		MaxCol:        best.col,
+		AlignedQuery:  alignedQuery,
+		AlignedRef:    alignedRef,
+		QueryStart:    startRow,
+		QueryEnd:      best.row,
+		RefStart:      startCol,
+		RefEnd:        best.col,
+		NoAlignment:   best.score == 0,
+		ClippedPrefix: query[:startRow],
+		ClippedSuffix: query[best.row:],
+	}
+}
+
+// fillWaveChunk computes the cells of one wave for rows start..end (inclusive)
+// and returns the best cell among them.
+func fillWaveChunk(matrix [][]int, query, reference string, wave, start, end int) alignerCell {
+	best := alignerCell{}
+
+	for i := start; i <= end; i++ {
+		j := wave - i
+
+		// Determine if this is a match or mismatch
+		match := MismatchScore
+		if query[i-1] == reference[j-1] {
+			match = MatchScore
+		}
+
+		// Compute scores
+		scoreDiag := matrix[i-1][j-1] + match
+		scoreUp := matrix[i-1][j] + GapPenalty
+		scoreLeft := matrix[i][j-1] + GapPenalty
+
+		// Apply Smith-Waterman scoring rule (no negative scores)
+		matrix[i][j] = smithMax(0, scoreDiag, scoreUp, scoreLeft)
+
+		cell := alignerCell{score: matrix[i][j], row: i, col: j}
+		if isBetterCell(cell, best) {
+			best = cell
+		}
+	}
+
+	return best
+}
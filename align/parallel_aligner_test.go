@@ -0,0 +1,37 @@
+package align
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestParallelAligner checks that the pooled aligner matches the sequential algorithm
+func TestParallelAligner(t *testing.T) {
+	aligner := NewParallelAligner(4)
+	defer aligner.Close()
+
+	r := rand.New(rand.NewSource(42))
+	bases := "ACGT"
+	randomDNA := func(length int) string {
+		seq := make([]byte, length)
+		for i := range seq {
+			seq[i] = bases[r.Intn(len(bases))]
+		}
+		return string(seq)
+	}
+
+	// Reuse the same aligner for several alignments of different shapes
+	for _, lengths := range [][2]int{{30, 40}, {100, 100}, {120, 300}, {300, 80}} {
+		query, reference := randomDNA(lengths[0]), randomDNA(lengths[1])
+
+		seqResult := SmithWaterman(query, reference)
+		parResult := aligner.Align(query, reference)
+
+		if seqResult.MaxScore != parResult.MaxScore {
+			t.Errorf("Lengths %v: sequential score %d, pooled score %d", lengths, seqResult.MaxScore, parResult.MaxScore)
+		}
+		if seqResult.AlignedQuery != parResult.AlignedQuery || seqResult.AlignedRef != parResult.AlignedRef {
+			t.Errorf("Lengths %v: pooled alignment differs from sequential", lengths)
+		}
+	}
+}
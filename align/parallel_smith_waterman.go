@@ -1,19 +1,15 @@
 package align
 
 import (
+	"context"
 	"runtime"
 	"sync"
 )
 
-// ParallelAlignmentResult holds the alignment matrix and results for parallel execution.
-type ParallelAlignmentResult struct {
-	ScoreMatrix  [][]int // The Smith-Waterman dynamic programming matrix
-	MaxScore     int     // Maximum score in the matrix
-	MaxRow       int     // Row index of the maximum score
-	MaxCol       int     // Column index of the maximum score
-	AlignedQuery string  // The aligned query sequence
-	AlignedRef   string  // The aligned reference sequence
-}
+// ParallelAlignmentResult is the former result type of ParallelSmithWaterman.
+//
+// Deprecated: ParallelSmithWaterman now returns AlignmentResult; use it directly.
+type ParallelAlignmentResult = AlignmentResult
 
 // ParallelSmithWaterman performs local sequence alignment using the Smith-Waterman
 // algorithm with parallel matrix calculation using goroutines.
@@ -24,8 +20,8 @@ type ParallelAlignmentResult struct {
 //   - numWorkers (int): Number of goroutines to use (0 = use GOMAXPROCS)
 //
 // Returns:
-//   - (ParallelAlignmentResult): A struct containing the alignment matrix and results.
-func ParallelSmithWaterman(query, reference string, numWorkers int) ParallelAlignmentResult {
+//   - (AlignmentResult): A struct containing the alignment matrix and results.
+func ParallelSmithWaterman(query, reference string, numWorkers int) AlignmentResult {
 	m, n := len(query), len(reference)
 
 	// If the number of workers is not specified, use the number of CPUs
@@ -35,15 +31,7 @@ func ParallelSmithWaterman(query, reference string, numWorkers int) ParallelAlig
 
 	// For very small sequences, just use sequential algorithm
 	if m < 50 || n < 50 {
-		result := SmithWaterman(query, reference)
-		return ParallelAlignmentResult{
-			ScoreMatrix:  result.ScoreMatrix,
-			MaxScore:     result.MaxScore,
-			MaxRow:       0, // Not tracked in sequential version
-			MaxCol:       0, // Not tracked in sequential version
-			AlignedQuery: result.AlignedQuery,
-			AlignedRef:   result.AlignedRef,
-		}
+		return SmithWaterman(query, reference)
 	}
 
 	// Initialize score matrix
@@ -52,71 +40,64 @@ func ParallelSmithWaterman(query, reference string, numWorkers int) ParallelAlig
 		matrix[i] = make([]int, n+1)
 	}
 
-	// Shared variables for maximum score tracking (protected by mutex)
-	var mu sync.Mutex
-	maxScore := 0
-	maxRow, maxCol := 0, 0
-
-	// Calculate work chunks - divide matrix into blocks
-	// Using wave-front decomposition instead of block decomposition
-
-	// Use wait group to synchronize workers
+	best := alignerCell{}
+	chunkBest := make([]alignerCell, numWorkers)
 	var wg sync.WaitGroup
 
-	// Process the matrix in diagonal waves to handle dependencies
-	// Each cell (i,j) depends on (i-1,j-1), (i-1,j), and (i,j-1)
+	// Process the matrix in diagonal waves to handle dependencies.
+	// Each cell (i,j) depends on (i-1,j-1), (i-1,j), and (i,j-1), which all lie on
+	// earlier waves, so the cells of one wave can be computed in parallel as long as
+	// the previous wave is complete before it starts.
 	for wave := 2; wave <= m+n; wave++ {
-		wg.Add(1)
-		go func(waveFront int) {
-			defer wg.Done()
+		// Rows of this wave that fall inside the matrix
+		firstRow := max(1, wave-n)
+		lastRow := min(m, wave-1)
+		cells := lastRow - firstRow + 1
 
-			// Process all cells where i+j = waveFront
-			for i := 1; i <= m && i < waveFront; i++ {
-				j := waveFront - i
-				if j < 1 || j > n {
-					continue // Skip invalid coordinates
-				}
+		chunks := min(numWorkers, cells)
+		chunkSize := (cells + chunks - 1) / chunks
 
-				// Determine if this is a match or mismatch
-				match := MismatchScore
-				if query[i-1] == reference[j-1] {
-					match = MatchScore
-				}
+		for c := 0; c < chunks; c++ {
+			start := firstRow + c*chunkSize
+			end := min(start+chunkSize-1, lastRow)
 
-				// Compute scores
-				scoreDiag := matrix[i-1][j-1] + match
-				scoreUp := matrix[i-1][j] + GapPenalty
-				scoreLeft := matrix[i][j-1] + GapPenalty
-
-				// Apply Smith-Waterman scoring rule (no negative scores)
-				matrix[i][j] = smithMax(0, scoreDiag, scoreUp, scoreLeft)
-
-				// Track maximum score for traceback
-				if matrix[i][j] > 0 {
-					mu.Lock()
-					if matrix[i][j] > maxScore {
-						maxScore = matrix[i][j]
-						maxRow, maxCol = i, j
-					}
-					mu.Unlock()
-				}
+			wg.Add(1)
+			go func(c, start, end int) {
+				defer wg.Done()
+				chunkBest[c] = fillWaveChunk(matrix, query, reference, wave, start, end)
+			}(c, start, end)
+		}
+
+		// Join the wave before starting the next one
+		wg.Wait()
+
+		// Keep the highest score, preferring the first cell in row-major order on ties
+		for c := 0; c < chunks; c++ {
+			if isBetterCell(chunkBest[c], best) {
+				best = chunkBest[c]
 			}
-		}(wave)
+		}
 	}
 
-	// Wait for all diagonal waves to complete
-	wg.Wait()
+	maxScore, maxRow, maxCol := best.score, best.row, best.col
 
 	// Perform traceback to reconstruct the alignment
-	alignedQuery, alignedRef := parallelTraceback(matrix, query, reference, maxRow, maxCol)
-
-	return ParallelAlignmentResult{
-		ScoreMatrix:  matrix,
-		MaxScore:     maxScore,
-		MaxRow:       maxRow,
-		MaxCol:       maxCol,
-		AlignedQuery: alignedQuery,
-		AlignedRef:   alignedRef,
+	alignedQuery, alignedRef, startRow, startCol := parallelTraceback(matrix, query, reference, maxRow, maxCol)
+
+	return AlignmentResult{
+		ScoreMatrix:   matrix,
+		MaxScore:      maxScore,
+		MaxRow:        maxRow,
+		MaxCol:        maxCol,
+		AlignedQuery:  alignedQuery,
+		AlignedRef:    alignedRef,
+		QueryStart:    startRow,
+		QueryEnd:      maxRow,
+		RefStart:      startCol,
+		RefEnd:        maxCol,
+		NoAlignment:   maxScore == 0,
+		ClippedPrefix: query[:startRow],
+		ClippedSuffix: query[maxRow:],
 	}
 }
 
@@ -132,9 +113,12 @@ func ParallelSmithWaterman(query, reference string, numWorkers int) ParallelAlig
 //   - col (int): The column index of the highest score.
 //
 // Returns:
-//   - (string, string): The aligned query and reference sequences.
-func parallelTraceback(matrix [][]int, query, reference string, row, col int) (string, string) {
-	var alignedQuery, alignedRef string
+//   - (string, string, int, int): The aligned query and reference sequences, followed by
+//     the 0-based start of the alignment in the query and reference.
+func parallelTraceback(matrix [][]int, query, reference string, row, col int) (string, string, int, int) {
+	// Build the alignment back to front and reverse it once at the end
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(row, col))
+	alignedRef := make([]byte, 0, MaxAlignmentLength(row, col))
 
 	// Perform traceback from the highest scoring cell
 	for row > 0 && col > 0 && matrix[row][col] > 0 {
@@ -148,19 +132,19 @@ func parallelTraceback(matrix [][]int, query, reference string, row, col int) (s
 
 		// Check diagonal move (match/mismatch)
 		if currentScore == matrix[row-1][col-1]+match {
-			alignedQuery = string(query[row-1]) + alignedQuery
-			alignedRef = string(reference[col-1]) + alignedRef
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, reference[col-1])
 			row--
 			col--
 		} else if currentScore == matrix[row-1][col]+GapPenalty {
 			// Gap in reference
-			alignedQuery = string(query[row-1]) + alignedQuery
-			alignedRef = "-" + alignedRef
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
 			row--
 		} else if currentScore == matrix[row][col-1]+GapPenalty {
 			// Gap in query
-			alignedQuery = "-" + alignedQuery
-			alignedRef = string(reference[col-1]) + alignedRef
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
 			col--
 		} else {
 			// This shouldn't happen with correct scoring, but break as a safeguard
@@ -168,7 +152,10 @@ func parallelTraceback(matrix [][]int, query, reference string, row, col int) (s
 		}
 	}
 
-	return alignedQuery, alignedRef
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return string(alignedQuery), string(alignedRef), row, col
 }
 
 // ConcurrentSmithWatermanBatch processes multiple sequence alignments concurrently.
@@ -182,6 +169,78 @@ func parallelTraceback(matrix [][]int, query, reference string, row, col int) (s
 // Returns:
 //   - ([]AlignmentResult): Array of alignment results, one per reference.
 func ConcurrentSmithWatermanBatch(query string, references []string, numWorkers int) []AlignmentResult {
+	return ConcurrentSmithWatermanBatchWithProgress(query, references, numWorkers, nil)
+}
+
+// ConcurrentSmithWatermanBatchReuse behaves like ConcurrentSmithWatermanBatch, but
+// each worker fills one reused score matrix (see Aligner) instead of allocating a
+// new one per reference. Use it when only the scores and aligned sequences are
+// needed; for a batch of similar-length references it allocates far less.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - references ([]string): An array of reference DNA sequences.
+//   - numWorkers (int): Maximum number of concurrent alignments (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - ([]AlignmentResult): Array of alignment results, one per reference. ScoreMatrix
+//     is nil, since each worker's matrix is overwritten by its next alignment.
+func ConcurrentSmithWatermanBatchReuse(query string, references []string, numWorkers int) []AlignmentResult {
+	return concurrentSmithWatermanBatch(query, references, numWorkers, true, nil)
+}
+
+// ConcurrentSmithWatermanBatchWithProgress behaves like ConcurrentSmithWatermanBatch
+// but reports progress each time an alignment in the batch completes.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - references ([]string): An array of reference DNA sequences.
+//   - numWorkers (int): Maximum number of concurrent alignments (0 = use GOMAXPROCS).
+//   - progress (ProgressFunc): Called with (referencesDone, totalReferences); may be nil.
+//     Calls are serialized, so the callback does not need its own locking.
+//
+// Returns:
+//   - ([]AlignmentResult): Array of alignment results, one per reference.
+func ConcurrentSmithWatermanBatchWithProgress(query string, references []string, numWorkers int, progress ProgressFunc) []AlignmentResult {
+	return concurrentSmithWatermanBatch(query, references, numWorkers, false, progress)
+}
+
+// concurrentSmithWatermanBatch collects a concurrent batch into a slice in input
+// order, reusing each worker's score matrix when reuse is set.
+func concurrentSmithWatermanBatch(query string, references []string, numWorkers int, reuse bool, progress ProgressFunc) []AlignmentResult {
+	results := make([]AlignmentResult, len(references))
+	done := 0
+
+	concurrentSmithWatermanBatchFunc(query, references, numWorkers, reuse, func(index int, result AlignmentResult) {
+		results[index] = result
+
+		if progress != nil {
+			done++
+			progress(done, len(references))
+		}
+	})
+
+	return results
+}
+
+// ConcurrentSmithWatermanBatchFunc aligns a query against multiple references
+// concurrently and hands each result to fn as soon as it completes, instead of
+// collecting the whole batch. Results arrive in completion order, so fn receives
+// the index of the reference each result belongs to.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - references ([]string): An array of reference DNA sequences.
+//   - numWorkers (int): Maximum number of concurrent alignments (0 = use GOMAXPROCS).
+//   - fn (func(int, AlignmentResult)): Called once per reference with its index and result.
+//     Calls are serialized, so the callback does not need its own locking.
+func ConcurrentSmithWatermanBatchFunc(query string, references []string, numWorkers int, fn func(index int, result AlignmentResult)) {
+	concurrentSmithWatermanBatchFunc(query, references, numWorkers, false, fn)
+}
+
+// concurrentSmithWatermanBatchFunc runs ConcurrentSmithWatermanBatchFunc. When reuse
+// is set, each worker aligns with its own Aligner and the results carry no ScoreMatrix.
+func concurrentSmithWatermanBatchFunc(query string, references []string, numWorkers int, reuse bool, fn func(index int, result AlignmentResult)) {
 	// If the number of workers is not specified, use the number of CPUs
 	if numWorkers <= 0 {
 		numWorkers = runtime.GOMAXPROCS(0)
@@ -192,28 +251,154 @@ func ConcurrentSmithWatermanBatch(query string, references []string, numWorkers
 		numWorkers = len(references)
 	}
 
-	// Create a channel for results and a semaphore channel to limit concurrency
-	results := make([]AlignmentResult, len(references))
-	semaphore := make(chan struct{}, numWorkers)
+	// Feed reference indices to a fixed pool of workers, so only numWorkers
+	// goroutines exist however large the batch is
+	jobs := make(chan int)
 	var wg sync.WaitGroup
 
-	// Process each reference sequence
-	for i, ref := range references {
-		wg.Add(1)
-		semaphore <- struct{}{} // Acquire semaphore
+	// Serialize callbacks across workers
+	var fnMu sync.Mutex
 
-		go func(index int, reference string) {
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
 			defer wg.Done()
-			defer func() { <-semaphore }() // Release semaphore
 
-			// Run the standard Smith-Waterman algorithm
-			results[index] = SmithWaterman(query, reference)
-		}(i, ref)
+			// With reuse, each worker fills one matrix for all of its alignments
+			aligner := NewAligner()
+			for index := range jobs {
+				var result AlignmentResult
+				if reuse {
+					result = aligner.Align(query, references[index])
+				} else {
+					result = SmithWaterman(query, references[index])
+				}
+
+				fnMu.Lock()
+				fn(index, result)
+				fnMu.Unlock()
+			}
+		}()
+	}
+
+	for i := range references {
+		jobs <- i
 	}
+	close(jobs)
 
 	// Wait for all alignments to complete
 	wg.Wait()
-	close(semaphore)
+}
+
+// IndexedResult pairs an alignment result with the position of its reference in the input.
+type IndexedResult struct {
+	Index int // Position of the reference in the input stream (0-based)
+	AlignmentResult
+}
+
+// SmithWatermanBatchStream aligns a query against references read from a channel
+// using a fixed pool of worker goroutines, and emits each result as soon as it
+// completes. Unlike ConcurrentSmithWatermanBatch it never holds the whole batch, so
+// memory stays bounded by the number of workers however many references are streamed.
+//
+// Results arrive in completion order; Index records the order the references were
+// read in, so callers that need input order can restore it. The caller must receive
+// every result until the channel is closed: the output is buffered only to
+// numWorkers, so a consumer that stops reading leaves the workers blocked forever.
+// Use SmithWatermanBatchStreamContext to be able to stop early.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - refs (<-chan string): The reference sequences. The stream ends once refs is
+//     closed and every reference read from it has been aligned.
+//   - numWorkers (int): Number of worker goroutines (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - (<-chan IndexedResult): One result per reference, closed after the last one.
+func SmithWatermanBatchStream(query string, refs <-chan string, numWorkers int) <-chan IndexedResult {
+	return SmithWatermanBatchStreamContext(context.Background(), query, refs, numWorkers)
+}
+
+// SmithWatermanBatchStreamContext behaves like SmithWatermanBatchStream but stops
+// when ctx is cancelled: no further references are read from refs, alignments in
+// progress are abandoned, and the output channel is closed once the workers have
+// exited. The caller may then stop receiving without leaking goroutines.
+//
+// Parameters:
+//   - ctx (context.Context): Cancels the stream when done.
+//   - query (string): The DNA query sequence.
+//   - refs (<-chan string): The reference sequences. The stream ends once refs is
+//     closed and every reference read from it has been aligned, or ctx is cancelled.
+//   - numWorkers (int): Number of worker goroutines (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - (<-chan IndexedResult): One result per aligned reference, closed after the last
+//     one. After cancellation some references may have no result.
+func SmithWatermanBatchStreamContext(ctx context.Context, query string, refs <-chan string, numWorkers int) <-chan IndexedResult {
+	// If the number of workers is not specified, use the number of CPUs
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	type job struct {
+		index     int
+		reference string
+	}
+
+	jobs := make(chan job)
+	results := make(chan IndexedResult, numWorkers)
+
+	// Number the references in the order they arrive
+	go func() {
+		defer close(jobs)
+		index := 0
+		for {
+			var ref string
+			var ok bool
+			select {
+			case ref, ok = <-refs:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case jobs <- job{index: index, reference: ref}:
+				index++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Start the worker pool
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result, err := SmithWatermanContext(ctx, query, j.reference)
+				if err != nil {
+					return
+				}
+
+				select {
+				case results <- IndexedResult{Index: j.index, AlignmentResult: result}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Close the output once every worker has finished
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
 	return results
 }
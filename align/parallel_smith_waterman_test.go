@@ -0,0 +1,307 @@
+package align
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParallelSmithWatermanLarge checks that the wave-front fill matches the sequential
+// result on 2000bp inputs. Run with -race to confirm that waves do not overlap.
+func TestParallelSmithWatermanLarge(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	bases := "ACGT"
+	query := make([]byte, 2000)
+	for i := range query {
+		query[i] = bases[r.Intn(len(bases))]
+	}
+
+	// Mutate about one base in ten so the reference is related but not identical
+	reference := append([]byte(nil), query...)
+	for i := range reference {
+		if r.Intn(10) == 0 {
+			reference[i] = bases[r.Intn(len(bases))]
+		}
+	}
+
+	seqResult := SmithWaterman(string(query), string(reference))
+
+	for _, workers := range []int{1, 4, 16} {
+		parResult := ParallelSmithWaterman(string(query), string(reference), workers)
+
+		if parResult.MaxScore != seqResult.MaxScore {
+			t.Errorf("Workers=%d: score = %d, want %d", workers, parResult.MaxScore, seqResult.MaxScore)
+		}
+		if parResult.MaxRow != seqResult.QueryEnd || parResult.MaxCol != seqResult.RefEnd {
+			t.Errorf("Workers=%d: maximum at (%d,%d), want (%d,%d)",
+				workers, parResult.MaxRow, parResult.MaxCol, seqResult.QueryEnd, seqResult.RefEnd)
+		}
+	}
+}
+
+// TestMaxScoreTieBreak checks that every aligner picks the same cell when two share
+// the maximum score: the first in row-major order
+func TestMaxScoreTieBreak(t *testing.T) {
+	// The reference occurs twice in the query, so rows 30 and 90 both reach the maximum
+	repeat := "ATGGCCTACGATCGTAGCTAGGCTACGATC"
+	query := repeat + strings.Repeat("T", 30) + repeat
+	reference := strings.Repeat("G", 10) + repeat + strings.Repeat("G", 20)
+
+	want := SmithWaterman(query, reference)
+	if want.QueryEnd != 30 || want.RefEnd != 40 {
+		t.Fatalf("Expected the sequential maximum at (30,40), got (%d,%d)", want.QueryEnd, want.RefEnd)
+	}
+
+	aligner := NewParallelAligner(4)
+	defer aligner.Close()
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		parallel := ParallelSmithWaterman(query, reference, workers)
+		if parallel.MaxRow != want.QueryEnd || parallel.MaxCol != want.RefEnd || parallel.AlignedQuery != want.AlignedQuery {
+			t.Errorf("ParallelSmithWaterman with %d workers ended at (%d,%d), want (%d,%d)",
+				workers, parallel.MaxRow, parallel.MaxCol, want.QueryEnd, want.RefEnd)
+		}
+
+		tiled := TiledParallelSmithWaterman(query, reference, 16, workers)
+		if tiled.QueryEnd != want.QueryEnd || tiled.RefEnd != want.RefEnd {
+			t.Errorf("TiledParallelSmithWaterman with %d workers ended at (%d,%d), want (%d,%d)",
+				workers, tiled.QueryEnd, tiled.RefEnd, want.QueryEnd, want.RefEnd)
+		}
+	}
+
+	pooled := aligner.Align(query, reference)
+	if pooled.MaxRow != want.QueryEnd || pooled.MaxCol != want.RefEnd {
+		t.Errorf("ParallelAligner ended at (%d,%d), want (%d,%d)", pooled.MaxRow, pooled.MaxCol, want.QueryEnd, want.RefEnd)
+	}
+
+	linear := SmithWatermanLinear(query, reference)
+	if linear.QueryEnd != want.QueryEnd || linear.RefEnd != want.RefEnd {
+		t.Errorf("SmithWatermanLinear ended at (%d,%d), want (%d,%d)", linear.QueryEnd, linear.RefEnd, want.QueryEnd, want.RefEnd)
+	}
+
+	// The linear aligner swaps its inputs when the reference is longer
+	swapped := SmithWatermanLinear(reference, query)
+	if wantSwapped := SmithWaterman(reference, query); swapped.QueryEnd != wantSwapped.QueryEnd || swapped.RefEnd != wantSwapped.RefEnd {
+		t.Errorf("SmithWatermanLinear on swapped inputs ended at (%d,%d), want (%d,%d)",
+			swapped.QueryEnd, swapped.RefEnd, wantSwapped.QueryEnd, wantSwapped.RefEnd)
+	}
+
+	// Inputs small enough for the sequential fallback report the same maximum cell
+	small := ParallelSmithWaterman("GATTACA", "GATTACA", 4)
+	if small.MaxRow != 7 || small.MaxCol != 7 {
+		t.Errorf("Expected the small-input path to report (7,7), got (%d,%d)", small.MaxRow, small.MaxCol)
+	}
+}
+
+// TestParallelSmithWatermanClipping checks that the parallel result fills in the
+// soft-clipped query bases the same way the sequential result does
+func TestParallelSmithWatermanClipping(t *testing.T) {
+	query := strings.Repeat("T", 40) + "GATTACAGATTACA" + strings.Repeat("T", 40)
+	reference := strings.Repeat("C", 60) + "GATTACAGATTACA" + strings.Repeat("C", 60)
+
+	aligner := NewParallelAligner(2)
+	defer aligner.Close()
+
+	want := SmithWaterman(query, reference)
+	for _, got := range []AlignmentResult{ParallelSmithWaterman(query, reference, 4), aligner.Align(query, reference)} {
+		if got.ClippedPrefix != want.ClippedPrefix || got.ClippedSuffix != want.ClippedSuffix {
+			t.Errorf("Expected clipped %q/%q, got %q/%q", want.ClippedPrefix, want.ClippedSuffix, got.ClippedPrefix, got.ClippedSuffix)
+		}
+		if err := VerifyAlignment(got, query, reference); err != nil {
+			t.Errorf("Parallel result failed verification: %v", err)
+		}
+	}
+}
+
+// TestSmithWatermanBatchStream checks that every streamed reference yields one result with its input index
+func TestSmithWatermanBatchStream(t *testing.T) {
+	query := "GATTACAGATTACA"
+	references := []string{"GATTACA", "TTTTTTT", "GATTTACAGATACA", "CCCGATTACAGGG", "ACGT"}
+
+	refs := make(chan string)
+	go func() {
+		for _, ref := range references {
+			refs <- ref
+		}
+		close(refs)
+	}()
+
+	seen := make([]bool, len(references))
+	for result := range SmithWatermanBatchStream(query, refs, 3) {
+		if result.Index < 0 || result.Index >= len(references) || seen[result.Index] {
+			t.Fatalf("Unexpected or repeated index %d", result.Index)
+		}
+		seen[result.Index] = true
+
+		want := SmithWaterman(query, references[result.Index])
+		if result.MaxScore != want.MaxScore || result.AlignedQuery != want.AlignedQuery {
+			t.Errorf("Reference %d: got score %d, want %d", result.Index, result.MaxScore, want.MaxScore)
+		}
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("No result for reference %d", i)
+		}
+	}
+
+	// An empty stream closes the output without results
+	empty := make(chan string)
+	close(empty)
+	if _, ok := <-SmithWatermanBatchStream(query, empty, 0); ok {
+		t.Error("Expected no results for an empty stream")
+	}
+}
+
+// TestSmithWatermanBatchStreamContext checks that cancelling the context closes the
+// output even though the references never end and the consumer stops reading
+func TestSmithWatermanBatchStreamContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// An endless reference stream, as from a client that keeps sending
+	refs := make(chan string)
+	go func() {
+		for {
+			select {
+			case refs <- "GATTACA":
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := SmithWatermanBatchStreamContext(ctx, "GATTACA", refs, 2)
+	if _, ok := <-results; !ok {
+		t.Fatal("Expected a result before cancellation")
+	}
+	cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Output channel was not closed after cancellation")
+	}
+}
+
+// TestParallelSmithWatermanAsymmetric checks the wave-front bounds on lopsided inputs,
+// where most waves are clipped by one sequence and not the other
+func TestParallelSmithWatermanAsymmetric(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	randomDNA := func(n int) string {
+		seq := make([]byte, n)
+		for i := range seq {
+			seq[i] = "ACGT"[r.Intn(4)]
+		}
+		return string(seq)
+	}
+
+	short := randomDNA(60)
+	long := randomDNA(1500) + short + randomDNA(1440)
+
+	aligner := NewParallelAligner(4)
+	defer aligner.Close()
+
+	pairs := []struct{ name, query, reference string }{
+		{"short query", short, long},
+		{"short reference", long, short},
+		{"one row over the fallback", randomDNA(50), randomDNA(3000)},
+		{"one column over the fallback", randomDNA(3000), randomDNA(50)},
+	}
+
+	for _, pair := range pairs {
+		want := SmithWaterman(pair.query, pair.reference)
+
+		for _, workers := range []int{1, 3, 8} {
+			got := ParallelSmithWaterman(pair.query, pair.reference, workers)
+			if got.MaxScore != want.MaxScore || got.MaxRow != want.QueryEnd || got.MaxCol != want.RefEnd {
+				t.Errorf("%s, %d workers: score %d at (%d,%d), want %d at (%d,%d)", pair.name, workers,
+					got.MaxScore, got.MaxRow, got.MaxCol, want.MaxScore, want.QueryEnd, want.RefEnd)
+			}
+			if got.AlignedQuery != want.AlignedQuery || got.AlignedRef != want.AlignedRef {
+				t.Errorf("%s, %d workers: alignment differs from the sequential one", pair.name, workers)
+			}
+		}
+
+		if pooled := aligner.Align(pair.query, pair.reference); pooled.MaxScore != want.MaxScore {
+			t.Errorf("%s: ParallelAligner score = %d, want %d", pair.name, pooled.MaxScore, want.MaxScore)
+		}
+		if tiled := TiledParallelSmithWaterman(pair.query, pair.reference, 64, 4); tiled.MaxScore != want.MaxScore {
+			t.Errorf("%s: TiledParallelSmithWaterman score = %d, want %d", pair.name, tiled.MaxScore, want.MaxScore)
+		}
+	}
+}
+
+// TestConcurrentSmithWatermanBatchGoroutines checks that a large batch runs on a fixed
+// pool: the number of live goroutines never exceeds the workers plus those already running
+func TestConcurrentSmithWatermanBatchGoroutines(t *testing.T) {
+	const workers = 4
+	references := make([]string, 20000)
+	for i := range references {
+		references[i] = "GATTACA"
+	}
+
+	baseline := runtime.NumGoroutine()
+	peak, calls := 0, 0
+	ConcurrentSmithWatermanBatchFunc("GATTACA", references, workers, func(index int, result AlignmentResult) {
+		peak = max(peak, runtime.NumGoroutine())
+		calls++
+	})
+
+	if calls != len(references) {
+		t.Errorf("Expected %d results, got %d", len(references), calls)
+	}
+	if peak > baseline+workers {
+		t.Errorf("Peak of %d goroutines, want at most %d (baseline %d + %d workers)", peak, baseline+workers, baseline, workers)
+	}
+}
+
+// TestConcurrentSmithWatermanBatchReuse checks that the plain batch keeps each score
+// matrix while the reusing batch drops it, and that both give the same alignments
+func TestConcurrentSmithWatermanBatchReuse(t *testing.T) {
+	query := "GATTACA"
+	references := []string{"GATCACA", "TTGATTACATT", "CCCC", "GATTA"}
+
+	plain := ConcurrentSmithWatermanBatch(query, references, 2)
+	reused := ConcurrentSmithWatermanBatchReuse(query, references, 2)
+
+	for i := range references {
+		if plain[i].ScoreMatrix == nil {
+			t.Errorf("Reference %d: expected ConcurrentSmithWatermanBatch to keep the ScoreMatrix", i)
+		}
+		if reused[i].ScoreMatrix != nil {
+			t.Errorf("Reference %d: expected no ScoreMatrix from ConcurrentSmithWatermanBatchReuse", i)
+		}
+		if reused[i].MaxScore != plain[i].MaxScore || reused[i].AlignedQuery != plain[i].AlignedQuery ||
+			reused[i].AlignedRef != plain[i].AlignedRef {
+			t.Errorf("Reference %d: reused alignment %+v differs from %+v", i, reused[i], plain[i])
+		}
+	}
+}
+
+// TestParallelSmithWatermanStable checks that repeated parallel runs on input with many
+// tied maxima return the same alignment every time, matching the sequential one
+func TestParallelSmithWatermanStable(t *testing.T) {
+	// Every copy of the motif in the query scores the same against the reference
+	motif := "ACGTTGCA"
+	query := strings.Repeat(motif+"TT", 30)
+	reference := strings.Repeat("G", 40) + motif + strings.Repeat("G", 40)
+
+	want := SmithWaterman(query, reference)
+	for run := 0; run < 20; run++ {
+		got := ParallelSmithWaterman(query, reference, 8)
+		if got.AlignedQuery != want.AlignedQuery || got.AlignedRef != want.AlignedRef ||
+			got.MaxRow != want.QueryEnd || got.MaxCol != want.RefEnd {
+			t.Fatalf("Run %d ended at (%d,%d), want (%d,%d)", run, got.MaxRow, got.MaxCol, want.QueryEnd, want.RefEnd)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package align
+
+// Version is the version of the alignment code recorded in provenance metadata.
+const Version = "0.1.0"
+
+// Algorithm names recorded in provenance metadata
+const (
+	AlgorithmSmithWaterman         = "smith-waterman"
+	AlgorithmParallelSmithWaterman = "parallel-smith-waterman"
+)
+
+// Provenance describes how an alignment was produced, so that serialized or
+// cached results remain traceable to the algorithm and scores behind them.
+type Provenance struct {
+	Algorithm string        `json:"algorithm"` // The aligner that produced the result
+	Scheme    ScoringScheme `json:"scheme"`    // The scores used during alignment
+	Version   string        `json:"version"`   // The package version that produced the result
+}
+
+// NewProvenance returns provenance for the given algorithm and scoring scheme,
+// stamped with the current package version.
+//
+// Parameters:
+//   - algorithm (string): The algorithm name, such as AlgorithmSmithWaterman.
+//   - scheme (ScoringScheme): The scoring scheme used.
+//
+// Returns:
+//   - (*Provenance): The provenance record.
+func NewProvenance(algorithm string, scheme ScoringScheme) *Provenance {
+	return &Provenance{Algorithm: algorithm, Scheme: scheme, Version: Version}
+}
+
+// WithProvenance returns a copy of the result carrying provenance metadata.
+// The aligners leave Provenance nil; callers attach it when they want
+// self-describing results.
+//
+// Parameters:
+//   - algorithm (string): The algorithm name, such as AlgorithmSmithWaterman.
+//   - scheme (ScoringScheme): The scoring scheme used.
+//
+// Returns:
+//   - (AlignmentResult): The result with Provenance set.
+func (r AlignmentResult) WithProvenance(algorithm string, scheme ScoringScheme) AlignmentResult {
+	r.Provenance = NewProvenance(algorithm, scheme)
+	return r
+}
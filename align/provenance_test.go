@@ -0,0 +1,44 @@
+package align
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestProvenanceJSONRoundTrip checks that provenance survives serialization of a result
+func TestProvenanceJSONRoundTrip(t *testing.T) {
+	result := SmithWaterman("GATTACA", "GATTACA").WithProvenance(AlgorithmSmithWaterman, DefaultScoring)
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Could not encode result: %v", err)
+	}
+
+	var decoded AlignmentResult
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Could not decode result: %v", err)
+	}
+
+	if decoded.Provenance == nil {
+		t.Fatal("Provenance was lost in the round trip")
+	}
+	if *decoded.Provenance != *result.Provenance {
+		t.Errorf("Provenance changed in the round trip: got %+v, expected %+v", *decoded.Provenance, *result.Provenance)
+	}
+	if decoded.Provenance.Version != Version || decoded.Provenance.Scheme != DefaultScoring {
+		t.Errorf("Unexpected provenance %+v", *decoded.Provenance)
+	}
+
+	// Results without provenance omit the field entirely
+	plain, err := json.Marshal(SmithWaterman("GATTACA", "GATTACA"))
+	if err != nil {
+		t.Fatalf("Could not encode result: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(plain, &fields); err != nil {
+		t.Fatalf("Could not decode result: %v", err)
+	}
+	if _, ok := fields["provenance"]; ok {
+		t.Error("Expected no provenance field when none was attached")
+	}
+}
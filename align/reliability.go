@@ -0,0 +1,120 @@
+package align
+
+import "math"
+
+// reliabilityScale converts alignment scores into path weights for AlignmentReliability:
+// a local alignment with score s has weight exp(reliabilityScale * s). Larger values
+// concentrate the weight on the optimal path; smaller ones spread it to near-optimal ones.
+const reliabilityScale = 1.0
+
+// AlignmentReliability estimates how confident each column of the optimal local
+// alignment is. Every local alignment of the two sequences is weighted by the
+// exponential of its score, and a column's confidence is the share of the total
+// weight carried by alignments that contain the same column: the same pair of bases,
+// or the same base against a gap at the same place. This is the posterior probability
+// of the column under a simple probabilistic Smith-Waterman model, computed with a
+// forward and a backward pass over the matrix.
+//
+// A column that every high-scoring alignment agrees on scores close to 1. Columns
+// where near-optimal alignments disagree, such as a gap that could sit anywhere in
+// a homopolymer or a read that fits several copies of a repeat, score low.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - ([]float64): One confidence between 0 and 1 per column of the alignment
+//     SmithWaterman returns; empty if there is no alignment.
+func AlignmentReliability(query, reference string) []float64 {
+	result := SmithWaterman(query, reference)
+	if result.NoAlignment {
+		return []float64{}
+	}
+
+	m, n := len(query), len(reference)
+	sc := defaultScorer()
+	gap := reliabilityScale * float64(sc.gap)
+	match := func(i, j int) float64 {
+		return reliabilityScale * float64(sc.score(query, reference, i-1, j-1))
+	}
+
+	// forward[i][j] is the log total weight of alignments ending at cell (i,j);
+	// backward[i][j] is the log total weight of the ways to continue from (i,j),
+	// including stopping there. Both are computed in log space to avoid overflow.
+	forward := logMatrix(m, n)
+	backward := logMatrix(m, n)
+
+	logZ := 0.0 // The empty alignment has weight 1
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			// A match either starts a new alignment or extends one ending diagonally
+			forward[i][j] = logAddExp(
+				match(i, j)+logAddExp(0, forward[i-1][j-1]),
+				logAddExp(gap+forward[i-1][j], gap+forward[i][j-1]),
+			)
+			logZ = logAddExp(logZ, forward[i][j])
+		}
+	}
+
+	for i := m; i >= 0; i-- {
+		for j := n; j >= 0; j-- {
+			total := 0.0 // Stopping here
+			if i < m && j < n {
+				total = logAddExp(total, match(i+1, j+1)+backward[i+1][j+1])
+			}
+			if i < m {
+				total = logAddExp(total, gap+backward[i+1][j])
+			}
+			if j < n {
+				total = logAddExp(total, gap+backward[i][j+1])
+			}
+			backward[i][j] = total
+		}
+	}
+
+	// Walk the optimal alignment, scoring the move that produced each column
+	confidence := make([]float64, len(result.AlignedQuery))
+	row, col := result.QueryStart, result.RefStart
+	for k := range confidence {
+		var logWeight float64
+		switch {
+		case result.AlignedRef[k] == '-':
+			logWeight = gap + forward[row][col]
+			row++
+		case result.AlignedQuery[k] == '-':
+			logWeight = gap + forward[row][col]
+			col++
+		default:
+			logWeight = match(row+1, col+1) + logAddExp(0, forward[row][col])
+			row++
+			col++
+		}
+		confidence[k] = math.Min(1, math.Exp(logWeight+backward[row][col]-logZ))
+	}
+
+	return confidence
+}
+
+// logMatrix returns an (m+1) x (n+1) matrix filled with log(0).
+func logMatrix(m, n int) [][]float64 {
+	matrix := make([][]float64, m+1)
+	for i := range matrix {
+		matrix[i] = make([]float64, n+1)
+		for j := range matrix[i] {
+			matrix[i][j] = math.Inf(-1)
+		}
+	}
+	return matrix
+}
+
+// logAddExp returns log(exp(a) + exp(b)) without overflowing.
+func logAddExp(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
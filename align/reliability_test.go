@@ -0,0 +1,50 @@
+package align
+
+import "testing"
+
+// TestAlignmentReliability checks that a unique match is confident and a gap in a homopolymer is not
+func TestAlignmentReliability(t *testing.T) {
+	// The query lacks one T of the reference's seven-T run, and the deletion could be
+	// placed at any of seven positions, all with the same score
+	left := "GCATCGGACTAGCCGTACGA"
+	right := "CGATGCCAGTCAGGCTAGCA"
+	query := left + "TTTTTT" + right
+	reference := left + "TTTTTTT" + right
+
+	result := SmithWaterman(query, reference)
+	confidence := AlignmentReliability(query, reference)
+	if len(confidence) != len(result.AlignedQuery) {
+		t.Fatalf("Expected %d columns, got %d", len(result.AlignedQuery), len(confidence))
+	}
+
+	// The middle of the unique flank is determined by every high-scoring alignment
+	for k := 5; k < 15; k++ {
+		if confidence[k] < 0.9 {
+			t.Errorf("Column %d in the unique flank has confidence %.3f, want at least 0.9", k, confidence[k])
+		}
+	}
+
+	// The deletion column itself is one of seven equally good placements
+	gapColumn := -1
+	for k := range result.AlignedQuery {
+		if result.AlignedQuery[k] == '-' {
+			gapColumn = k
+		}
+	}
+	if gapColumn < 0 {
+		t.Fatalf("Expected a deletion in the alignment %s / %s", result.AlignedQuery, result.AlignedRef)
+	}
+	if confidence[gapColumn] > 0.5 {
+		t.Errorf("Deletion in the homopolymer has confidence %.3f, want at most 0.5", confidence[gapColumn])
+	}
+
+	for k, c := range confidence {
+		if c < 0 || c > 1 {
+			t.Errorf("Column %d has confidence %f outside [0, 1]", k, c)
+		}
+	}
+
+	if got := AlignmentReliability("AAAA", "CCCC"); len(got) != 0 {
+		t.Errorf("Expected no columns without an alignment, got %v", got)
+	}
+}
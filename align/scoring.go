@@ -0,0 +1,115 @@
+package align
+
+// ScoringScheme holds the scores of a linear-gap alignment.
+type ScoringScheme struct {
+	Match    int `json:"match"`    // Score for identical bases
+	Mismatch int `json:"mismatch"` // Score for different bases
+	Gap      int `json:"gap"`      // Score for each gap column
+}
+
+// DefaultScoring is the scheme used by SmithWaterman and the other aligners
+// that do not take a scheme explicitly.
+var DefaultScoring = ScoringScheme{Match: MatchScore, Mismatch: MismatchScore, Gap: GapPenalty}
+
+// ScoreFromCounts computes the score an alignment should have from its column
+// counts alone. Because a linear-gap score is a sum over columns, this gives an
+// independent check of the dynamic programming: the MaxScore of a local alignment
+// must equal ScoreFromCounts applied to the columns of its own aligned strings.
+//
+// Parameters:
+//   - matches (int): The number of columns with identical bases.
+//   - mismatches (int): The number of columns with different bases.
+//   - gaps (int): The number of columns with a gap in either sequence.
+//   - scheme (ScoringScheme): The scores to apply.
+//
+// Returns:
+//   - (int): The alignment score.
+func ScoreFromCounts(matches, mismatches, gaps int, scheme ScoringScheme) int {
+	return matches*scheme.Match + mismatches*scheme.Mismatch + gaps*scheme.Gap
+}
+
+// scorer supplies the substitution and gap scores used to fill and trace the
+// score matrix. When substitute is nil, identical bases score match and
+// different bases score mismatch.
+type scorer struct {
+	match      int                // Score for identical bases
+	mismatch   int                // Score for different bases
+	gap        int                // Score for a gap column
+	substitute func(i, j int) int // Optional score for aligning query[i] with reference[j]
+}
+
+// defaultScorer returns a scorer using the package scoring constants.
+func defaultScorer() scorer {
+	return scorer{match: MatchScore, mismatch: MismatchScore, gap: GapPenalty}
+}
+
+// scorer returns a scorer applying the scheme's scores.
+func (s ScoringScheme) scorer() scorer {
+	return scorer{match: s.Match, mismatch: s.Mismatch, gap: s.Gap}
+}
+
+// score returns the substitution score for aligning query[i] with reference[j].
+func (s scorer) score(query, reference string, i, j int) int {
+	if s.substitute != nil {
+		return s.substitute(i, j)
+	}
+	if query[i] == reference[j] {
+		return s.match
+	}
+	return s.mismatch
+}
+
+// SmithWatermanWithMatcher performs local alignment with a caller-supplied
+// substitution score. This is the most flexible scoring hook: the callback
+// decides the score of every base pair, so it can implement collapsed alphabets,
+// ambiguity codes, or any other rule. Gaps still cost GapPenalty.
+//
+// Parameters:
+//   - query (string): The query sequence.
+//   - reference (string): The reference sequence.
+//   - match (func(a, b byte) int): Returns the score for aligning query base a with reference base b.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
+func SmithWatermanWithMatcher(query, reference string, match func(a, b byte) int) AlignmentResult {
+	sc := defaultScorer()
+	sc.substitute = func(i, j int) int {
+		return match(query[i], reference[j])
+	}
+
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: sc})
+	return result
+}
+
+// SmithWatermanWithWildcards performs local alignment against a reference with
+// known variant sites. Each listed reference position matches any query base, so
+// known polymorphisms do not lower the score of reads carrying the alternate allele.
+// Gaps are scored as usual.
+//
+// Parameters:
+//   - query (string): The query sequence.
+//   - reference (string): The reference sequence.
+//   - wildcardPositions ([]int): 0-based reference positions that score as a match
+//     against any query base. Positions outside the reference are ignored.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
+func SmithWatermanWithWildcards(query, reference string, wildcardPositions []int) AlignmentResult {
+	wildcard := make([]bool, len(reference))
+	for _, pos := range wildcardPositions {
+		if pos >= 0 && pos < len(reference) {
+			wildcard[pos] = true
+		}
+	}
+
+	sc := defaultScorer()
+	sc.substitute = func(i, j int) int {
+		if wildcard[j] || query[i] == reference[j] {
+			return sc.match
+		}
+		return sc.mismatch
+	}
+
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: sc})
+	return result
+}
@@ -0,0 +1,142 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanWithMatcher aligns sequences that differ only by transitions
+// using a matcher that collapses purines (A/G) and pyrimidines (C/T)
+func TestSmithWatermanWithMatcher(t *testing.T) {
+	purineCollapsed := func(a, b byte) int {
+		isPurine := func(base byte) bool { return base == 'A' || base == 'G' }
+		if isPurine(a) == isPurine(b) {
+			return MatchScore
+		}
+		return MismatchScore
+	}
+
+	// Every base is replaced by its transition partner (A<->G, C<->T)
+	query := "GATTACAGATTACA"
+	reference := "AGCCGTGAGCCGTG"
+
+	result := SmithWatermanWithMatcher(query, reference, purineCollapsed)
+	if expected := len(query) * MatchScore; result.MaxScore != expected {
+		t.Errorf("Expected full score %d with the purine/pyrimidine matcher, got %d", expected, result.MaxScore)
+	}
+	if result.AlignedQuery != query || result.AlignedRef != reference {
+		t.Errorf("Expected an ungapped end-to-end alignment, got %s / %s", result.AlignedQuery, result.AlignedRef)
+	}
+
+	// The standard scoring sees these sequences as mostly mismatches
+	if standard := SmithWaterman(query, reference); standard.MaxScore >= result.MaxScore {
+		t.Errorf("Expected the standard score (%d) to be lower than the collapsed score (%d)",
+			standard.MaxScore, result.MaxScore)
+	}
+}
+
+// TestScoreFromCounts checks the DP score against the score rebuilt from the alignment's column counts
+func TestScoreFromCounts(t *testing.T) {
+	testCases := []struct {
+		query     string
+		reference string
+	}{
+		{"GATTACA", "GATTACA"},
+		{"GATTACAGATTACA", "GATTTACAGATACA"},
+		{"ACGTACGTTTGCA", "ACGAACGTTGCA"},
+		{generateRandomDNA(200), generateRandomDNA(150)},
+	}
+
+	for _, tc := range testCases {
+		result := SmithWaterman(tc.query, tc.reference)
+
+		matches, mismatches, gaps := 0, 0, 0
+		for i := 0; i < len(result.AlignedQuery); i++ {
+			switch {
+			case result.AlignedQuery[i] == '-' || result.AlignedRef[i] == '-':
+				gaps++
+			case result.AlignedQuery[i] == result.AlignedRef[i]:
+				matches++
+			default:
+				mismatches++
+			}
+		}
+
+		if expected := ScoreFromCounts(matches, mismatches, gaps, DefaultScoring); result.MaxScore != expected {
+			t.Errorf("Alignment of %s and %s scored %d, but its columns score %d",
+				tc.query, tc.reference, result.MaxScore, expected)
+		}
+	}
+
+	scheme := ScoringScheme{Match: 5, Mismatch: -4, Gap: -10}
+	if score := ScoreFromCounts(3, 2, 1, scheme); score != -3 {
+		t.Errorf("Expected a score of -3 with a custom scheme, got %d", score)
+	}
+}
+
+// TestSmithWatermanWithWildcards checks that a mismatch at a masked site scores as a match
+func TestSmithWatermanWithWildcards(t *testing.T) {
+	reference := "GATTACAGATTACA"
+	read := "GATTACCGATTACA" // Differs from the reference at position 6
+
+	plain := SmithWaterman(read, reference)
+	masked := SmithWatermanWithWildcards(read, reference, []int{6, -1, 100})
+
+	perfect := len(reference) * MatchScore
+	if masked.MaxScore != perfect {
+		t.Errorf("Expected the masked alignment to score %d, got %d", perfect, masked.MaxScore)
+	}
+	if plain.MaxScore >= perfect {
+		t.Errorf("Expected the unmasked alignment to score below %d, got %d", perfect, plain.MaxScore)
+	}
+	if masked.AlignedQuery != read || masked.AlignedRef != reference {
+		t.Errorf("Expected an ungapped alignment, got %s / %s", masked.AlignedQuery, masked.AlignedRef)
+	}
+
+	// Masking a site the read agrees with changes nothing
+	same := SmithWatermanWithWildcards(reference, reference, []int{3})
+	if same.MaxScore != perfect {
+		t.Errorf("Expected an identical read to score %d, got %d", perfect, same.MaxScore)
+	}
+}
+
+// TestSmithWatermanWithScoring checks that a custom scheme drives both the fill and the traceback
+func TestSmithWatermanWithScoring(t *testing.T) {
+	query, reference := "GATTACAGATTACA", "GATTTACAGATACA"
+
+	// The default scheme reproduces SmithWaterman
+	expected := SmithWaterman(query, reference)
+	result := SmithWatermanWithScoring(query, reference, DefaultScoring)
+	if result.MaxScore != expected.MaxScore || result.AlignedQuery != expected.AlignedQuery ||
+		result.AlignedRef != expected.AlignedRef {
+		t.Errorf("Default scheme gave %d %s/%s, expected %d %s/%s", result.MaxScore, result.AlignedQuery,
+			result.AlignedRef, expected.MaxScore, expected.AlignedQuery, expected.AlignedRef)
+	}
+
+	// With expensive gaps, a mismatch is cheaper than an indel
+	scheme := ScoringScheme{Match: 5, Mismatch: -1, Gap: -20}
+	result = SmithWatermanWithScoring("GATTACAGATTACA", "GATTACCGATTACA", scheme)
+	if result.MaxScore != 13*5-1 || strings.Contains(result.AlignedQuery+result.AlignedRef, "-") {
+		t.Errorf("Expected an ungapped alignment scoring %d, got %d: %s/%s",
+			13*5-1, result.MaxScore, result.AlignedQuery, result.AlignedRef)
+	}
+
+	// The traceback must agree with the matrix under the custom scheme
+	scheme = ScoringScheme{Match: 5, Mismatch: -4, Gap: -3}
+	result = SmithWatermanWithScoring(query, reference, scheme)
+	matches, mismatches, gaps := 0, 0, 0
+	for i := 0; i < len(result.AlignedQuery); i++ {
+		switch {
+		case result.AlignedQuery[i] == '-' || result.AlignedRef[i] == '-':
+			gaps++
+		case result.AlignedQuery[i] == result.AlignedRef[i]:
+			matches++
+		default:
+			mismatches++
+		}
+	}
+	if rescored := ScoreFromCounts(matches, mismatches, gaps, scheme); rescored != result.MaxScore {
+		t.Errorf("Alignment %s/%s rescored to %d, but MaxScore is %d",
+			result.AlignedQuery, result.AlignedRef, rescored, result.MaxScore)
+	}
+}
@@ -0,0 +1,112 @@
+package align
+
+// SemiGlobalAlign aligns the whole query to the best-matching region of the
+// reference, as when mapping a short read onto a longer reference. Gaps before and
+// after the query in the reference are free, so the overhanging reference is not
+// penalized, but unlike SmithWaterman every query base takes part in the alignment.
+// The first row is all zeros, letting the alignment start at any reference column,
+// and the traceback starts from the best cell in the last row.
+//
+// Parameters:
+//   - query (string): The DNA query sequence, aligned end to end.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. QueryStart and QueryEnd span the whole
+//     query, RefStart and RefEnd locate it in the reference, and MaxScore may be
+//     negative when the query matches poorly. Ties between end columns go to the
+//     leftmost.
+func SemiGlobalAlign(query, reference string) AlignmentResult {
+	m, n := len(query), len(reference)
+	sc := defaultScorer()
+
+	// Leading reference bases are free; leading query bases still cost a gap each
+	matrix := make([][]int, m+1)
+	for i := range matrix {
+		matrix[i] = make([]int, n+1)
+		matrix[i][0] = i * sc.gap
+	}
+
+	// Fill the score matrix
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			match := sc.score(query, reference, i-1, j-1)
+
+			scoreDiag := matrix[i-1][j-1] + match
+			scoreUp := matrix[i-1][j] + sc.gap
+			scoreLeft := matrix[i][j-1] + sc.gap
+
+			// Negative scores are kept, since the query may not be clipped
+			matrix[i][j] = smithMax(scoreDiag, scoreUp, scoreLeft)
+		}
+	}
+
+	// Trailing reference bases are free, so the alignment may end in any column
+	endCol := 0
+	for j := 1; j <= n; j++ {
+		if matrix[m][j] > matrix[m][endCol] {
+			endCol = j
+		}
+	}
+
+	alignedQuery, alignedRef, startCol := semiGlobalTraceback(matrix, query, reference, sc, endCol)
+
+	return AlignmentResult{
+		ScoreMatrix:  matrix,
+		MaxScore:     matrix[m][endCol],
+		MaxRow:       m,
+		MaxCol:       endCol,
+		AlignedQuery: alignedQuery,
+		AlignedRef:   alignedRef,
+		QueryStart:   0,
+		QueryEnd:     m,
+		RefStart:     startCol,
+		RefEnd:       endCol,
+		NoAlignment:  m == 0,
+	}
+}
+
+// semiGlobalTraceback reconstructs a semi-global alignment from the last row of the
+// matrix back to the first, where the free leading reference gap begins.
+//
+// Parameters:
+//   - matrix ([][]int): The alignment score matrix.
+//   - query (string): The query DNA sequence.
+//   - reference (string): The reference DNA sequence.
+//   - sc (scorer): The scores used to fill the matrix.
+//   - col (int): The column of the last row the alignment ends in.
+//
+// Returns:
+//   - (string, string, int): The aligned query and reference sequences, and the
+//     0-based start of the alignment in the reference.
+func semiGlobalTraceback(matrix [][]int, query, reference string, sc scorer, col int) (string, string, int) {
+	row := len(query)
+	alignedQuery := make([]byte, 0, MaxAlignmentLength(row, col))
+	alignedRef := make([]byte, 0, MaxAlignmentLength(row, col))
+
+	for row > 0 {
+		switch {
+		case col > 0 && matrix[row][col] == matrix[row-1][col-1]+sc.score(query, reference, row-1, col-1):
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, reference[col-1])
+			row--
+			col--
+		case matrix[row][col] == matrix[row-1][col]+sc.gap:
+			// Gap in reference
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
+			row--
+		default:
+			// Gap in query
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
+			col--
+		}
+	}
+
+	// The alignment was built back to front
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return string(alignedQuery), string(alignedRef), col
+}
@@ -0,0 +1,39 @@
+package align
+
+import "testing"
+
+// TestSemiGlobalAlign checks that the whole query is aligned and that the
+// reference overhangs on either side cost nothing
+func TestSemiGlobalAlign(t *testing.T) {
+	result := SemiGlobalAlign("GATTACA", "XXXXXXGATTACAXXXXXX")
+	if result.AlignedQuery != "GATTACA" || result.AlignedRef != "GATTACA" {
+		t.Errorf("Expected GATTACA/GATTACA, got %q/%q", result.AlignedQuery, result.AlignedRef)
+	}
+	if result.MaxScore != 7*MatchScore {
+		t.Errorf("Expected score %d with free overhangs, got %d", 7*MatchScore, result.MaxScore)
+	}
+	if result.RefStart != 6 || result.RefEnd != 13 || result.QueryStart != 0 || result.QueryEnd != 7 {
+		t.Errorf("Expected query [0,7) at reference [6,13), got [%d,%d) at [%d,%d)",
+			result.QueryStart, result.QueryEnd, result.RefStart, result.RefEnd)
+	}
+
+	// A local alignment would clip the mismatched read ends; semi-global keeps them
+	read := "TTGATTACATT"
+	reference := "CCCCGATTACACCCC"
+	if local := SmithWaterman(read, reference); local.QueryStart == 0 && local.QueryEnd == len(read) {
+		t.Fatalf("Expected the local alignment to clip the read, got [%d,%d)", local.QueryStart, local.QueryEnd)
+	}
+	full := SemiGlobalAlign(read, reference)
+	if err := VerifyAlignment(full, read, reference); err != nil {
+		t.Errorf("Semi-global alignment failed verification: %v", err)
+	}
+	if full.QueryStart != 0 || full.QueryEnd != len(read) {
+		t.Errorf("Expected the whole read to be aligned, got [%d,%d)", full.QueryStart, full.QueryEnd)
+	}
+
+	// A query longer than the reference is aligned against gaps
+	long := SemiGlobalAlign("ACGTACGT", "CGTA")
+	if long.AlignedQuery != "ACGTACGT" || long.AlignedRef != "-CGTA---" {
+		t.Errorf("Expected ACGTACGT/-CGTA---, got %q/%q", long.AlignedQuery, long.AlignedRef)
+	}
+}
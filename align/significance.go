@@ -0,0 +1,58 @@
+package align
+
+import (
+	"math"
+	"runtime"
+
+	"pgfp/data"
+)
+
+// AlignmentZScore measures how far an alignment score stands above chance for
+// sequences of the same composition.
+//
+// The query is shuffled the given number of times, preserving its base counts, and
+// each shuffle is aligned to the reference. The observed score is then expressed in
+// standard deviations above the mean shuffled score. Shuffles use fixed seeds, so the
+// result is reproducible.
+//
+// Parameters:
+//   - query (string): The query sequence.
+//   - reference (string): The reference sequence.
+//   - shuffles (int): The number of shuffled queries to score (at least 2).
+//
+// Returns:
+//   - (float64): (observed - mean) / stddev of the shuffled scores, or 0 if fewer than
+//     two shuffles are requested or every shuffled score is the same.
+func AlignmentZScore(query, reference string, shuffles int) float64 {
+	if shuffles < 2 {
+		return 0
+	}
+
+	observed := SmithWaterman(query, reference).MaxScore
+
+	shuffled := make([]string, shuffles)
+	for i := range shuffled {
+		shuffled[i] = data.ShuffleSequence(query, int64(i+1))
+	}
+
+	// Local alignment scores are symmetric, so one batch aligns the reference against every shuffle
+	results := ConcurrentSmithWatermanBatchReuse(reference, shuffled, runtime.GOMAXPROCS(0))
+
+	mean := 0.0
+	for _, result := range results {
+		mean += float64(result.MaxScore)
+	}
+	mean /= float64(shuffles)
+
+	variance := 0.0
+	for _, result := range results {
+		diff := float64(result.MaxScore) - mean
+		variance += diff * diff
+	}
+	stddev := math.Sqrt(variance / float64(shuffles-1))
+	if stddev == 0 {
+		return 0
+	}
+
+	return (float64(observed) - mean) / stddev
+}
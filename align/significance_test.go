@@ -0,0 +1,29 @@
+package align
+
+import (
+	"testing"
+
+	"pgfp/data"
+)
+
+// TestAlignmentZScore checks that related sequences stand far above their shuffles and unrelated ones do not
+func TestAlignmentZScore(t *testing.T) {
+	query := "GATTACAGATCAGATAGATACAGATAGACCAGGTACCATGCATGCAGTCA"
+
+	if z := AlignmentZScore(query, query, 50); z < 5 {
+		t.Errorf("Expected a high z-score for identical sequences, got %f", z)
+	}
+
+	// A shuffle of the query is just another draw from the null distribution
+	unrelated := data.ShuffleSequence(query, 1000)
+	if z := AlignmentZScore(query, unrelated, 50); z > 3 {
+		t.Errorf("Expected a low z-score for an unrelated sequence, got %f", z)
+	}
+
+	if z := AlignmentZScore(query, query, 1); z != 0 {
+		t.Errorf("Expected 0 with a single shuffle, got %f", z)
+	}
+	if AlignmentZScore(query, query, 20) != AlignmentZScore(query, query, 20) {
+		t.Error("Expected reproducible z-scores")
+	}
+}
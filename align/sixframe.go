@@ -0,0 +1,36 @@
+package align
+
+import "pgfp/data"
+
+// SixFrameAlign translates a DNA query in all six reading frames and aligns each
+// peptide against a protein reference, as in a tblastn search. Frames 0 to 2 read
+// the query from offsets 0, 1, and 2; frames 3 to 5 read its reverse complement
+// from the same offsets. Stop codons are kept as '*' so the peptide of each frame
+// stays contiguous; BLOSUM62 penalizes aligning them.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The protein reference sequence.
+//   - sm (SubstitutionMatrix): The score for each pair of residues.
+//   - gap (int): The score for each gap column, usually negative.
+//
+// Returns:
+//   - (AlignmentResult): The highest-scoring alignment. AlignedQuery and the query
+//     coordinates refer to the translated peptide of the winning frame.
+//   - (int): The winning frame, 0 to 5. On a tie the lowest frame is kept.
+func SixFrameAlign(query, reference string, sm SubstitutionMatrix, gap int) (AlignmentResult, int) {
+	strands := [2]string{query, data.ReverseComplement(query)}
+
+	var best AlignmentResult
+	bestFrame := -1
+	for frame := 0; frame < 6; frame++ {
+		peptide := data.Translate(strands[frame/3], frame%3, false)
+
+		result := SmithWatermanMatrix(peptide, reference, sm, gap)
+		if bestFrame < 0 || result.MaxScore > best.MaxScore {
+			best, bestFrame = result, frame
+		}
+	}
+
+	return best, bestFrame
+}
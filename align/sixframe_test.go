@@ -0,0 +1,30 @@
+package align
+
+import (
+	"testing"
+
+	"pgfp/data"
+)
+
+// TestSixFrameAlign checks that the frame encoding the reference protein wins, on
+// both strands and at each offset
+func TestSixFrameAlign(t *testing.T) {
+	// Encodes MKWVTFISLL followed by a stop
+	coding := "ATGAAATGGGTAACCTTTATTTCCCTTCTTTAA"
+	protein := "MKWVTFISLL"
+
+	for frame := 0; frame < 6; frame++ {
+		query := "GGGG"[:frame%3] + coding + "CC"
+		if frame >= 3 {
+			query = data.ReverseComplement(query)
+		}
+
+		result, got := SixFrameAlign(query, protein, BLOSUM62(), -4)
+		if got != frame {
+			t.Errorf("Expected frame %d to win, got %d", frame, got)
+		}
+		if result.AlignedQuery != protein || result.AlignedRef != protein {
+			t.Errorf("Frame %d: expected %s aligned to itself, got %q/%q", frame, protein, result.AlignedQuery, result.AlignedRef)
+		}
+	}
+}
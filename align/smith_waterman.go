@@ -1,5 +1,12 @@
 package align
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
 // Scoring parameters
 const (
 	MatchScore    = 2  // Score for a matching base
@@ -11,12 +18,52 @@ const (
 type AlignmentResult struct {
 	ScoreMatrix  [][]int // The Smith-Waterman dynamic programming matrix
 	MaxScore     int     // Maximum score in the matrix
+	MaxRow       int     // Row index of the maximum score
+	MaxCol       int     // Column index of the maximum score
 	AlignedQuery string  // The aligned query sequence
 	AlignedRef   string  // The aligned reference sequence
+	QueryStart   int     // Start of the aligned region in the query (0-based, inclusive)
+	QueryEnd     int     // End of the aligned region in the query (exclusive)
+	RefStart     int     // Start of the aligned region in the reference (0-based, inclusive)
+	RefEnd       int     // End of the aligned region in the reference (exclusive)
+	NoAlignment  bool    // True when no pair of bases scores above zero, so the alignment is empty
+
+	// MatrixRowOffset and MatrixColOffset locate ScoreMatrix in the full matrix when
+	// only a window of it is kept (see SmithWatermanWindowed): ScoreMatrix[i][j] is
+	// cell (i+MatrixRowOffset, j+MatrixColOffset). Both are zero for a full matrix.
+	MatrixRowOffset int
+	MatrixColOffset int
+
+	// ClippedPrefix and ClippedSuffix hold the query bases before QueryStart and from
+	// QueryEnd on, which a local alignment leaves unaligned (soft clipping in SAM terms).
+	ClippedPrefix string
+	ClippedSuffix string
+
+	// AlternativeAlignments holds other optimal alignments when the traceback is
+	// ambiguous. It is only populated by SmithWatermanAlternatives, and each entry is
+	// an {alignedQuery, alignedRef} pair.
+	AlternativeAlignments [][2]string
+
+	// Provenance optionally records how the result was produced. It is nil unless
+	// attached with WithProvenance.
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
+// ErrAlignmentTooLong is returned when a traceback exceeds the configured maximum alignment length.
+var ErrAlignmentTooLong = errors.New("alignment exceeds maximum length")
+
+// ProgressFunc receives progress updates from long-running alignments.
+// done is the number of completed work units (matrix rows for a single
+// alignment, references for a batch) and total is the number expected.
+type ProgressFunc func(done, total int)
+
 // SmithWaterman performs local sequence alignment using the Smith-Waterman algorithm.
 //
+// When several cells share the maximum score, the alignment ends at the first of
+// them in row-major order (the smallest query position, then the smallest reference
+// position). Every aligner in this package applies the same rule, so sequential and
+// parallel implementations report the same alignment.
+//
 // Parameters:
 //   - query (string): The DNA query sequence.
 //   - reference (string): The DNA reference sequence.
@@ -24,51 +71,285 @@ type AlignmentResult struct {
 // Returns:
 //   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
 func SmithWaterman(query, reference string) AlignmentResult {
+	return SmithWatermanWithScoring(query, reference, DefaultScoring)
+}
+
+// SmithWatermanWithScoring performs local sequence alignment with the given
+// match, mismatch, and gap scores instead of the package defaults. The same
+// scores are used to fill the matrix and to trace back through it.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - scheme (ScoringScheme): The scores to align with.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
+func SmithWatermanWithScoring(query, reference string, scheme ScoringScheme) AlignmentResult {
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: scheme.scorer()})
+	return result
+}
+
+// SmithWatermanWithProgress performs the same alignment as SmithWaterman but
+// reports progress after each row of the score matrix is filled.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - progress (ProgressFunc): Called with (rowsDone, totalRows); may be nil.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
+func SmithWatermanWithProgress(query, reference string, progress ProgressFunc) AlignmentResult {
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), progress: progress})
+	return result
+}
+
+// SmithWatermanMaxLength performs the same alignment as SmithWaterman but aborts the
+// traceback once the alignment grows past maxLength columns. This bounds the memory
+// used for the aligned strings when aligning untrusted input, where a long run of
+// positive scores could otherwise produce an alignment as long as the two inputs combined.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - maxLength (int): The maximum number of alignment columns (0 = unlimited).
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, or a zero value if the cap was exceeded.
+//   - (error): An error wrapping ErrAlignmentTooLong if the alignment is longer than maxLength.
+func SmithWatermanMaxLength(query, reference string, maxLength int) (AlignmentResult, error) {
+	return smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), maxLength: maxLength})
+}
+
+// SmithWatermanAlternatives performs the same alignment as SmithWaterman and also
+// exposes the non-uniqueness of the optimal local alignment. The traceback is run
+// twice from the maximum-scoring cell: once breaking ties toward the diagonal
+// (the default, used for AlignedQuery/AlignedRef) and once breaking ties toward gaps.
+// When the two tracebacks differ, both are returned in AlternativeAlignments,
+// the diagonal-preferring one first. Both alignments have the same score.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, with AlternativeAlignments set if the tracebacks differ.
+func SmithWatermanAlternatives(query, reference string) AlignmentResult {
+	result := SmithWaterman(query, reference)
+
+	alignedQuery, alignedRef, _, _, _ := traceback(result.ScoreMatrix, query, reference, defaultScorer(),
+		result.QueryEnd, result.RefEnd, 0, true, nil)
+	if alignedQuery != result.AlignedQuery || alignedRef != result.AlignedRef {
+		result.AlternativeAlignments = [][2]string{
+			{result.AlignedQuery, result.AlignedRef},
+			{alignedQuery, alignedRef},
+		}
+	}
+
+	return result
+}
+
+// SmithWatermanEndBonus performs local alignment with a bonus for alignments that
+// reach the end of either sequence. When choosing where the alignment ends, cells in
+// the last row or column score endBonus higher, which favours dovetail overlaps
+// (the suffix of one read aligned to the prefix of another) over contained internal
+// matches, as wanted for assembly overlap detection. The matrix itself is unchanged.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - endBonus (int): The score added to alignments ending at a sequence boundary.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result. MaxScore includes the bonus when the
+//     chosen alignment reaches a boundary.
+func SmithWatermanEndBonus(query, reference string, endBonus int) AlignmentResult {
+	result, _ := smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), endBonus: endBonus})
+	return result
+}
+
+// SmithWatermanContext performs the same alignment as SmithWaterman but stops early
+// if ctx is cancelled. The context is checked once per row of the score matrix, so
+// a cancelled alignment releases the CPU within one row's work.
+//
+// Parameters:
+//   - ctx (context.Context): Cancels the alignment when done.
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, or a zero value if cancelled.
+//   - (error): ctx.Err() if the context was cancelled before the alignment finished.
+func SmithWatermanContext(ctx context.Context, query, reference string) (AlignmentResult, error) {
+	return smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), ctx: ctx})
+}
+
+// SmithWatermanContextMaxLength combines SmithWatermanContext and
+// SmithWatermanMaxLength for request handlers aligning untrusted input: the fill
+// stops if ctx is cancelled, and the traceback aborts once the alignment grows past
+// maxLength columns.
+//
+// Parameters:
+//   - ctx (context.Context): Cancels the alignment when done.
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - maxLength (int): The maximum number of alignment columns (0 = unlimited).
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, or a zero value on error.
+//   - (error): ctx.Err() if the context was cancelled, or an error wrapping
+//     ErrAlignmentTooLong if the alignment is longer than maxLength.
+func SmithWatermanContextMaxLength(ctx context.Context, query, reference string, maxLength int) (AlignmentResult, error) {
+	return smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), ctx: ctx, maxLength: maxLength})
+}
+
+// SmithWatermanEarlyExit performs the same alignment as SmithWaterman but gives up
+// as soon as no alignment can reach minScore. After each row it bounds the best
+// score still possible: any later cell extends an alignment ending in the current
+// row (or starts afresh) and gains at most MatchScore per remaining query base.
+// Once that bound and the best score so far both fall short, the rest of the matrix
+// is skipped, which makes it cheap to discard unrelated references in a large batch.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - minScore (int): The score an alignment needs to be worth reporting.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, or a zero value when the bool is false.
+//   - (bool): True if the best alignment scores at least minScore.
+func SmithWatermanEarlyExit(query, reference string, minScore int) (AlignmentResult, bool) {
+	result, err := smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), minScore: minScore})
+	if err != nil || result.MaxScore < minScore {
+		return AlignmentResult{}, false
+	}
+	return result, true
+}
+
+// alignOptions collects the settings shared by the sequential alignment variants.
+type alignOptions struct {
+	scorer    scorer          // Substitution and gap scores
+	progress  ProgressFunc    // Called after each matrix row; may be nil
+	maxLength int             // Maximum number of alignment columns (0 = unlimited)
+	timings   *PhaseTimings   // Receives the duration of each phase; may be nil
+	onStep    func(TraceStep) // Called for each traceback move; may be nil
+	endBonus  int             // Added to positive cells in the last row or column when picking the maximum
+	ctx       context.Context // Checked once per row; may be nil
+	masked    [][]bool        // Cells forced to zero, indexed like the matrix; may be nil
+	matrix    [][]int         // Preallocated (m+1)x(n+1) score matrix with a zero first row and column; may be nil
+	minScore  int             // Abandon the fill once no cell can reach this score (0 = never)
+}
+
+// errScoreUnreachable stops the fill when no alignment can reach alignOptions.minScore.
+var errScoreUnreachable = errors.New("minimum score unreachable")
+
+// smithWaterman fills the score matrix and runs the traceback, reporting progress
+// per row and enforcing an optional cap on the alignment length.
+func smithWaterman(query, reference string, opts alignOptions) (AlignmentResult, error) {
 	m, n := len(query), len(reference)
+	sc := opts.scorer
+	fillStart := time.Now()
 
-	// Initialize score matrix
-	matrix := make([][]int, m+1)
-	for i := range matrix {
-		matrix[i] = make([]int, n+1)
+	// Initialize score matrix, unless the caller supplied one to reuse
+	matrix := opts.matrix
+	if matrix == nil {
+		matrix = make([][]int, m+1)
+		for i := range matrix {
+			matrix[i] = make([]int, n+1)
+		}
 	}
 
-	maxScore := 0
-	maxRow, maxCol := 0, 0
+	best := alignerCell{}
 
 	// Fill the score matrix
 	for i := 1; i <= m; i++ {
+		if opts.ctx != nil {
+			if err := opts.ctx.Err(); err != nil {
+				return AlignmentResult{}, err
+			}
+		}
+
+		rowBest := 0
 		for j := 1; j <= n; j++ {
 			// Determine if this is a match or mismatch
-			match := MismatchScore
-			if query[i-1] == reference[j-1] {
-				match = MatchScore
-			}
+			match := sc.score(query, reference, i-1, j-1)
 
 			// Compute scores
 			scoreDiag := matrix[i-1][j-1] + match
-			scoreUp := matrix[i-1][j] + GapPenalty
-			scoreLeft := matrix[i][j-1] + GapPenalty
+			scoreUp := matrix[i-1][j] + sc.gap
+			scoreLeft := matrix[i][j-1] + sc.gap
 
 			// Apply Smith-Waterman scoring rule (no negative scores)
 			matrix[i][j] = smithMax(0, scoreDiag, scoreUp, scoreLeft)
+			if opts.masked != nil && opts.masked[i][j] {
+				matrix[i][j] = 0
+			}
 
-			// Track maximum score for traceback
-			if matrix[i][j] > maxScore {
-				maxScore = matrix[i][j]
-				maxRow, maxCol = i, j
+			// Track maximum score for traceback, rewarding alignments that reach a sequence end
+			score := matrix[i][j]
+			if score > 0 && (i == m || j == n) {
+				score += opts.endBonus
 			}
+			if cell := (alignerCell{score: score, row: i, col: j}); isBetterCell(cell, best) {
+				best = cell
+			}
+			rowBest = max(rowBest, matrix[i][j])
+		}
+
+		// Every remaining query base can add at most one match to the best alignment
+		if opts.minScore > 0 && best.score < opts.minScore && rowBest+(m-i)*sc.match < opts.minScore {
+			return AlignmentResult{}, errScoreUnreachable
+		}
+
+		if opts.progress != nil {
+			opts.progress(i, m)
 		}
 	}
 
+	maxScore, maxRow, maxCol := best.score, best.row, best.col
+
 	// Traceback to reconstruct the alignment
-	alignedQuery, alignedRef := traceback(matrix, query, reference, maxRow, maxCol)
+	tracebackStart := time.Now()
+	alignedQuery, alignedRef, startRow, startCol, err := traceback(matrix, query, reference, sc, maxRow, maxCol, opts.maxLength, false, opts.onStep)
+	if opts.timings != nil {
+		opts.timings.Fill = tracebackStart.Sub(fillStart)
+		opts.timings.Traceback = time.Since(tracebackStart)
+	}
+	if err != nil {
+		return AlignmentResult{}, err
+	}
 
 	return AlignmentResult{
-		ScoreMatrix:  matrix,
-		MaxScore:     maxScore,
-		AlignedQuery: alignedQuery,
-		AlignedRef:   alignedRef,
-	}
+		ScoreMatrix:   matrix,
+		MaxScore:      maxScore,
+		MaxRow:        maxRow,
+		MaxCol:        maxCol,
+		AlignedQuery:  alignedQuery,
+		AlignedRef:    alignedRef,
+		QueryStart:    startRow,
+		QueryEnd:      maxRow,
+		RefStart:      startCol,
+		RefEnd:        maxCol,
+		NoAlignment:   maxScore == 0,
+		ClippedPrefix: query[:startRow],
+		ClippedSuffix: query[maxRow:],
+	}, nil
+}
+
+// MaxAlignmentLength returns the largest number of columns any alignment of two
+// sequences of the given lengths can have. Every column consumes a base from at
+// least one sequence, so the bound is reached only when no two bases are paired
+// and every column is a gap on one side.
+//
+// Parameters:
+//   - queryLen (int): The length of the query.
+//   - refLen (int): The length of the reference.
+//
+// Returns:
+//   - (int): The upper bound on alignment length, queryLen + refLen.
+func MaxAlignmentLength(queryLen, refLen int) int {
+	return queryLen + refLen
 }
 
 // traceback reconstructs the best local alignment from the score matrix.
@@ -77,39 +358,84 @@ func SmithWaterman(query, reference string) AlignmentResult {
 //   - matrix ([][]int): The alignment score matrix.
 //   - query (string): The query DNA sequence.
 //   - reference (string): The reference DNA sequence.
+//   - sc (scorer): The scores used to fill the matrix.
 //   - row (int): The row index of the highest score.
 //   - col (int): The column index of the highest score.
+//   - maxLength (int): The maximum number of alignment columns (0 = unlimited).
+//   - preferGaps (bool): When a gap move and the diagonal move are equally valid, take the
+//     gap move instead of the diagonal one. Both choices yield an optimal alignment.
+//   - onStep (func(TraceStep)): Called with each move before it is taken; may be nil.
 //
 // Returns:
-//   - (string, string): The aligned query and reference sequences.
-func traceback(matrix [][]int, query, reference string, row, col int) (string, string) {
-	var alignedQuery, alignedRef string
+//   - (string, string, int, int): The aligned query and reference sequences, followed by
+//     the row and column where the traceback stopped (the 0-based start of the alignment
+//     in the query and reference).
+//   - (error): An error wrapping ErrAlignmentTooLong if the alignment exceeds maxLength.
+func traceback(matrix [][]int, query, reference string, sc scorer, row, col, maxLength int, preferGaps bool, onStep func(TraceStep)) (string, string, int, int, error) {
+	// Build the alignment back to front and reverse it once at the end
+	capacity := MaxAlignmentLength(row, col)
+	if maxLength > 0 && maxLength < capacity {
+		capacity = maxLength
+	}
+	alignedQuery := make([]byte, 0, capacity)
+	alignedRef := make([]byte, 0, capacity)
 
 	// Perform traceback from the highest scoring cell
 	for row > 0 && col > 0 && matrix[row][col] > 0 {
+		if maxLength > 0 && len(alignedQuery) >= maxLength {
+			return "", "", 0, 0, fmt.Errorf("%w (limit %d columns)", ErrAlignmentTooLong, maxLength)
+		}
+
 		currentScore := matrix[row][col]
 
 		// Calculate match score for current position
-		match := MismatchScore
-		if query[row-1] == reference[col-1] {
-			match = MatchScore
+		match := sc.score(query, reference, row-1, col-1)
+
+		// Determine which moves could have produced the current score
+		diagOK := currentScore == matrix[row-1][col-1]+match
+		upOK := currentScore == matrix[row-1][col]+sc.gap
+		leftOK := currentScore == matrix[row][col-1]+sc.gap
+
+		// Break ties toward gaps if requested
+		if preferGaps && (upOK || leftOK) {
+			diagOK = false
+		}
+
+		if onStep != nil {
+			step := TraceStep{
+				Row:   row,
+				Col:   col,
+				Score: currentScore,
+				Diag:  matrix[row-1][col-1] + match,
+				Up:    matrix[row-1][col] + sc.gap,
+				Left:  matrix[row][col-1] + sc.gap,
+			}
+			switch {
+			case diagOK:
+				step.Move = MoveDiagonal
+			case upOK:
+				step.Move = MoveUp
+			case leftOK:
+				step.Move = MoveLeft
+			}
+			onStep(step)
 		}
 
 		// Check diagonal move (match/mismatch)
-		if currentScore == matrix[row-1][col-1]+match {
-			alignedQuery = string(query[row-1]) + alignedQuery
-			alignedRef = string(reference[col-1]) + alignedRef
+		if diagOK {
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, reference[col-1])
 			row--
 			col--
-		} else if currentScore == matrix[row-1][col]+GapPenalty {
+		} else if upOK {
 			// Gap in reference
-			alignedQuery = string(query[row-1]) + alignedQuery
-			alignedRef = "-" + alignedRef
+			alignedQuery = append(alignedQuery, query[row-1])
+			alignedRef = append(alignedRef, '-')
 			row--
-		} else if currentScore == matrix[row][col-1]+GapPenalty {
+		} else if leftOK {
 			// Gap in query
-			alignedQuery = "-" + alignedQuery
-			alignedRef = string(reference[col-1]) + alignedRef
+			alignedQuery = append(alignedQuery, '-')
+			alignedRef = append(alignedRef, reference[col-1])
 			col--
 		} else {
 			// This shouldn't happen with correct scoring, but break as a safeguard
@@ -117,7 +443,31 @@ func traceback(matrix [][]int, query, reference string, row, col int) (string, s
 		}
 	}
 
-	return alignedQuery, alignedRef
+	reverseBytes(alignedQuery)
+	reverseBytes(alignedRef)
+
+	return string(alignedQuery), string(alignedRef), row, col, nil
+}
+
+// alignerCell is a candidate maximum-scoring cell of the score matrix.
+type alignerCell struct {
+	score, row, col int
+}
+
+// isBetterCell reports whether candidate should replace current as the maximum.
+// Higher scores win; equal positive scores go to the cell that comes first in
+// row-major order, which matches the order the sequential algorithm scans the matrix.
+func isBetterCell(candidate, current alignerCell) bool {
+	if candidate.score != current.score {
+		return candidate.score > current.score
+	}
+	if candidate.score == 0 {
+		return false
+	}
+	if candidate.row != current.row {
+		return candidate.row < current.row
+	}
+	return candidate.col < current.col
 }
 
 // smithMax returns the maximum of the provided integer values.
@@ -130,3 +480,10 @@ func smithMax(values ...int) int {
 	}
 	return maxVal
 }
+
+// reverseBytes reverses a byte slice in place.
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
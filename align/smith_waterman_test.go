@@ -1,96 +1,21 @@
 package align
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 )
 
-// TestCase defines the structure for test inputs and expected results.
-type TestCase struct {
-	Query         string
-	Reference     string
-	ExpectedScore int
-	// Instead of expecting exact alignments, we'll check for correct matching regions
-	RequiredMatches []string
-}
-
-// TestSmithWaterman runs multiple test cases to verify correctness.
+// TestSmithWaterman runs the known cases to verify correctness.
 func TestSmithWaterman(t *testing.T) {
-	testCases := []TestCase{
-		// Test Case 1: Perfect Match (Score = 7*2 = 14)
-		{
-			Query:           "GATTACA",
-			Reference:       "GATTACA",
-			ExpectedScore:   14,
-			RequiredMatches: []string{"GATTACA"},
-		},
-		// Test Case 2: One Mismatch (Score = (6*2) - 1 = 11)
-		{
-			Query:           "GATTACA",
-			Reference:       "GATTTCA",
-			ExpectedScore:   11,
-			RequiredMatches: []string{"GAT", "CA"}, // Before and after the mismatch
-		},
-		// Test Case 3: Insertion/Deletion (Score = (6*2) - 2 = 10)
-		{
-			Query:           "GATTACA",
-			Reference:       "GATACA",
-			ExpectedScore:   10,
-			RequiredMatches: []string{"GAT", "ACA"}, // Before and after the indel
-		},
-		// Test Case 4: Insertion/Deletion (Score = (6*2) - 2 = 10)
-		{
-			Query:           "GATTACA",
-			Reference:       "GATTCA",
-			ExpectedScore:   10,
-			RequiredMatches: []string{"GATT", "CA"}, // Before and after the indel
-		},
-		// Test Case 5: Partial match at beginning
-		{
-			Query:           "GATTACA",
-			Reference:       "GATXXXX",
-			ExpectedScore:   6, // 3 matches * 2
-			RequiredMatches: []string{"GAT"},
-		},
-		// Test Case 6: Partial match at end
-		{
-			Query:           "GATTACA",
-			Reference:       "XXXXACA",
-			ExpectedScore:   6, // 3 matches * 2
-			RequiredMatches: []string{"ACA"},
-		},
-		// Test Case 7: Local alignment should find best subsection
-		{
-			Query:           "XXGATTACAXX",
-			Reference:       "YYGATTACAYY",
-			ExpectedScore:   14, // Should find the GATTACA substring
-			RequiredMatches: []string{"GATTACA"},
-		},
-	}
-
 	// Run all test cases
-	for i, tc := range testCases {
+	for i, tc := range KnownCases() {
 		result := SmithWaterman(tc.Query, tc.Reference)
 
-		// Validate alignment score
-		if result.MaxScore != tc.ExpectedScore {
-			t.Errorf("Test case %d - FAIL: Query: %s, Ref: %s\nExpected Score: %d, Got: %d",
-				i+1, tc.Query, tc.Reference, tc.ExpectedScore, result.MaxScore)
-		}
-
-		// Validate that required matching sections are present in the alignment
-		strippedQuery := stripGaps(result.AlignedQuery)
-		strippedRef := stripGaps(result.AlignedRef)
-
-		for _, match := range tc.RequiredMatches {
-			if !containsSubsequence(strippedQuery, match) {
-				t.Errorf("Test case %d - FAIL: Query alignment %s does not contain required match: %s",
-					i+1, strippedQuery, match)
-			}
-			if !containsSubsequence(strippedRef, match) {
-				t.Errorf("Test case %d - FAIL: Reference alignment %s does not contain required match: %s",
-					i+1, strippedRef, match)
-			}
+		// Check the score and the required matching sections
+		if err := tc.Check(result); err != nil {
+			t.Errorf("Test case %d (%s) - FAIL: %v", i+1, tc.Name, err)
 		}
 
 		// Check that the aligned sequences make biological sense
@@ -106,11 +31,6 @@ func stripGaps(seq string) string {
 	return strings.ReplaceAll(seq, "-", "")
 }
 
-// containsSubsequence checks if a sequence contains a specific subsequence
-func containsSubsequence(seq, subseq string) bool {
-	return strings.Contains(seq, subseq)
-}
-
 // isValidAlignment checks if an alignment makes biological sense
 func isValidAlignment(query, reference string) bool {
 	// Alignments must be the same length
@@ -127,3 +47,283 @@ func isValidAlignment(query, reference string) bool {
 
 	return true
 }
+
+// TestAlignmentCoordinates verifies the reported start and end of the aligned region
+func TestAlignmentCoordinates(t *testing.T) {
+	result := SmithWaterman("XXGATTACAXX", "YYYGATTACAYY")
+
+	if result.QueryStart != 2 || result.QueryEnd != 9 {
+		t.Errorf("Expected query coordinates [2,9), got [%d,%d)", result.QueryStart, result.QueryEnd)
+	}
+	if result.RefStart != 3 || result.RefEnd != 10 {
+		t.Errorf("Expected reference coordinates [3,10), got [%d,%d)", result.RefStart, result.RefEnd)
+	}
+}
+
+// TestSmithWatermanMaxLength verifies that long tracebacks are aborted at the cap
+func TestSmithWatermanMaxLength(t *testing.T) {
+	// A repetitive sequence aligned to itself never drops to zero, so the
+	// traceback walks the whole diagonal
+	seq := strings.Repeat("AC", 50)
+
+	_, err := SmithWatermanMaxLength(seq, seq, 64)
+	if !errors.Is(err, ErrAlignmentTooLong) {
+		t.Fatalf("Expected ErrAlignmentTooLong, got %v", err)
+	}
+
+	// The same input fits when the cap is large enough
+	result, err := SmithWatermanMaxLength(seq, seq, len(seq))
+	if err != nil {
+		t.Fatalf("Unexpected error with a sufficient cap: %v", err)
+	}
+	if result.AlignedQuery != seq {
+		t.Errorf("Expected full self-alignment, got %s", result.AlignedQuery)
+	}
+
+	// Zero disables the cap
+	if _, err := SmithWatermanMaxLength(seq, seq, 0); err != nil {
+		t.Errorf("Unexpected error with the cap disabled: %v", err)
+	}
+
+	// The context variant enforces the same cap and also honours cancellation
+	if _, err := SmithWatermanContextMaxLength(context.Background(), seq, seq, 64); !errors.Is(err, ErrAlignmentTooLong) {
+		t.Errorf("Expected ErrAlignmentTooLong from the context variant, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SmithWatermanContextMaxLength(ctx, seq, seq, len(seq)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from the context variant, got %v", err)
+	}
+}
+
+// TestSmithWatermanContext checks that a cancelled context stops the fill and a live one does not
+func TestSmithWatermanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancel from the progress callback once a few rows are filled
+	rows := 0
+	result, err := smithWaterman("GATTACAGATTACA", "GATTACAGATTACA", alignOptions{
+		scorer: defaultScorer(),
+		ctx:    ctx,
+		progress: func(done, total int) {
+			rows = done
+			if done == 3 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if rows != 3 || result.MaxScore != 0 {
+		t.Errorf("Expected the fill to stop after row 3 with a zero result, stopped after %d rows", rows)
+	}
+
+	if _, err := SmithWatermanContext(ctx, "GATTACA", "GATTACA"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected an already-cancelled context to fail, got %v", err)
+	}
+
+	result, err = SmithWatermanContext(context.Background(), "GATTACA", "GATTACA")
+	if err != nil || result.MaxScore != 14 {
+		t.Errorf("Expected score 14 without cancellation, got %d (err %v)", result.MaxScore, err)
+	}
+}
+
+// TestSmithWatermanAlternatives checks that ambiguous gap placements are both reported
+func TestSmithWatermanAlternatives(t *testing.T) {
+	// The deleted T can be placed on either side of the remaining T
+	result := SmithWatermanAlternatives("GATTACA", "GATACA")
+
+	if len(result.AlternativeAlignments) != 2 {
+		t.Fatalf("Expected 2 alternative alignments, got %d: %v",
+			len(result.AlternativeAlignments), result.AlternativeAlignments)
+	}
+
+	first, second := result.AlternativeAlignments[0], result.AlternativeAlignments[1]
+	if first == second {
+		t.Errorf("Expected the alternatives to differ, both are %v", first)
+	}
+	if first[0] != result.AlignedQuery || first[1] != result.AlignedRef {
+		t.Errorf("Expected the first alternative to be the primary alignment, got %v", first)
+	}
+
+	// Both must be valid alignments with the optimal score
+	for _, alt := range result.AlternativeAlignments {
+		if !isValidAlignment(alt[0], alt[1]) {
+			t.Errorf("Invalid alternative alignment: %v", alt)
+		}
+		if score := AlignmentScore(alt[0], alt[1]); score != result.MaxScore {
+			t.Errorf("Alternative %v scores %d, expected %d", alt, score, result.MaxScore)
+		}
+	}
+
+	// An unambiguous alignment has no alternatives
+	if alts := SmithWatermanAlternatives("GATTACA", "GATTACA").AlternativeAlignments; alts != nil {
+		t.Errorf("Expected no alternatives for a perfect match, got %v", alts)
+	}
+}
+
+// TestClippedBases checks that unaligned query flanks are reported as clipped
+func TestClippedBases(t *testing.T) {
+	prefix, core, suffix := "CCCCC", "GATTACAGATTACA", "TTT"
+	result := SmithWaterman(prefix+core+suffix, "AAGG"+core+"GG")
+
+	if result.ClippedPrefix != prefix {
+		t.Errorf("Expected clipped prefix %s, got %s", prefix, result.ClippedPrefix)
+	}
+	if result.ClippedSuffix != suffix {
+		t.Errorf("Expected clipped suffix %s, got %s", suffix, result.ClippedSuffix)
+	}
+	if result.ClippedPrefix+stripGaps(result.AlignedQuery)+result.ClippedSuffix != prefix+core+suffix {
+		t.Error("Clipped flanks and aligned query do not reassemble the query")
+	}
+
+	// A fully aligned query has nothing clipped
+	full := SmithWaterman(core, core)
+	if full.ClippedPrefix != "" || full.ClippedSuffix != "" {
+		t.Errorf("Expected no clipping, got %q and %q", full.ClippedPrefix, full.ClippedSuffix)
+	}
+}
+
+// TestMaxAlignmentLength checks the bound and that real alignments stay within it
+func TestMaxAlignmentLength(t *testing.T) {
+	testCases := []struct {
+		queryLen, refLen, expected int
+	}{
+		{0, 0, 0},
+		{7, 0, 7},
+		{7, 9, 16},
+		{1000, 1, 1001},
+	}
+
+	for _, tc := range testCases {
+		if length := MaxAlignmentLength(tc.queryLen, tc.refLen); length != tc.expected {
+			t.Errorf("MaxAlignmentLength(%d, %d) = %d, expected %d", tc.queryLen, tc.refLen, length, tc.expected)
+		}
+	}
+
+	query, reference := "GATTACAGATTACA", "GATTTACAGATACA"
+	if result := SmithWaterman(query, reference); len(result.AlignedQuery) > MaxAlignmentLength(len(query), len(reference)) {
+		t.Errorf("Alignment of %d columns exceeds the bound", len(result.AlignedQuery))
+	}
+}
+
+// TestNoAlignment checks that dissimilar sequences are flagged on every alignment path
+func TestNoAlignment(t *testing.T) {
+	query := strings.Repeat("A", 60)
+	reference := strings.Repeat("C", 60)
+
+	sequential := SmithWaterman(query, reference)
+	if !sequential.NoAlignment || sequential.AlignedQuery != "" {
+		t.Errorf("Sequential: expected NoAlignment with an empty alignment, got %t", sequential.NoAlignment)
+	}
+
+	parallel := ParallelSmithWaterman(query, reference, 4)
+	if !parallel.NoAlignment || parallel.AlignedQuery != "" {
+		t.Errorf("Parallel: expected NoAlignment with an empty alignment, got %t", parallel.NoAlignment)
+	}
+
+	// Short inputs take the sequential fallback inside the parallel aligner
+	if short := ParallelSmithWaterman("AAAA", "CCCC", 4); !short.NoAlignment {
+		t.Error("Parallel fallback: expected NoAlignment")
+	}
+
+	aligner := NewParallelAligner(2)
+	defer aligner.Close()
+	if pooled := aligner.Align(query, reference); !pooled.NoAlignment {
+		t.Error("ParallelAligner: expected NoAlignment")
+	}
+
+	if tiled := TiledParallelSmithWaterman(query, reference, 16, 2); !tiled.NoAlignment {
+		t.Error("Tiled: expected NoAlignment")
+	}
+
+	if affine := SmithWatermanAffine(query, reference, -3, -1); !affine.NoAlignment {
+		t.Error("Affine: expected NoAlignment")
+	}
+
+	// A single shared base is enough for an alignment
+	if SmithWaterman("AAAG", "CCGC").NoAlignment || ParallelSmithWaterman(query+"G", reference+"G", 4).NoAlignment {
+		t.Error("Expected an alignment when the sequences share a base")
+	}
+}
+
+// TestSmithWatermanEndBonus checks that the end bonus prefers a dovetail overlap
+// over an internal match with a higher raw score
+func TestSmithWatermanEndBonus(t *testing.T) {
+	// The query's last six bases overlap the start of the reference (raw score 12),
+	// while a ten-base block matches inside both sequences (raw score 20)
+	query := "TTTTTTTTACGTACGTACTTTTTTTTGATTGA"
+	reference := "GATTGACCCCCCCCACGTACGTAC" + strings.Repeat("C", 24)
+
+	plain := SmithWatermanEndBonus(query, reference, 0)
+	if plain.AlignedQuery != "ACGTACGTAC" || plain.MaxScore != 20 {
+		t.Fatalf("Expected the internal match without a bonus, got %q with score %d", plain.AlignedQuery, plain.MaxScore)
+	}
+
+	bonus := SmithWatermanEndBonus(query, reference, 10)
+	if bonus.AlignedQuery != "GATTGA" || bonus.AlignedRef != "GATTGA" {
+		t.Errorf("Expected the dovetail overlap with a bonus, got %q/%q", bonus.AlignedQuery, bonus.AlignedRef)
+	}
+	if bonus.QueryEnd != len(query) {
+		t.Errorf("Expected the alignment to reach the end of the query, got QueryEnd %d", bonus.QueryEnd)
+	}
+	if bonus.MaxScore != 22 {
+		t.Errorf("Expected a score of 22 including the bonus, got %d", bonus.MaxScore)
+	}
+}
+
+// TestSmithWatermanMaxCell checks that the sequential result reports the max-score
+// cell and that it agrees with the parallel implementation
+func TestSmithWatermanMaxCell(t *testing.T) {
+	query := "TTGATTACATT"
+	reference := "CCCCGATTACACCCC"
+
+	result := SmithWaterman(query, reference)
+	if result.MaxRow != 9 || result.MaxCol != 11 {
+		t.Errorf("Expected the max score at [9,11], got [%d,%d]", result.MaxRow, result.MaxCol)
+	}
+	if result.ScoreMatrix[result.MaxRow][result.MaxCol] != result.MaxScore {
+		t.Errorf("Expected cell [%d,%d] to hold the max score %d", result.MaxRow, result.MaxCol, result.MaxScore)
+	}
+
+	parallel := ParallelSmithWaterman(query, reference, 2)
+	if parallel.MaxRow != result.MaxRow || parallel.MaxCol != result.MaxCol {
+		t.Errorf("Expected parallel to agree on [%d,%d], got [%d,%d]",
+			result.MaxRow, result.MaxCol, parallel.MaxRow, parallel.MaxCol)
+	}
+}
+
+// TestSmithWatermanEarlyExit checks that abandoning the fill never changes the
+// outcome: results reaching minScore are identical to SmithWaterman, and the rest
+// are reported as unreachable
+func TestSmithWatermanEarlyExit(t *testing.T) {
+	query := "GATTACAGATTACA"
+
+	result, ok := SmithWatermanEarlyExit(query, "CCGATTACAGATTACACC", 20)
+	if want := SmithWaterman(query, "CCGATTACAGATTACACC"); !ok || result.MaxScore != want.MaxScore || result.AlignedQuery != want.AlignedQuery {
+		t.Errorf("Expected the full alignment with score %d, got ok=%v score %d", want.MaxScore, ok, result.MaxScore)
+	}
+
+	if _, ok := SmithWatermanEarlyExit(strings.Repeat("A", 200), strings.Repeat("C", 200), 10); ok {
+		t.Error("Expected unrelated sequences to fall short of the minimum score")
+	}
+	if _, ok := SmithWatermanEarlyExit("", "GATTACA", 1); ok {
+		t.Error("Expected an empty query to fall short of the minimum score")
+	}
+
+	// The decision must agree with a full alignment on random pairs near the threshold
+	for i := 0; i < 50; i++ {
+		q, r := generateRandomDNA(60), generateRandomDNA(60)
+		want := SmithWaterman(q, r)
+		for _, minScore := range []int{want.MaxScore - 1, want.MaxScore, want.MaxScore + 1} {
+			got, ok := SmithWatermanEarlyExit(q, r, minScore)
+			if ok != (want.MaxScore >= minScore) {
+				t.Errorf("minScore %d with best score %d: got ok=%v", minScore, want.MaxScore, ok)
+			}
+			if ok && got.MaxScore != want.MaxScore {
+				t.Errorf("minScore %d: got score %d, want %d", minScore, got.MaxScore, want.MaxScore)
+			}
+		}
+	}
+}
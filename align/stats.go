@@ -0,0 +1,183 @@
+package align
+
+// Identity returns the fraction of alignment columns in which the query and
+// reference bases are identical. Gap columns count toward the alignment length,
+// so an alignment with many gaps has a low identity even if every aligned base matches.
+//
+// Returns:
+//   - (float64): The identity between 0 and 1, or 0 for an empty alignment.
+func (r AlignmentResult) Identity() float64 {
+	if len(r.AlignedQuery) == 0 {
+		return 0
+	}
+
+	matches := 0
+	for i := 0; i < len(r.AlignedQuery) && i < len(r.AlignedRef); i++ {
+		if r.AlignedQuery[i] != '-' && r.AlignedQuery[i] == r.AlignedRef[i] {
+			matches++
+		}
+	}
+
+	return float64(matches) / float64(len(r.AlignedQuery))
+}
+
+// HasMatchRun reports whether the alignment contains at least minRun consecutive
+// matching columns. Requiring such a run filters out alignments whose score comes
+// from scattered matches rather than a contiguous stretch of similarity.
+//
+// Parameters:
+//   - minRun (int): The minimum number of consecutive matches required. Values of
+//     zero or less are always satisfied.
+//
+// Returns:
+//   - (bool): True if the alignment contains a run of at least minRun matches.
+func (r AlignmentResult) HasMatchRun(minRun int) bool {
+	if minRun <= 0 {
+		return true
+	}
+
+	run := 0
+	for i := 0; i < len(r.AlignedQuery) && i < len(r.AlignedRef); i++ {
+		if r.AlignedQuery[i] != '-' && r.AlignedQuery[i] == r.AlignedRef[i] {
+			run++
+			if run >= minRun {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	return false
+}
+
+// DiagonalOffset returns the diagonal of the dot plot on which the alignment
+// starts, RefStart - QueryStart. Zero means the query aligns at the same position
+// in the reference; a positive offset means the matching region sits further along
+// the reference, as happens when the reference carries an upstream insertion.
+//
+// Returns:
+//   - (int): The offset of the reference start relative to the query start.
+func (r AlignmentResult) DiagonalOffset() int {
+	return r.RefStart - r.QueryStart
+}
+
+// MutationCount returns the number of discrete mutation events in the alignment:
+// each mismatched column is one SNP, and each run of consecutive gaps on the same
+// side is one insertion or deletion, however many bases it spans. This matches how
+// mutation detection merges adjacent gap columns, and is a better summary of
+// divergence than the raw number of differing columns.
+//
+// Returns:
+//   - (int): The number of SNPs plus the number of indel events.
+func (r AlignmentResult) MutationCount() int {
+	count := 0
+	var previousGap byte // 'q' for a gap in the query, 'r' for a gap in the reference, 0 otherwise
+
+	for i := 0; i < len(r.AlignedQuery) && i < len(r.AlignedRef); i++ {
+		var gap byte
+		switch {
+		case r.AlignedQuery[i] == '-':
+			gap = 'q'
+		case r.AlignedRef[i] == '-':
+			gap = 'r'
+		case r.AlignedQuery[i] != r.AlignedRef[i]:
+			count++
+		}
+
+		// A gap column starts a new event unless it extends a run on the same side
+		if gap != 0 && gap != previousGap {
+			count++
+		}
+		previousGap = gap
+	}
+
+	return count
+}
+
+// SimilarityProfile returns the local identity around every alignment column: the
+// fraction of identical columns within a window centred on it. Near the ends of
+// the alignment the window is truncated rather than padded. Plotting or colouring
+// by the profile shows conserved and divergent regions at a glance.
+//
+// Parameters:
+//   - window (int): The number of columns in each window. Values below 1 are treated as 1.
+//
+// Returns:
+//   - ([]float64): One identity between 0 and 1 per alignment column.
+func (r AlignmentResult) SimilarityProfile(window int) []float64 {
+	length := min(len(r.AlignedQuery), len(r.AlignedRef))
+	if window < 1 {
+		window = 1
+	}
+
+	// matches[i] is the number of identical columns before column i
+	matches := make([]int, length+1)
+	for i := 0; i < length; i++ {
+		matches[i+1] = matches[i]
+		if r.AlignedQuery[i] != '-' && r.AlignedQuery[i] == r.AlignedRef[i] {
+			matches[i+1]++
+		}
+	}
+
+	profile := make([]float64, length)
+	for i := range profile {
+		start := max(0, i-window/2)
+		end := min(length, start+window)
+		start = max(0, end-window)
+		profile[i] = float64(matches[end]-matches[start]) / float64(end-start)
+	}
+
+	return profile
+}
+
+// Stats summarizes the columns of an alignment.
+type Stats struct {
+	Matches         int     `json:"matches"`         // Columns with identical bases
+	Mismatches      int     `json:"mismatches"`      // Columns with two different bases
+	QueryGaps       int     `json:"queryGaps"`       // Columns with a gap in the query
+	RefGaps         int     `json:"refGaps"`         // Columns with a gap in the reference
+	Length          int     `json:"length"`          // Total number of alignment columns
+	PercentIdentity float64 `json:"percentIdentity"` // Matches as a percentage of the gap-free columns
+	Coverage        float64 `json:"coverage"`        // Fraction of the query covered by the alignment
+}
+
+// AlignmentStats counts the matches, mismatches, and gaps in an alignment.
+// Unlike Identity, the percent identity leaves gap columns out of the denominator,
+// so it measures how similar the aligned bases are independently of indels.
+// Coverage is the aligned query span over the whole query, recovered from the
+// clipped bases either side of it.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to summarize.
+//
+// Returns:
+//   - (Stats): The column counts, percent identity (0 to 100), and coverage (0 to 1).
+//     Both ratios are 0 when their denominator is.
+func AlignmentStats(result AlignmentResult) Stats {
+	stats := Stats{Length: min(len(result.AlignedQuery), len(result.AlignedRef))}
+
+	for i := 0; i < stats.Length; i++ {
+		switch q, r := result.AlignedQuery[i], result.AlignedRef[i]; {
+		case q == '-':
+			stats.QueryGaps++
+		case r == '-':
+			stats.RefGaps++
+		case q == r:
+			stats.Matches++
+		default:
+			stats.Mismatches++
+		}
+	}
+
+	if aligned := stats.Matches + stats.Mismatches; aligned > 0 {
+		stats.PercentIdentity = 100 * float64(stats.Matches) / float64(aligned)
+	}
+
+	span := result.QueryEnd - result.QueryStart
+	if queryLen := len(result.ClippedPrefix) + span + len(result.ClippedSuffix); queryLen > 0 {
+		stats.Coverage = float64(span) / float64(queryLen)
+	}
+
+	return stats
+}
@@ -0,0 +1,160 @@
+package align
+
+import "testing"
+
+// TestIdentity checks identity on perfect, partial, and empty alignments
+func TestIdentity(t *testing.T) {
+	testCases := []struct {
+		result   AlignmentResult
+		expected float64
+	}{
+		{AlignmentResult{AlignedQuery: "GATTACA", AlignedRef: "GATTACA"}, 1.0},
+		{AlignmentResult{AlignedQuery: "GATT", AlignedRef: "GACT"}, 0.75},
+		{AlignmentResult{AlignedQuery: "GA-T", AlignedRef: "GATT"}, 0.75},
+		{AlignmentResult{}, 0},
+	}
+
+	for _, tc := range testCases {
+		if identity := tc.result.Identity(); identity != tc.expected {
+			t.Errorf("Identity of %s/%s = %f, expected %f",
+				tc.result.AlignedQuery, tc.result.AlignedRef, identity, tc.expected)
+		}
+	}
+}
+
+// TestHasMatchRun checks that scattered matches do not satisfy the run requirement
+func TestHasMatchRun(t *testing.T) {
+	// Every other column mismatches, so the longest run of matches is 1
+	scattered := AlignmentResult{AlignedQuery: "GATCACGAT-CA", AlignedRef: "GCTGAGGCTACC"}
+	if scattered.HasMatchRun(2) {
+		t.Error("Expected no run of 2 matches in an alignment of scattered matches")
+	}
+	if !scattered.HasMatchRun(1) {
+		t.Error("Expected a run of 1 match")
+	}
+
+	// A gap breaks a run of matches
+	gapped := AlignmentResult{AlignedQuery: "GAT-TACA", AlignedRef: "GATTTACA"}
+	if gapped.HasMatchRun(5) {
+		t.Error("Expected the gap to break the run of matches")
+	}
+	if !gapped.HasMatchRun(4) {
+		t.Error("Expected a run of 4 matches after the gap")
+	}
+
+	// A non-positive requirement is always met
+	if !(AlignmentResult{}).HasMatchRun(0) {
+		t.Error("Expected a minimum run of 0 to always be satisfied")
+	}
+}
+
+// TestDiagonalOffset checks the offset of a query matching a shifted region of the reference
+func TestDiagonalOffset(t *testing.T) {
+	core := "GATTACAGATCAGATAGATACA"
+
+	// The reference carries five extra bases upstream of the shared region
+	result := SmithWaterman("CC"+core, "CCTTTTT"+core)
+	if offset := result.DiagonalOffset(); offset != 5 {
+		t.Errorf("Expected offset 5, got %d (query start %d, reference start %d)", offset, result.QueryStart, result.RefStart)
+	}
+
+	// The query carries the extra bases instead
+	result = SmithWaterman("TTTTTTT"+core, core)
+	if offset := result.DiagonalOffset(); offset != -7 {
+		t.Errorf("Expected offset -7, got %d", offset)
+	}
+
+	if offset := SmithWaterman(core, core).DiagonalOffset(); offset != 0 {
+		t.Errorf("Expected offset 0 for identical sequences, got %d", offset)
+	}
+}
+
+// TestMutationCount checks that indels count once per event while SNPs count per base
+func TestMutationCount(t *testing.T) {
+	testCases := []struct {
+		result   AlignmentResult
+		expected int
+	}{
+		// One 3bp deletion
+		{AlignmentResult{AlignedQuery: "GAT---ACA", AlignedRef: "GATTTTACA"}, 1},
+		// Three SNPs
+		{AlignmentResult{AlignedQuery: "GATCCCACA", AlignedRef: "GATTTTACA"}, 3},
+		// A deletion directly followed by an insertion is two events
+		{AlignmentResult{AlignedQuery: "GA--TTACA", AlignedRef: "GATT--ACA"}, 2},
+		// Two separate deletions and a SNP
+		{AlignmentResult{AlignedQuery: "G-TTCC-A", AlignedRef: "GATTACAA"}, 3},
+		{AlignmentResult{AlignedQuery: "GATTACA", AlignedRef: "GATTACA"}, 0},
+	}
+
+	for _, tc := range testCases {
+		if count := tc.result.MutationCount(); count != tc.expected {
+			t.Errorf("MutationCount of %s/%s = %d, expected %d",
+				tc.result.AlignedQuery, tc.result.AlignedRef, count, tc.expected)
+		}
+	}
+}
+
+// TestSimilarityProfile checks the local identity across a conserved and a divergent region
+func TestSimilarityProfile(t *testing.T) {
+	// Ten identical columns followed by ten mismatches
+	result := AlignmentResult{
+		AlignedQuery: "GATTACAGAT" + "AAAAAAAAAA",
+		AlignedRef:   "GATTACAGAT" + "CCCCCCCCCC",
+	}
+
+	profile := result.SimilarityProfile(4)
+	if len(profile) != 20 {
+		t.Fatalf("Expected 20 values, got %d", len(profile))
+	}
+	if profile[0] != 1 || profile[5] != 1 || profile[14] != 0 || profile[19] != 0 {
+		t.Errorf("Expected identity 1 in the conserved region and 0 in the divergent one, got %v", profile)
+	}
+	if profile[10] != 0.5 {
+		t.Errorf("Expected identity 0.5 at the boundary, got %f", profile[10])
+	}
+
+	// A window of 1 is the per-column identity
+	for i, value := range result.SimilarityProfile(1) {
+		if expected := map[bool]float64{true: 1, false: 0}[i < 10]; value != expected {
+			t.Errorf("Column %d: identity %f, expected %f", i, value, expected)
+		}
+	}
+
+	// A window wider than the alignment gives the overall identity everywhere
+	for _, value := range result.SimilarityProfile(100) {
+		if value != result.Identity() {
+			t.Errorf("Expected the overall identity %f, got %f", result.Identity(), value)
+		}
+	}
+}
+
+// TestAlignmentStats checks the column counts, that gaps are left out of the
+// percent identity, and that coverage accounts for the clipped query bases
+func TestAlignmentStats(t *testing.T) {
+	result := SmithWaterman("TTTTGATTACAGGCATCTTTT", "CCGATCACAGGCCATCCC")
+	stats := AlignmentStats(result)
+
+	if stats.Length != len(result.AlignedQuery) {
+		t.Errorf("Expected length %d, got %d", len(result.AlignedQuery), stats.Length)
+	}
+	if total := stats.Matches + stats.Mismatches + stats.QueryGaps + stats.RefGaps; total != stats.Length {
+		t.Errorf("Expected the counts to sum to the length %d, got %d", stats.Length, total)
+	}
+
+	gapped := AlignmentStats(AlignmentResult{
+		AlignedQuery:  "GAT-TACA",
+		AlignedRef:    "GATTTGCA",
+		QueryStart:    2,
+		QueryEnd:      9,
+		ClippedPrefix: "CC",
+		ClippedSuffix: "C",
+	})
+	want := Stats{Matches: 6, Mismatches: 1, QueryGaps: 1, Length: 8, PercentIdentity: 600.0 / 7, Coverage: 0.7}
+	if gapped != want {
+		t.Errorf("Expected %+v, got %+v", want, gapped)
+	}
+
+	if empty := AlignmentStats(AlignmentResult{}); empty != (Stats{}) {
+		t.Errorf("Expected zero stats for an empty alignment, got %+v", empty)
+	}
+}
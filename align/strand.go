@@ -0,0 +1,25 @@
+package align
+
+import "pgfp/data"
+
+// SmithWatermanBestStrand aligns both the query and its reverse complement against
+// the reference and returns the better of the two alignments. This handles reads
+// that may come from either strand. On a tie the forward strand is kept.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The higher-scoring alignment. When the reverse strand wins,
+//     AlignedQuery and the query coordinates refer to the reverse complement of query.
+//   - (bool): True if the reverse complement scored higher.
+func SmithWatermanBestStrand(query, reference string) (AlignmentResult, bool) {
+	forward := SmithWaterman(query, reference)
+	reverse := SmithWaterman(data.ReverseComplement(query), reference)
+
+	if reverse.MaxScore > forward.MaxScore {
+		return reverse, true
+	}
+	return forward, false
+}
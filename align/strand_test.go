@@ -0,0 +1,30 @@
+package align
+
+import (
+	"testing"
+
+	"pgfp/data"
+)
+
+// TestSmithWatermanBestStrand checks that reads from either strand align to the reference
+func TestSmithWatermanBestStrand(t *testing.T) {
+	reference := "TTGACCGATGGTACCAGTTCAGGATCCTGA"
+	read := "GATGGTACCAGTTCAGG"
+
+	result, reversed := SmithWatermanBestStrand(read, reference)
+	if reversed {
+		t.Error("Expected the forward strand to win for a forward read")
+	}
+	if result.AlignedQuery != read {
+		t.Errorf("Expected the whole read to align, got %q", result.AlignedQuery)
+	}
+
+	result, reversed = SmithWatermanBestStrand(data.ReverseComplement(read), reference)
+	if !reversed {
+		t.Error("Expected the reverse strand to win for a reverse-complemented read")
+	}
+	if result.AlignedQuery != read || result.RefStart != 6 || result.RefEnd != 23 {
+		t.Errorf("Expected the read to align at [6,23) on the reverse strand, got %q at [%d,%d)",
+			result.AlignedQuery, result.RefStart, result.RefEnd)
+	}
+}
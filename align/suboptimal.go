@@ -0,0 +1,60 @@
+package align
+
+import "sort"
+
+// SmithWatermanAll reports every local alignment scoring at least minScore, using
+// the Waterman-Eggert method. After each alignment is found, the cells on its path
+// are masked to zero and the matrix is refilled, so the next alignment cannot reuse
+// any aligned pair of an earlier one. This finds each copy of a repeated motif in
+// the reference rather than only the best one.
+//
+// Each pass refills the whole matrix, so the cost is one SmithWaterman per result.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - minScore (int): The lowest score to report. Values below 1 are treated as 1.
+//
+// Returns:
+//   - ([]AlignmentResult): The alignments sorted by descending score; the first is the
+//     one SmithWaterman returns. Each ScoreMatrix is the masked matrix the alignment
+//     was found in. Empty if no alignment reaches minScore.
+func SmithWatermanAll(query, reference string, minScore int) []AlignmentResult {
+	minScore = max(minScore, 1)
+
+	masked := make([][]bool, len(query)+1)
+	for i := range masked {
+		masked[i] = make([]bool, len(reference)+1)
+	}
+
+	results := []AlignmentResult{}
+	for {
+		result, _ := smithWaterman(query, reference, alignOptions{scorer: defaultScorer(), masked: masked})
+		if result.NoAlignment || result.MaxScore < minScore {
+			break
+		}
+		results = append(results, result)
+		maskPath(masked, result)
+	}
+
+	// Masking only lowers scores, so the order is already descending; keep it explicit
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].MaxScore > results[b].MaxScore
+	})
+
+	return results
+}
+
+// maskPath marks every cell the alignment's traceback passed through.
+func maskPath(masked [][]bool, result AlignmentResult) {
+	row, col := result.QueryStart, result.RefStart
+	for k := 0; k < len(result.AlignedQuery); k++ {
+		if result.AlignedQuery[k] != '-' {
+			row++
+		}
+		if result.AlignedRef[k] != '-' {
+			col++
+		}
+		masked[row][col] = true
+	}
+}
@@ -0,0 +1,41 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanAll checks that every copy of a repeated motif is reported, best first
+func TestSmithWatermanAll(t *testing.T) {
+	// One exact copy of the motif and one with a mismatch, separated by unrelated bases
+	motif := "GATTACAGGCT"
+	variant := "GATTACTGGCT"
+	reference := strings.Repeat("C", 10) + variant + strings.Repeat("C", 15) + motif + strings.Repeat("C", 10)
+
+	results := SmithWatermanAll(motif, reference, 12)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 alignments, got %d", len(results))
+	}
+
+	// The exact copy comes first and matches the single best alignment
+	best := SmithWaterman(motif, reference)
+	if results[0].MaxScore != best.MaxScore || results[0].RefStart != best.RefStart {
+		t.Errorf("First result scored %d at %d, want %d at %d", results[0].MaxScore, results[0].RefStart, best.MaxScore, best.RefStart)
+	}
+	if results[0].RefStart != 36 || results[1].RefStart != 10 {
+		t.Errorf("Expected the copies at reference positions 36 and 10, got %d and %d", results[0].RefStart, results[1].RefStart)
+	}
+	if results[1].MaxScore != 19 {
+		t.Errorf("Expected the variant to score 19, got %d", results[1].MaxScore)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].MaxScore > results[i-1].MaxScore {
+			t.Errorf("Results are not sorted by descending score")
+		}
+	}
+
+	// A threshold above the variant's score keeps only the exact copy
+	if results := SmithWatermanAll(motif, reference, 20); len(results) != 1 {
+		t.Errorf("Expected 1 alignment scoring at least 20, got %d", len(results))
+	}
+}
@@ -0,0 +1,85 @@
+package align
+
+import "testing"
+
+// TestPositives checks that a conservative substitution counts as a positive but not an identity
+func TestPositives(t *testing.T) {
+	// A fragment of BLOSUM62: I and V are interchangeable, I and K are not
+	sub := SubstitutionMatrix{
+		'I': {'I': 4, 'V': 3, 'K': -3, 'L': 2},
+		'V': {'I': 3, 'V': 4, 'K': -2, 'L': 1},
+		'K': {'I': -3, 'V': -2, 'K': 5, 'L': -2},
+		'L': {'I': 2, 'V': 1, 'K': -2, 'L': 4},
+	}
+
+	result := AlignmentResult{
+		AlignedQuery: "KIVLK-L",
+		AlignedRef:   "KVVLIKL",
+	}
+
+	// K/K, V/V, L/L, L/L are identities; I/V is conservative; K/I scores negative
+	if got := result.Positives(sub); got != 5 {
+		t.Errorf("Positives() = %d, want 5", got)
+	}
+
+	if got := AlignmentStats(result).Matches; got != 4 {
+		t.Errorf("Expected 4 identities, got %d", got)
+	}
+
+	// With BLOSUM62, Y/F and I/L are conservative: 20 identities but 22 positives
+	protein := SmithWatermanMatrix("MKTAYIAKQRQISFVKSHFSRQ", "MKTAFIAKQRQLSFVKSHFSRQ", BLOSUM62(), -8)
+	if got := AlignmentStats(protein).Matches; got != 20 {
+		t.Errorf("Expected 20 identities, got %d", got)
+	}
+	if got := protein.Positives(BLOSUM62()); got != 22 {
+		t.Errorf("Positives(BLOSUM62()) = %d, want 22", got)
+	}
+
+	// Residues missing from the matrix score 0, which is not positive
+	if got := (AlignmentResult{AlignedQuery: "W", AlignedRef: "W"}).Positives(sub); got != 0 {
+		t.Errorf("Expected no positives for residues outside the matrix, got %d", got)
+	}
+}
+
+// TestBLOSUM62 checks known entries, symmetry, and coverage of the 20 amino acids plus X and '*'
+func TestBLOSUM62(t *testing.T) {
+	sm := BLOSUM62()
+
+	for _, tc := range []struct {
+		a, b byte
+		want int
+	}{{'W', 'W', 11}, {'C', 'C', 9}, {'I', 'V', 3}, {'A', 'R', -1}, {'D', 'E', 2}, {'X', 'A', 0}, {'*', '*', 1}, {'*', 'A', -4}} {
+		if got := sm.Score(tc.a, tc.b); got != tc.want {
+			t.Errorf("Score(%c, %c) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	residues := "ACDEFGHIKLMNPQRSTVWYX*"
+	for i := 0; i < len(residues); i++ {
+		for j := 0; j < len(residues); j++ {
+			a, b := residues[i], residues[j]
+			if _, ok := sm[a][b]; !ok {
+				t.Errorf("Missing entry for (%c, %c)", a, b)
+			}
+			if sm.Score(a, b) != sm.Score(b, a) {
+				t.Errorf("Score(%c, %c) = %d but Score(%c, %c) = %d", a, b, sm.Score(a, b), b, a, sm.Score(b, a))
+			}
+		}
+	}
+}
+
+// TestSmithWatermanMatrix checks that protein alignment uses the matrix scores
+func TestSmithWatermanMatrix(t *testing.T) {
+	// HEAGAWGHEE / PAWHEAE is the classic textbook example: the best local alignment
+	// under BLOSUM62 with a gap score of -8 is AWGHE against AW-HE
+	result := SmithWatermanMatrix("HEAGAWGHEE", "PAWHEAE", BLOSUM62(), -8)
+
+	if result.AlignedQuery != "AWGHE" || result.AlignedRef != "AW-HE" {
+		t.Errorf("Got %s / %s, want AWGHE / AW-HE", result.AlignedQuery, result.AlignedRef)
+	}
+
+	// A + W + H + E - 8 = 4 + 11 + 8 + 5 - 8
+	if result.MaxScore != 20 {
+		t.Errorf("MaxScore = %d, want 20", result.MaxScore)
+	}
+}
@@ -0,0 +1,41 @@
+package align
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTiledParallelSmithWaterman checks that the tiled fill matches the sequential algorithm exactly
+func TestTiledParallelSmithWaterman(t *testing.T) {
+	testCases := []struct {
+		query     string
+		reference string
+	}{
+		{"GATTACA", "GATTACA"},
+		{"GATTACAGATTACA", "GATTTACAGATACA"},
+		{"", "GATTACA"},
+		{generateRandomDNA(300), generateRandomDNA(170)},
+		{"TTGATTACACC" + generateRandomDNA(150), generateRandomDNA(90) + "GATTACA"},
+	}
+
+	for _, tc := range testCases {
+		expected := SmithWaterman(tc.query, tc.reference)
+
+		for _, blockSize := range []int{1, 7, 32, 1000, 0} {
+			for _, workers := range []int{1, 3, 0} {
+				result := TiledParallelSmithWaterman(tc.query, tc.reference, blockSize, workers)
+
+				if result.MaxScore != expected.MaxScore ||
+					result.AlignedQuery != expected.AlignedQuery || result.AlignedRef != expected.AlignedRef ||
+					result.QueryStart != expected.QueryStart || result.QueryEnd != expected.QueryEnd ||
+					result.RefStart != expected.RefStart || result.RefEnd != expected.RefEnd {
+					t.Errorf("Block size %d, %d workers: result differs from sequential for %d x %d bases",
+						blockSize, workers, len(tc.query), len(tc.reference))
+				}
+				if !reflect.DeepEqual(result.ScoreMatrix, expected.ScoreMatrix) {
+					t.Errorf("Block size %d, %d workers: score matrix differs from sequential", blockSize, workers)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package align
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Tile is a window cut from a longer sequence.
+type Tile struct {
+	Start int    // Offset of the tile in the original sequence (0-based)
+	Seq   string // The bases covered by the tile
+}
+
+// TileHit is the best local alignment of one query tile against the reference,
+// expressed in the coordinates of the original (untiled) query.
+type TileHit struct {
+	Score        int    // Alignment score
+	AlignedQuery string // The aligned query bases
+	AlignedRef   string // The aligned reference bases
+	QueryStart   int    // Start of the hit in the original query (inclusive)
+	QueryEnd     int    // End of the hit in the original query (exclusive)
+	RefStart     int    // Start of the hit in the reference (inclusive)
+	RefEnd       int    // End of the hit in the reference (exclusive)
+}
+
+// TileSequence splits a sequence into overlapping windows.
+// Consecutive tiles start tileSize-overlap bases apart, and the final tile is
+// shortened if needed so that every base of the sequence is covered.
+//
+// Parameters:
+//   - seq (string): The sequence to split.
+//   - tileSize (int): The length of each tile (must be positive).
+//   - overlap (int): The number of bases shared by neighbouring tiles (0 <= overlap < tileSize).
+//
+// Returns:
+//   - ([]Tile): The tiles in order of their start position, or nil if the parameters are invalid.
+func TileSequence(seq string, tileSize, overlap int) []Tile {
+	if tileSize <= 0 || overlap < 0 || overlap >= tileSize {
+		return nil
+	}
+
+	step := tileSize - overlap
+	var tiles []Tile
+	for start := 0; start < len(seq); start += step {
+		end := start + tileSize
+		if end > len(seq) {
+			end = len(seq)
+		}
+		tiles = append(tiles, Tile{Start: start, Seq: seq[start:end]})
+
+		// Stop once a tile reaches the end of the sequence
+		if end == len(seq) {
+			break
+		}
+	}
+
+	return tiles
+}
+
+// AlignTiles screens a long query against a reference by tiling the query and
+// aligning every tile concurrently. Hits are translated back to original query
+// coordinates and merged: hits found by several overlapping tiles are reported
+// once, and partial hits contained in a better one are dropped. A hit that spans a
+// tile boundary is still found whole as long as it fits inside the overlap.
+//
+// Parameters:
+//   - query (string): The long DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - tileSize (int): The length of each query tile.
+//   - overlap (int): The number of bases shared by neighbouring tiles.
+//   - numWorkers (int): Maximum number of concurrent alignments (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - ([]TileHit): The merged hits ordered by query position, then reference position.
+func AlignTiles(query, reference string, tileSize, overlap, numWorkers int) []TileHit {
+	tiles := TileSequence(query, tileSize, overlap)
+	if len(tiles) == 0 {
+		return nil
+	}
+
+	// If the number of workers is not specified, use the number of CPUs
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	// Limit workers to number of tiles
+	if numWorkers > len(tiles) {
+		numWorkers = len(tiles)
+	}
+
+	// Feed tile indices to a fixed pool of workers, so only numWorkers
+	// goroutines exist however many tiles the query is cut into
+	hits := make([]TileHit, len(tiles))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for index := range jobs {
+				tile := tiles[index]
+				result := SmithWaterman(tile.Seq, reference)
+				hits[index] = TileHit{
+					Score:        result.MaxScore,
+					AlignedQuery: result.AlignedQuery,
+					AlignedRef:   result.AlignedRef,
+					QueryStart:   tile.Start + result.QueryStart,
+					QueryEnd:     tile.Start + result.QueryEnd,
+					RefStart:     result.RefStart,
+					RefEnd:       result.RefEnd,
+				}
+			}
+		}()
+	}
+
+	for i := range tiles {
+		jobs <- i
+	}
+	close(jobs)
+
+	// Wait for all tiles to be aligned
+	wg.Wait()
+
+	return mergeTileHits(hits)
+}
+
+// mergeTileHits removes empty, duplicate, and contained hits and sorts the rest.
+func mergeTileHits(hits []TileHit) []TileHit {
+	// Consider the best hits first so contained partial hits can be discarded
+	sort.SliceStable(hits, func(a, b int) bool {
+		return hits[a].Score > hits[b].Score
+	})
+
+	var merged []TileHit
+	for _, hit := range hits {
+		if hit.Score <= 0 {
+			continue
+		}
+
+		contained := false
+		for _, kept := range merged {
+			if hit.QueryStart >= kept.QueryStart && hit.QueryEnd <= kept.QueryEnd &&
+				hit.RefStart >= kept.RefStart && hit.RefEnd <= kept.RefEnd {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			merged = append(merged, hit)
+		}
+	}
+
+	sort.Slice(merged, func(a, b int) bool {
+		if merged[a].QueryStart != merged[b].QueryStart {
+			return merged[a].QueryStart < merged[b].QueryStart
+		}
+		return merged[a].RefStart < merged[b].RefStart
+	})
+
+	return merged
+}
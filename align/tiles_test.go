@@ -0,0 +1,62 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTileSequence checks tile boundaries and coverage
+func TestTileSequence(t *testing.T) {
+	seq := "ACGTACGTACGT" // 12 bases
+	tiles := TileSequence(seq, 5, 2)
+
+	expectedStarts := []int{0, 3, 6, 9}
+	if len(tiles) != len(expectedStarts) {
+		t.Fatalf("Expected %d tiles, got %d: %+v", len(expectedStarts), len(tiles), tiles)
+	}
+	for i, tile := range tiles {
+		if tile.Start != expectedStarts[i] {
+			t.Errorf("Tile %d starts at %d, expected %d", i, tile.Start, expectedStarts[i])
+		}
+		if tile.Seq != seq[tile.Start:tile.Start+len(tile.Seq)] {
+			t.Errorf("Tile %d sequence %s does not match the original", i, tile.Seq)
+		}
+	}
+
+	// The last tile must reach the end of the sequence
+	last := tiles[len(tiles)-1]
+	if last.Start+len(last.Seq) != len(seq) {
+		t.Errorf("Tiles do not cover the end of the sequence: last tile %+v", last)
+	}
+
+	// Invalid parameters
+	if TileSequence(seq, 0, 0) != nil || TileSequence(seq, 5, 5) != nil || TileSequence(seq, 5, -1) != nil {
+		t.Error("Expected nil for invalid tile parameters")
+	}
+}
+
+// TestAlignTilesBoundaryHit checks that a hit spanning a tile boundary is found whole
+func TestAlignTilesBoundaryHit(t *testing.T) {
+	// The motif uses only G and T so it cannot extend into the A/C flanks
+	motif := "GTTGTGGTTGGTGTTTGGTGTGGTTGTGTG"
+	query := strings.Repeat("A", 35) + motif + strings.Repeat("A", 35) // motif at 35-65
+	reference := strings.Repeat("C", 20) + motif + strings.Repeat("C", 20)
+
+	// Tiles of 50 with 40 overlap: the motif crosses the boundary of the first
+	// tile at position 50, but fits entirely in the tile starting at 30
+	hits := AlignTiles(query, reference, 50, 40, 4)
+	if len(hits) != 1 {
+		t.Fatalf("Expected a single merged hit, got %d: %+v", len(hits), hits)
+	}
+
+	hit := hits[0]
+	if hit.QueryStart != 35 || hit.QueryEnd != 65 {
+		t.Errorf("Expected query coordinates [35,65), got [%d,%d)", hit.QueryStart, hit.QueryEnd)
+	}
+	if hit.RefStart != 20 || hit.RefEnd != 50 {
+		t.Errorf("Expected reference coordinates [20,50), got [%d,%d)", hit.RefStart, hit.RefEnd)
+	}
+	if hit.Score != len(motif)*MatchScore {
+		t.Errorf("Expected full motif score %d, got %d", len(motif)*MatchScore, hit.Score)
+	}
+}
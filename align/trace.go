@@ -0,0 +1,52 @@
+package align
+
+import "fmt"
+
+// TraceMove is the direction of one traceback step.
+type TraceMove string
+
+// Traceback moves
+const (
+	MoveDiagonal TraceMove = "diagonal" // Query and reference bases aligned together
+	MoveUp       TraceMove = "up"       // Query base aligned to a gap in the reference
+	MoveLeft     TraceMove = "left"     // Reference base aligned to a gap in the query
+)
+
+// TraceStep records one decision made while tracing back through the score matrix.
+type TraceStep struct {
+	Row   int       `json:"row"`   // Matrix row of the cell being traced (query position + 1)
+	Col   int       `json:"col"`   // Matrix column of the cell being traced (reference position + 1)
+	Score int       `json:"score"` // Score of the cell
+	Diag  int       `json:"diag"`  // Score reached by the diagonal move
+	Up    int       `json:"up"`    // Score reached by the move from the cell above
+	Left  int       `json:"left"`  // Score reached by the move from the cell to the left
+	Move  TraceMove `json:"move"`  // The move taken; empty if none reproduces the score
+}
+
+// String formats the step as a single log line.
+func (s TraceStep) String() string {
+	return fmt.Sprintf("[%d,%d] score=%d diag=%d up=%d left=%d -> %s",
+		s.Row, s.Col, s.Score, s.Diag, s.Up, s.Left, s.Move)
+}
+
+// SmithWatermanTrace performs the same alignment as SmithWaterman and also returns
+// every traceback decision, from the maximum-scoring cell back to the start of the
+// alignment. A move is taken when its candidate score equals the cell's score, and
+// ties are broken toward the diagonal, then up, then left. Inspecting the candidates
+// shows why an expected alignment lost out to the one returned.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): A struct containing the alignment score matrix, maximum score, and aligned sequences.
+//   - ([]TraceStep): One step per alignment column, in traceback order (last column first).
+func SmithWatermanTrace(query, reference string) (AlignmentResult, []TraceStep) {
+	var steps []TraceStep
+	result, _ := smithWaterman(query, reference, alignOptions{
+		scorer: defaultScorer(),
+		onStep: func(step TraceStep) { steps = append(steps, step) },
+	})
+	return result, steps
+}
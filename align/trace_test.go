@@ -0,0 +1,47 @@
+package align
+
+import "testing"
+
+// TestSmithWatermanTrace checks that the trace has one step per column and explains each column
+func TestSmithWatermanTrace(t *testing.T) {
+	result, steps := SmithWatermanTrace("GATTACAGATTACA", "GATTTACAGATACA")
+
+	if len(steps) != len(result.AlignedQuery) {
+		t.Fatalf("Expected %d trace steps, got %d", len(result.AlignedQuery), len(steps))
+	}
+
+	// The trace starts at the maximum-scoring cell
+	if steps[0].Row != result.QueryEnd || steps[0].Col != result.RefEnd || steps[0].Score != result.MaxScore {
+		t.Errorf("Trace starts at [%d,%d] with score %d, expected [%d,%d] with score %d",
+			steps[0].Row, steps[0].Col, steps[0].Score, result.QueryEnd, result.RefEnd, result.MaxScore)
+	}
+
+	// Steps run from the last column to the first, and each move matches its column
+	for k, step := range steps {
+		column := len(result.AlignedQuery) - 1 - k
+		var expected TraceMove
+		switch {
+		case result.AlignedRef[column] == '-':
+			expected = MoveUp
+		case result.AlignedQuery[column] == '-':
+			expected = MoveLeft
+		default:
+			expected = MoveDiagonal
+		}
+
+		if step.Move != expected {
+			t.Errorf("Step %d (%s): move %s, expected %s", k, step, step.Move, expected)
+		}
+	}
+
+	// Identical sequences trace straight down the diagonal
+	_, steps = SmithWatermanTrace("GATTACA", "GATTACA")
+	if len(steps) != 7 {
+		t.Fatalf("Expected 7 trace steps, got %d", len(steps))
+	}
+	for _, step := range steps {
+		if step.Move != MoveDiagonal || step.Diag != step.Score {
+			t.Errorf("Unexpected step %s", step)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package align
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidDNA is returned when a sequence contains anything other than A, C, G, or T.
+var ErrInvalidDNA = errors.New("invalid DNA sequence")
+
+// ValidateASCII checks that a sequence contains only ASCII bytes.
+// The aligners index sequences byte by byte, so a multi-byte UTF-8 character
+// (for example a smart quote or accented letter pasted from a document) would be
+// split across several matrix cells and silently corrupt the alignment.
+//
+// Parameters:
+//   - name (string): A label for the sequence used in the error message (e.g. "query").
+//   - seq (string): The sequence to check.
+//
+// Returns:
+//   - (error): nil if the sequence is plain ASCII, otherwise an error naming the
+//     offending character and its byte offset.
+func ValidateASCII(name, seq string) error {
+	for i := 0; i < len(seq); i++ {
+		if seq[i] < utf8.RuneSelf {
+			continue
+		}
+
+		r, _ := utf8.DecodeRuneInString(seq[i:])
+		if r == utf8.RuneError {
+			return fmt.Errorf("%s sequence contains invalid byte 0x%02x at position %d; sequences must be plain ASCII", name, seq[i], i)
+		}
+		return fmt.Errorf("%s sequence contains non-ASCII character %q at position %d; sequences must be plain ASCII", name, r, i)
+	}
+
+	return nil
+}
+
+// ValidateDNA checks that a sequence is a non-empty run of DNA bases. Upper- and
+// lowercase A, C, G, and T are accepted; anything else, including gaps, digits,
+// and IUPAC ambiguity codes, is rejected.
+//
+// Parameters:
+//   - name (string): A label for the sequence used in the error message (e.g. "query").
+//   - seq (string): The sequence to check.
+//
+// Returns:
+//   - (error): nil if the sequence is valid, otherwise an error wrapping ErrInvalidDNA
+//     that names the sequence, the offending character, and its byte offset.
+func ValidateDNA(name, seq string) error {
+	if seq == "" {
+		return fmt.Errorf("%w: %s sequence is empty", ErrInvalidDNA, name)
+	}
+
+	// Report non-ASCII characters whole rather than as their first byte
+	if err := ValidateASCII(name, seq); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDNA, err)
+	}
+
+	for i := 0; i < len(seq); i++ {
+		switch seq[i] {
+		case 'A', 'C', 'G', 'T', 'a', 'c', 'g', 't':
+		default:
+			return fmt.Errorf("%w: %s sequence contains %q at position %d; use only A, C, G, T",
+				ErrInvalidDNA, name, seq[i], i)
+		}
+	}
+
+	return nil
+}
+
+// IsValidDNA reports whether a sequence is a non-empty run of A, C, G, and T
+// in either case. See ValidateDNA for a descriptive error.
+//
+// Parameters:
+//   - seq (string): The sequence to check.
+//
+// Returns:
+//   - (bool): True if the sequence is valid DNA.
+func IsValidDNA(seq string) bool {
+	return ValidateDNA("", seq) == nil
+}
+
+// SmithWatermanChecked validates both sequences before aligning them with
+// SmithWaterman. Lowercase bases are accepted and converted to uppercase, so that
+// they score the same as their uppercase forms.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, or a zero value if validation fails.
+//   - (error): An error wrapping ErrInvalidDNA if either sequence is not valid DNA.
+func SmithWatermanChecked(query, reference string) (AlignmentResult, error) {
+	if err := ValidateDNA("query", query); err != nil {
+		return AlignmentResult{}, err
+	}
+	if err := ValidateDNA("reference", reference); err != nil {
+		return AlignmentResult{}, err
+	}
+
+	return SmithWaterman(strings.ToUpper(query), strings.ToUpper(reference)), nil
+}
+
+// Validate checks that the aligned rows form a well-formed alignment on their own,
+// without the original sequences: both rows have the same number of columns, and
+// no column is a gap in both. Either problem means the traceback stopped or
+// stepped incorrectly. Use VerifyAlignment to also check the rows against the sequences.
+//
+// Returns:
+//   - (error): nil if the alignment is well formed, otherwise an error describing
+//     the first problem found.
+func (r AlignmentResult) Validate() error {
+	if len(r.AlignedQuery) != len(r.AlignedRef) {
+		return fmt.Errorf("aligned query has %d columns but aligned reference has %d",
+			len(r.AlignedQuery), len(r.AlignedRef))
+	}
+
+	for i := 0; i < len(r.AlignedQuery); i++ {
+		if r.AlignedQuery[i] == '-' && r.AlignedRef[i] == '-' {
+			return fmt.Errorf("column %d is a gap in both the query and the reference", i)
+		}
+	}
+
+	return nil
+}
+
+// VerifyAlignment checks that an alignment is consistent with the sequences it was
+// computed from: the aligned rows have the same length, and with gaps removed each
+// row is exactly the stretch of its original sequence between the reported
+// coordinates. This catches traceback bugs that drop, duplicate, or swap bases.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to check.
+//   - query (string): The query the alignment was computed from.
+//   - reference (string): The reference the alignment was computed from.
+//
+// Returns:
+//   - (error): nil if the alignment is consistent, otherwise an error describing
+//     the first problem found.
+func VerifyAlignment(result AlignmentResult, query, reference string) error {
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	rows := []struct {
+		name       string
+		aligned    string
+		original   string
+		start, end int
+	}{
+		{"query", result.AlignedQuery, query, result.QueryStart, result.QueryEnd},
+		{"reference", result.AlignedRef, reference, result.RefStart, result.RefEnd},
+	}
+
+	for _, row := range rows {
+		if row.start < 0 || row.start > row.end || row.end > len(row.original) {
+			return fmt.Errorf("%s coordinates [%d,%d) are outside the %d-base sequence",
+				row.name, row.start, row.end, len(row.original))
+		}
+
+		ungapped := strings.ReplaceAll(row.aligned, "-", "")
+		expected := row.original[row.start:row.end]
+		if ungapped == expected {
+			continue
+		}
+
+		// Report the first position where the recovered bases diverge
+		i := 0
+		for i < len(ungapped) && i < len(expected) && ungapped[i] == expected[i] {
+			i++
+		}
+		if i == len(ungapped) || i == len(expected) {
+			return fmt.Errorf("aligned %s has %d bases but coordinates [%d,%d) span %d",
+				row.name, len(ungapped), row.start, row.end, len(expected))
+		}
+		return fmt.Errorf("aligned %s has %q at %s position %d, but the sequence has %q",
+			row.name, ungapped[i], row.name, row.start+i, expected[i])
+	}
+
+	return nil
+}
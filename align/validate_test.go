@@ -0,0 +1,166 @@
+package align
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestValidateASCII checks that non-ASCII input is rejected with a clear error
+func TestValidateASCII(t *testing.T) {
+	if err := ValidateASCII("query", "GATTACA"); err != nil {
+		t.Errorf("Unexpected error for ASCII sequence: %v", err)
+	}
+
+	if err := ValidateASCII("query", ""); err != nil {
+		t.Errorf("Unexpected error for empty sequence: %v", err)
+	}
+
+	// An accented letter pasted into the middle of the sequence
+	err := ValidateASCII("query", "GATTÁCA")
+	if err == nil {
+		t.Fatal("Expected an error for a sequence containing 'Á'")
+	}
+	if !strings.Contains(err.Error(), "'Á'") || !strings.Contains(err.Error(), "position 4") {
+		t.Errorf("Error should name the character and its position, got: %v", err)
+	}
+
+	// Invalid UTF-8 should be reported as a raw byte
+	err = ValidateASCII("reference", "GAT\xffACA")
+	if err == nil || !strings.Contains(err.Error(), "0xff") {
+		t.Errorf("Expected an invalid byte error, got: %v", err)
+	}
+}
+
+// TestNonASCIICorruptsAlignment documents why validation is needed: the aligner
+// works on bytes, so a single multi-byte character occupies several columns.
+func TestNonASCIICorruptsAlignment(t *testing.T) {
+	query := "GATT“ACA" // Left smart quote, three bytes in UTF-8
+	result := SmithWaterman(query, "GATTTACA")
+
+	// Every column of the alignment should correspond to one character, but
+	// the quote is spread over three byte columns
+	if len(result.AlignedQuery) == utf8.RuneCountInString(result.AlignedQuery) {
+		t.Fatalf("Expected the multi-byte character to be split across columns, got %q", result.AlignedQuery)
+	}
+	if ValidateASCII("query", query) == nil {
+		t.Error("ValidateASCII should reject input that corrupts the alignment")
+	}
+}
+
+// TestValidateDNA checks that non-DNA characters are reported with their sequence and position
+func TestValidateDNA(t *testing.T) {
+	for _, seq := range []string{"GATTACA", "gattaca", "GaTtAcA"} {
+		if err := ValidateDNA("query", seq); err != nil {
+			t.Errorf("Unexpected error for %q: %v", seq, err)
+		}
+		if !IsValidDNA(seq) {
+			t.Errorf("IsValidDNA(%q) = false, want true", seq)
+		}
+	}
+
+	tests := []struct {
+		seq  string
+		want string
+	}{
+		{"", "reference sequence is empty"},
+		{"GATT-ACA", "reference sequence contains '-' at position 4"},
+		{"GATTACA1", "reference sequence contains '1' at position 7"},
+		{"NGATTACA", "reference sequence contains 'N' at position 0"},
+		{"GATTÁCA", "'Á'"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateDNA("reference", tt.seq)
+		if !errors.Is(err, ErrInvalidDNA) {
+			t.Errorf("ValidateDNA(%q) = %v, want ErrInvalidDNA", tt.seq, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateDNA(%q) = %q, want it to contain %q", tt.seq, err, tt.want)
+		}
+		if IsValidDNA(tt.seq) {
+			t.Errorf("IsValidDNA(%q) = true, want false", tt.seq)
+		}
+	}
+}
+
+// TestSmithWatermanChecked checks that invalid input is rejected and lowercase input aligned as uppercase
+func TestSmithWatermanChecked(t *testing.T) {
+	result, err := SmithWatermanChecked("gattaca", "GATTACA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.MaxScore != 14 || result.AlignedQuery != "GATTACA" {
+		t.Errorf("Expected a full-length match scoring 14, got %q with score %d", result.AlignedQuery, result.MaxScore)
+	}
+
+	_, err = SmithWatermanChecked("GATTACA", "GAT-ACA")
+	if !errors.Is(err, ErrInvalidDNA) || !strings.Contains(err.Error(), "reference") {
+		t.Errorf("Expected an invalid reference error, got %v", err)
+	}
+}
+
+// TestAlignmentResultValidate checks that unequal rows and double-gap columns are flagged
+func TestAlignmentResultValidate(t *testing.T) {
+	if err := SmithWaterman("GATTACA", "GATCACA").Validate(); err != nil {
+		t.Errorf("Unexpected error for a real alignment: %v", err)
+	}
+	if err := (AlignmentResult{}).Validate(); err != nil {
+		t.Errorf("Unexpected error for an empty alignment: %v", err)
+	}
+
+	broken := []AlignmentResult{
+		{AlignedQuery: "GATTACA", AlignedRef: "GATTA"},
+		{AlignedQuery: "GAT-ACA", AlignedRef: "GAT-ACA"},
+	}
+	for _, result := range broken {
+		if err := result.Validate(); err == nil {
+			t.Errorf("Expected an error for %s / %s", result.AlignedQuery, result.AlignedRef)
+		}
+	}
+}
+
+// TestVerifyAlignment checks that real alignments verify and corrupted ones are caught
+func TestVerifyAlignment(t *testing.T) {
+	query := "TTTGATTACAGATCAGATAGATACAGATAGACCAGGTACCATG"
+	reference := "CCGATTACAGATCAGTAGATACAGATAGAACCAGGTACCA"
+
+	result := SmithWaterman(query, reference)
+	if err := VerifyAlignment(result, query, reference); err != nil {
+		t.Fatalf("Unexpected error for a real alignment: %v", err)
+	}
+
+	corrupt := func(edit func(r *AlignmentResult)) AlignmentResult {
+		r := result
+		edit(&r)
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		result AlignmentResult
+		want   string
+	}{
+		{"substituted base", corrupt(func(r *AlignmentResult) {
+			r.AlignedQuery = "C" + r.AlignedQuery[1:]
+		}), "aligned query has 'C' at query position 3, but the sequence has 'G'"},
+		{"dropped base", corrupt(func(r *AlignmentResult) {
+			r.AlignedRef = r.AlignedRef[:len(r.AlignedRef)-1] + "-"
+		}), "aligned reference has 37 bases but coordinates [2,40) span 38"},
+		{"rows of different lengths", corrupt(func(r *AlignmentResult) {
+			r.AlignedRef += "A"
+		}), "columns"},
+		{"coordinates out of range", corrupt(func(r *AlignmentResult) {
+			r.RefEnd = len(reference) + 1
+		}), "outside"},
+	}
+
+	for _, tt := range tests {
+		err := VerifyAlignment(tt.result, query, reference)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package align
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// vcfChrom is the CHROM value written by WriteVCF, which aligns against a single sequence
+const vcfChrom = "reference"
+
+// WriteVCF writes mutations as a minimal VCF 4.2 file against a single reference
+// sequence. SNPs become one-base records; insertions and deletions are anchored to
+// the reference base before them, as VCF requires, or to the base after them when
+// they start at the first base of the reference.
+//
+// Mutation positions index into ref. DetectMutations counts positions from the first
+// aligned reference base, so add the alignment's RefStart to each position before
+// writing them against the whole reference.
+//
+// Parameters:
+//   - w (io.Writer): The destination for the VCF text.
+//   - ref (string): The reference sequence the mutations are positioned in.
+//   - mutations ([]Mutation): The mutations to write, in reference order.
+//
+// Returns:
+//   - (error): An error if a mutation lies outside ref, its reference bases disagree
+//     with ref, or the write fails.
+func WriteVCF(w io.Writer, ref string, mutations []Mutation) error {
+	bw := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintln(bw, "##fileformat=VCFv4.2")
+	_, _ = fmt.Fprintf(bw, "##contig=<ID=%s,length=%d>\n", vcfChrom, len(ref))
+	_, _ = fmt.Fprintln(bw, "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO")
+
+	for i, mutation := range mutations {
+		pos, refAllele, altAllele, err := vcfAlleles(ref, mutation)
+		if err != nil {
+			return fmt.Errorf("mutation %d: %w", i+1, err)
+		}
+		if _, err := fmt.Fprintf(bw, "%s\t%d\t.\t%s\t%s\t.\t.\t.\n", vcfChrom, pos, refAllele, altAllele); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// vcfAlleles converts a mutation into its 1-based VCF position and REF/ALT alleles.
+func vcfAlleles(ref string, mutation Mutation) (int, string, string, error) {
+	pos := mutation.Position
+
+	switch mutation.Type {
+	case MutationSNP, MutationDeletion:
+		end := pos + len(mutation.Original)
+		if pos < 0 || end > len(ref) {
+			return 0, "", "", fmt.Errorf("%s at %d runs past the %d-base reference", mutation.Type, pos, len(ref))
+		}
+		if ref[pos:end] != mutation.Original {
+			return 0, "", "", fmt.Errorf("%s at %d expects %q but the reference has %q", mutation.Type, pos, mutation.Original, ref[pos:end])
+		}
+		if mutation.Type == MutationSNP {
+			return pos + 1, mutation.Original, mutation.Mutated, nil
+		}
+
+		// Anchor the deletion to the base before it, or after it at the reference start
+		if pos > 0 {
+			anchor := ref[pos-1 : pos]
+			return pos, anchor + mutation.Original, anchor, nil
+		}
+		if end == len(ref) {
+			return 0, "", "", fmt.Errorf("deletion of the whole reference has no anchor base")
+		}
+		anchor := ref[end : end+1]
+		return 1, mutation.Original + anchor, anchor, nil
+
+	case MutationInsertion:
+		if pos < 0 || pos > len(ref) || len(ref) == 0 {
+			return 0, "", "", fmt.Errorf("insertion at %d is outside the %d-base reference", pos, len(ref))
+		}
+
+		// Anchor the insertion to the base before it, or after it at the reference start
+		if pos > 0 {
+			anchor := ref[pos-1 : pos]
+			return pos, anchor, anchor + mutation.Mutated, nil
+		}
+		anchor := ref[:1]
+		return 1, anchor, mutation.Mutated + anchor, nil
+	}
+
+	return 0, "", "", fmt.Errorf("unknown mutation type %q", mutation.Type)
+}
@@ -0,0 +1,48 @@
+package align
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestWriteVCF checks the header and the anchored representation of each mutation type
+func TestWriteVCF(t *testing.T) {
+	// Query:     GATCA--CAGTTTA
+	// Reference: GATTACACAG--TA
+	mutations := DetectMutations("GATCA--CAGTTTA", "GATTACACAG--TA")
+
+	var buf bytes.Buffer
+	if err := WriteVCF(&buf, "GATTACACAGTA", mutations); err != nil {
+		t.Fatalf("WriteVCF returned an error: %v", err)
+	}
+
+	want := "##fileformat=VCFv4.2\n" +
+		"##contig=<ID=reference,length=12>\n" +
+		"#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n" +
+		"reference\t4\t.\tT\tC\t.\t.\t.\n" +
+		"reference\t5\t.\tACA\tA\t.\t.\t.\n" +
+		"reference\t10\t.\tG\tGTT\t.\t.\t.\n"
+	if buf.String() != want {
+		t.Errorf("WriteVCF() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// Indels at the start of the reference are anchored to the following base
+	buf.Reset()
+	mutations = []Mutation{
+		{Type: MutationInsertion, Position: 0, Length: 2, Original: "-", Mutated: "CC"},
+		{Type: MutationDeletion, Position: 0, Length: 1, Original: "G", Mutated: "-"},
+	}
+	if err := WriteVCF(&buf, "GATTACA", mutations); err != nil {
+		t.Fatalf("WriteVCF returned an error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\t1\t.\tG\tCCG\t") || !strings.Contains(buf.String(), "\t1\t.\tGA\tA\t") {
+		t.Errorf("Expected indels anchored to the next base, got\n%s", buf.String())
+	}
+
+	// Mutations that do not fit the reference are rejected
+	bad := []Mutation{{Type: MutationSNP, Position: 2, Length: 1, Original: "C", Mutated: "G"}}
+	if err := WriteVCF(&bytes.Buffer{}, "GATTACA", bad); err == nil {
+		t.Error("Expected an error for an SNP whose reference base does not match")
+	}
+}
@@ -0,0 +1,45 @@
+package align
+
+// SmithWatermanWindowed performs the same alignment as SmithWaterman but keeps only
+// the part of the score matrix around the traceback path: the rows and columns the
+// path spans, widened by margin on every side and clipped to the matrix. The rest of
+// the full matrix is released, which shrinks the result from (m+1)*(n+1) cells to
+// roughly the square of the alignment length for a short alignment in long inputs.
+//
+// Parameters:
+//   - query (string): The DNA query sequence.
+//   - reference (string): The DNA reference sequence.
+//   - margin (int): The number of extra rows and columns to keep on each side of the
+//     path. Negative values are treated as zero.
+//
+// Returns:
+//   - (AlignmentResult): The alignment result, with ScoreMatrix holding the window and
+//     MatrixRowOffset/MatrixColOffset giving its position in the full matrix.
+func SmithWatermanWindowed(query, reference string, margin int) AlignmentResult {
+	return windowScoreMatrix(SmithWaterman(query, reference), margin)
+}
+
+// windowScoreMatrix replaces the full score matrix of result with a copy of the
+// window around its traceback path.
+func windowScoreMatrix(result AlignmentResult, margin int) AlignmentResult {
+	if len(result.ScoreMatrix) == 0 {
+		return result
+	}
+	margin = max(margin, 0)
+
+	// The path runs from cell (QueryStart, RefStart) to cell (QueryEnd, RefEnd)
+	lastRow, lastCol := len(result.ScoreMatrix)-1, len(result.ScoreMatrix[0])-1
+	top, bottom := max(result.QueryStart-margin, 0), min(result.QueryEnd+margin, lastRow)
+	left, right := max(result.RefStart-margin, 0), min(result.RefEnd+margin, lastCol)
+
+	// Copy the rows so the full matrix is no longer referenced
+	window := make([][]int, bottom-top+1)
+	for i := range window {
+		window[i] = append([]int(nil), result.ScoreMatrix[top+i][left:right+1]...)
+	}
+
+	result.ScoreMatrix = window
+	result.MatrixRowOffset += top
+	result.MatrixColOffset += left
+	return result
+}
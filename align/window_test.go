@@ -0,0 +1,62 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSmithWatermanWindowed checks that the windowed matrix holds every cell of the traceback path
+func TestSmithWatermanWindowed(t *testing.T) {
+	// A short shared region embedded in long flanks that match nothing on the other side
+	core := "GTTGTGGTTTGTGGTGTTGGTG"
+	query := strings.Repeat("A", 400) + core + strings.Repeat("A", 400)
+	reference := strings.Repeat("C", 300) + core + strings.Repeat("C", 500)
+
+	full := SmithWaterman(query, reference)
+	const margin = 3
+	windowed := SmithWatermanWindowed(query, reference, margin)
+
+	if windowed.MaxScore != full.MaxScore || windowed.AlignedQuery != full.AlignedQuery ||
+		windowed.AlignedRef != full.AlignedRef {
+		t.Fatalf("Windowing changed the alignment")
+	}
+
+	rows, cols := len(windowed.ScoreMatrix), len(windowed.ScoreMatrix[0])
+	if rows >= len(full.ScoreMatrix) || cols >= len(full.ScoreMatrix[0]) {
+		t.Errorf("Expected a window smaller than the full %dx%d matrix, got %dx%d",
+			len(full.ScoreMatrix), len(full.ScoreMatrix[0]), rows, cols)
+	}
+	if windowed.MatrixRowOffset != full.QueryStart-margin || windowed.MatrixColOffset != full.RefStart-margin {
+		t.Errorf("Expected offsets (%d, %d), got (%d, %d)", full.QueryStart-margin, full.RefStart-margin,
+			windowed.MatrixRowOffset, windowed.MatrixColOffset)
+	}
+
+	// Every cell the traceback visits must be inside the window with its full-matrix score
+	row, col := full.QueryEnd, full.RefEnd
+	visit := func(row, col int) {
+		i, j := row-windowed.MatrixRowOffset, col-windowed.MatrixColOffset
+		if i < 0 || i >= rows || j < 0 || j >= cols {
+			t.Fatalf("Path cell (%d, %d) is outside the window", row, col)
+		}
+		if windowed.ScoreMatrix[i][j] != full.ScoreMatrix[row][col] {
+			t.Errorf("Cell (%d, %d) = %d, want %d", row, col, windowed.ScoreMatrix[i][j], full.ScoreMatrix[row][col])
+		}
+	}
+	visit(row, col)
+	for k := len(full.AlignedQuery) - 1; k >= 0; k-- {
+		if full.AlignedQuery[k] != '-' {
+			row--
+		}
+		if full.AlignedRef[k] != '-' {
+			col--
+		}
+		visit(row, col)
+	}
+
+	// The margin is clipped at the matrix edges
+	edge := SmithWatermanWindowed(core, core+strings.Repeat("A", 10), 5)
+	if edge.MatrixRowOffset != 0 || edge.MatrixColOffset != 0 || len(edge.ScoreMatrix) != len(core)+1 {
+		t.Errorf("Expected the window clipped to the matrix edges, got offsets (%d, %d) and %d rows",
+			edge.MatrixRowOffset, edge.MatrixColOffset, len(edge.ScoreMatrix))
+	}
+}
@@ -0,0 +1,140 @@
+package align
+
+import (
+	"fmt"
+	"strings"
+
+	"pgfp/data"
+)
+
+// AlignmentBlock is one fixed-width slice of an alignment, ready to print on its own.
+// Coordinates refer to the original sequences (0-based, end exclusive), so a block
+// that is all gaps on one side has an empty range on that side.
+type AlignmentBlock struct {
+	AlignedQuery string `json:"alignedQuery"`
+	AlignedRef   string `json:"alignedRef"`
+	QueryStart   int    `json:"queryStart"`
+	QueryEnd     int    `json:"queryEnd"`
+	RefStart     int    `json:"refStart"`
+	RefEnd       int    `json:"refEnd"`
+}
+
+// WrapAlignment splits an alignment into consecutive blocks of at most width columns.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to wrap.
+//   - width (int): The number of columns per block. Values of zero or less put the
+//     whole alignment in a single block.
+//
+// Returns:
+//   - ([]AlignmentBlock): The blocks in order; concatenating their aligned strings
+//     reproduces the full alignment. Empty for an empty alignment.
+func WrapAlignment(result AlignmentResult, width int) []AlignmentBlock {
+	length := len(result.AlignedQuery)
+	if length == 0 {
+		return nil
+	}
+	if width <= 0 {
+		width = length
+	}
+
+	blocks := make([]AlignmentBlock, 0, (length+width-1)/width)
+	queryPos, refPos := result.QueryStart, result.RefStart
+
+	for start := 0; start < length; start += width {
+		end := start + width
+		if end > length {
+			end = length
+		}
+
+		block := AlignmentBlock{
+			AlignedQuery: result.AlignedQuery[start:end],
+			AlignedRef:   result.AlignedRef[start:end],
+			QueryStart:   queryPos,
+			RefStart:     refPos,
+		}
+
+		// Advance the sequence positions past every non-gap base in the block
+		for i := start; i < end; i++ {
+			if result.AlignedQuery[i] != '-' {
+				queryPos++
+			}
+			if result.AlignedRef[i] != '-' {
+				refPos++
+			}
+		}
+		block.QueryEnd, block.RefEnd = queryPos, refPos
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// FormatPairwise renders an alignment as text, with a match line between the query
+// and reference: '|' for a match, '.' for a mismatch, and a space for a gap.
+// Long alignments are wrapped into blocks separated by blank lines.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to render.
+//   - width (int): The number of columns per block. Values of zero or less put the
+//     whole alignment on one set of lines.
+//
+// Returns:
+//   - (string): The formatted alignment, or an empty string for an empty alignment.
+func FormatPairwise(result AlignmentResult, width int) string {
+	var b strings.Builder
+
+	for i, block := range WrapAlignment(result, width) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(&b, "Query:     %s\n", block.AlignedQuery)
+		_, _ = fmt.Fprintf(&b, "           %s\n", matchLine(block.AlignedQuery, block.AlignedRef))
+		_, _ = fmt.Fprintf(&b, "Reference: %s\n", block.AlignedRef)
+	}
+
+	return b.String()
+}
+
+// matchLine returns the symbols shown between the aligned query and reference.
+func matchLine(alignedQuery, alignedRef string) string {
+	line := make([]byte, min(len(alignedQuery), len(alignedRef)))
+	for i := range line {
+		switch q, r := alignedQuery[i], alignedRef[i]; {
+		case q == '-' || r == '-':
+			line[i] = ' ' // Gap
+		case q == r:
+			line[i] = '|' // Match
+		default:
+			line[i] = '.' // Mismatch
+		}
+	}
+	return string(line)
+}
+
+// AlignmentRecords converts an alignment into two FASTA records, the aligned query
+// then the aligned reference, with gaps preserved. Each description gives the
+// 0-based, end-exclusive range the row covers in its original sequence.
+//
+// Parameters:
+//   - result (AlignmentResult): The alignment to convert.
+//   - queryID (string): The record ID for the query row.
+//   - refID (string): The record ID for the reference row.
+//
+// Returns:
+//   - ([]data.Record): The query and reference records, ready for data.WriteFASTA.
+func AlignmentRecords(result AlignmentResult, queryID, refID string) []data.Record {
+	return []data.Record{
+		{
+			ID:          queryID,
+			Description: fmt.Sprintf("range=%d-%d", result.QueryStart, result.QueryEnd),
+			Sequence:    result.AlignedQuery,
+		},
+		{
+			ID:          refID,
+			Description: fmt.Sprintf("range=%d-%d", result.RefStart, result.RefEnd),
+			Sequence:    result.AlignedRef,
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package align
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWrapAlignment checks that blocks reassemble into the full alignment with contiguous coordinates
+func TestWrapAlignment(t *testing.T) {
+	query := "TTTGATTACAGATCAGATAGATACAGATAGACCAGGTACCATG"
+	reference := "CCGATTACAGATCAGTAGATACAGATAGAACCAGGTACCA"
+	result := SmithWaterman(query, reference)
+
+	for _, width := range []int{1, 7, 10, len(result.AlignedQuery), 1000, 0} {
+		blocks := WrapAlignment(result, width)
+
+		var alignedQuery, alignedRef strings.Builder
+		queryPos, refPos := result.QueryStart, result.RefStart
+		for i, block := range blocks {
+			if width > 0 && len(block.AlignedQuery) > width {
+				t.Errorf("Width %d: block %d has %d columns", width, i, len(block.AlignedQuery))
+			}
+
+			// Each block starts where the previous one ended
+			if block.QueryStart != queryPos || block.RefStart != refPos {
+				t.Errorf("Width %d: block %d starts at (%d,%d), expected (%d,%d)",
+					width, i, block.QueryStart, block.RefStart, queryPos, refPos)
+			}
+
+			// The coordinates cover exactly the bases shown in the block
+			if got := query[block.QueryStart:block.QueryEnd]; got != stripGaps(block.AlignedQuery) {
+				t.Errorf("Width %d: block %d query range holds %s, block shows %s", width, i, got, block.AlignedQuery)
+			}
+			if got := reference[block.RefStart:block.RefEnd]; got != stripGaps(block.AlignedRef) {
+				t.Errorf("Width %d: block %d reference range holds %s, block shows %s", width, i, got, block.AlignedRef)
+			}
+
+			alignedQuery.WriteString(block.AlignedQuery)
+			alignedRef.WriteString(block.AlignedRef)
+			queryPos, refPos = block.QueryEnd, block.RefEnd
+		}
+
+		if alignedQuery.String() != result.AlignedQuery || alignedRef.String() != result.AlignedRef {
+			t.Errorf("Width %d: blocks do not reassemble into the full alignment", width)
+		}
+		if queryPos != result.QueryEnd || refPos != result.RefEnd {
+			t.Errorf("Width %d: blocks end at (%d,%d), expected (%d,%d)",
+				width, queryPos, refPos, result.QueryEnd, result.RefEnd)
+		}
+	}
+
+	if blocks := WrapAlignment(AlignmentResult{}, 10); len(blocks) != 0 {
+		t.Errorf("Expected no blocks for an empty alignment, got %d", len(blocks))
+	}
+}
+
+// TestFormatPairwise checks the match line and wrapping of the text rendering
+func TestFormatPairwise(t *testing.T) {
+	result := AlignmentResult{AlignedQuery: "GATT-ACAGTA", AlignedRef: "GATTTACTGTA"}
+
+	want := "Query:     GATT-ACAGTA\n" +
+		"           |||| ||.|||\n" +
+		"Reference: GATTTACTGTA\n"
+	if got := FormatPairwise(result, 0); got != want {
+		t.Errorf("FormatPairwise(width 0) =\n%s\nwant\n%s", got, want)
+	}
+
+	want = "Query:     GATT-A\n" +
+		"           |||| |\n" +
+		"Reference: GATTTA\n" +
+		"\n" +
+		"Query:     CAGTA\n" +
+		"           |.|||\n" +
+		"Reference: CTGTA\n"
+	if got := FormatPairwise(result, 6); got != want {
+		t.Errorf("FormatPairwise(width 6) =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatPairwise(AlignmentResult{}, 10); got != "" {
+		t.Errorf("Expected no output for an empty alignment, got %q", got)
+	}
+}
+
+// TestAlignmentRecords checks that the records carry the gapped rows and their ranges
+func TestAlignmentRecords(t *testing.T) {
+	result := SmithWaterman("TTGATTACA", "GATTTACA")
+	records := AlignmentRecords(result, "read", "ref")
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0].ID != "read" || records[0].Sequence != result.AlignedQuery || records[0].Description != "range=2-9" {
+		t.Errorf("Unexpected query record: %+v", records[0])
+	}
+	if records[1].ID != "ref" || records[1].Sequence != result.AlignedRef || records[1].Description != "range=0-8" {
+		t.Errorf("Unexpected reference record: %+v", records[1])
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"runtime/pprof"
+	"sort"
+	"strconv"
 	"time"
 
 	"pgfp/align"
@@ -35,8 +39,28 @@ func main() {
 	numWorkers := flag.Int("workers", runtime.GOMAXPROCS(0), "number of workers for parallel execution")
 	batchSize := flag.Int("batch", 10, "batch size for batch mode")
 	repetitions := flag.Int("reps", 3, "number of repetitions for more accurate timing")
+	showProgress := flag.Bool("progress", false, "print periodic progress to stderr (sequential and batch modes)")
+	progressInterval := flag.Duration("progress-interval", time.Second, "minimum time between progress lines")
+	seed := flag.Int64("seed", 0, "seed for the generated sequences (0 = pick one at random and print it)")
+	csvOut := flag.String("csv", "", "write every repetition's duration to this CSV file")
 	flag.Parse()
 
+	if *seqLength <= 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid length: %d (must be positive)\n", *seqLength)
+		os.Exit(1)
+	}
+
+	if *repetitions <= 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid repetitions: %d (must be positive)\n", *repetitions)
+		os.Exit(1)
+	}
+
+	// Progress output goes to stderr so it never mixes with the results on stdout
+	var progress progressConfig
+	if *showProgress {
+		progress = progressConfig{out: os.Stderr, interval: *progressInterval}
+	}
+
 	// Determine which modes to benchmark
 	var modesToRun []ExecutionMode
 	switch *modeFlag {
@@ -75,14 +99,20 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	// Track execution times
+	// Track mean execution times, and every repetition for the CSV output
 	var sequentialTime, parallelTime time.Duration
 	var batchSeqTime, batchParTime time.Duration
+	var runs []modeTimings
+
+	// Pick a seed if none was given; printing it lets a run be replayed with -seed
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
 
 	// Generate test data only once for all benchmarks
-	fmt.Printf("Generating test sequences (length: %d)...\n", *seqLength)
-	query := data.GenerateDNASequence(*seqLength)
-	reference := data.GenerateDNASequence(*seqLength)
+	fmt.Printf("Generating test sequences (length: %d, seed: %d)...\n", *seqLength, *seed)
+	query := data.GenerateDNASequenceSeeded(*seqLength, *seed)
+	reference := data.GenerateDNASequenceSeeded(*seqLength, *seed+1)
 
 	// Prepare batch data if needed
 	var references []string
@@ -90,7 +120,7 @@ func main() {
 		fmt.Printf("Generating %d reference sequences for batch processing...\n", *batchSize)
 		references = make([]string, *batchSize)
 		for i := range references {
-			references[i] = data.GenerateDNASequence(*seqLength)
+			references[i] = data.GenerateDNASequenceSeeded(*seqLength, *seed+2+int64(i))
 		}
 	}
 
@@ -103,40 +133,54 @@ func main() {
 			// Run sequential benchmark
 			fmt.Printf("Running sequential Smith-Waterman (length: %d, repetitions: %d)...\n",
 				*seqLength, *repetitions)
-			sequentialTime = runSequentialBenchmark(query, reference, *repetitions)
+			durations := runSequentialBenchmark(query, reference, *repetitions, progress)
+			sequentialTime = summarizeTimings(durations).Mean
 			fmt.Printf("Sequential execution time: %v\n", sequentialTime)
+			printTimingStats(os.Stdout, durations)
+			runs = append(runs, modeTimings{mode: Sequential, workers: 1, durations: durations})
 
 		case Parallel:
 			// Run parallel benchmark
 			fmt.Printf("Running parallel Smith-Waterman (length: %d, workers: %d, repetitions: %d)...\n",
 				*seqLength, *numWorkers, *repetitions)
-			parallelTime = runParallelBenchmark(query, reference, *numWorkers, *repetitions)
+			durations := runParallelBenchmark(query, reference, *numWorkers, *repetitions)
+			parallelTime = summarizeTimings(durations).Mean
 			fmt.Printf("Parallel execution time: %v\n", parallelTime)
+			printTimingStats(os.Stdout, durations)
+			runs = append(runs, modeTimings{mode: Parallel, workers: *numWorkers, durations: durations})
 
-			// Report speedup if sequential was also run
+			// Report speedup and efficiency if sequential was also run
 			if sequentialTime > 0 {
 				speedup := float64(sequentialTime) / float64(parallelTime)
 				fmt.Printf("Speedup factor: %.2fx\n", speedup)
+				reportEfficiency(os.Stdout, speedup, *numWorkers, "sequence may be too short for the worker count")
 			}
 
 		case BatchSequential:
 			// Run batch sequential benchmark
 			fmt.Printf("Running sequential batch processing (length: %d, batch size: %d, repetitions: %d)...\n",
 				*seqLength, *batchSize, *repetitions)
-			batchSeqTime = runBatchSequentialBenchmark(query, references, *repetitions)
+			durations := runBatchSequentialBenchmark(query, references, *repetitions, progress)
+			batchSeqTime = summarizeTimings(durations).Mean
 			fmt.Printf("Sequential batch execution time: %v\n", batchSeqTime)
+			printTimingStats(os.Stdout, durations)
+			runs = append(runs, modeTimings{mode: BatchSequential, workers: 1, durations: durations})
 
 		case BatchParallel:
 			// Run batch parallel benchmark
 			fmt.Printf("Running parallel batch processing (length: %d, batch size: %d, workers: %d, repetitions: %d)...\n",
 				*seqLength, *batchSize, *numWorkers, *repetitions)
-			batchParTime = runBatchParallelBenchmark(query, references, *numWorkers, *repetitions)
+			durations := runBatchParallelBenchmark(query, references, *numWorkers, *repetitions, progress)
+			batchParTime = summarizeTimings(durations).Mean
 			fmt.Printf("Parallel batch execution time: %v\n", batchParTime)
+			printTimingStats(os.Stdout, durations)
+			runs = append(runs, modeTimings{mode: BatchParallel, workers: *numWorkers, durations: durations})
 
-			// Report speedup if batch sequential was also run
+			// Report speedup and efficiency if batch sequential was also run
 			if batchSeqTime > 0 {
 				speedup := float64(batchSeqTime) / float64(batchParTime)
 				fmt.Printf("Batch speedup factor: %.2fx\n", speedup)
+				reportEfficiency(os.Stdout, speedup, *numWorkers, "batch may be too small for the worker count")
 			}
 		}
 	}
@@ -146,14 +190,25 @@ func main() {
 		fmt.Printf("\n=== Performance Summary ===\n")
 
 		if sequentialTime > 0 && parallelTime > 0 {
-			fmt.Printf("Single alignment: Sequential = %v, Parallel = %v, Speedup = %.2fx\n",
-				sequentialTime, parallelTime, float64(sequentialTime)/float64(parallelTime))
+			speedup := float64(sequentialTime) / float64(parallelTime)
+			fmt.Printf("Single alignment: Sequential = %v, Parallel = %v, Speedup = %.2fx, Efficiency = %.1f%%\n",
+				sequentialTime, parallelTime, speedup, parallelEfficiency(speedup, *numWorkers))
 		}
 
 		if batchSeqTime > 0 && batchParTime > 0 {
-			fmt.Printf("Batch processing: Sequential = %v, Parallel = %v, Speedup = %.2fx\n",
-				batchSeqTime, batchParTime, float64(batchSeqTime)/float64(batchParTime))
+			speedup := float64(batchSeqTime) / float64(batchParTime)
+			fmt.Printf("Batch processing: Sequential = %v, Parallel = %v, Speedup = %.2fx, Efficiency = %.1f%%\n",
+				batchSeqTime, batchParTime, speedup, parallelEfficiency(speedup, *numWorkers))
+		}
+	}
+
+	// Write per-repetition timings if requested
+	if *csvOut != "" {
+		if err := writeTimingsCSVFile(*csvOut, *seqLength, *batchSize, runs); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not write timings CSV: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Printf("\nPer-repetition timings written to %s\n", *csvOut)
 	}
 
 	// Memory profiling if requested
@@ -187,14 +242,66 @@ func main() {
 	fmt.Printf("\tNumGC = %v\n", m.NumGC)
 }
 
-// runSequentialBenchmark runs the sequential algorithm and returns execution time
-func runSequentialBenchmark(query, reference string, repetitions int) time.Duration {
-	totalTime := time.Duration(0)
+// lowEfficiencyPercent is the parallel efficiency below which a hint is printed
+const lowEfficiencyPercent = 50.0
+
+// parallelEfficiency returns the speedup per worker as a percentage. 100% means
+// every worker contributed fully; a 3x speedup on 8 workers is 37.5%.
+func parallelEfficiency(speedup float64, workers int) float64 {
+	if workers <= 0 {
+		return 0
+	}
+	return speedup / float64(workers) * 100
+}
+
+// reportEfficiency writes the parallel efficiency for a speedup, followed by the
+// given hint when the efficiency is below lowEfficiencyPercent.
+func reportEfficiency(w io.Writer, speedup float64, workers int, hint string) {
+	efficiency := parallelEfficiency(speedup, workers)
+	_, _ = fmt.Fprintf(w, "Parallel efficiency: %.1f%% (%d workers)\n", efficiency, workers)
+	if efficiency < lowEfficiencyPercent {
+		_, _ = fmt.Fprintf(w, "Hint: low parallel efficiency; %s\n", hint)
+	}
+}
+
+// progressConfig controls periodic progress output during long runs
+type progressConfig struct {
+	out      io.Writer     // Destination for progress lines; nil disables reporting
+	interval time.Duration // Minimum time between two progress lines
+}
+
+// reporter returns a throttled progress callback labelled with the given text,
+// or nil when progress reporting is disabled. The final update is always written.
+func (p progressConfig) reporter(label string) align.ProgressFunc {
+	if p.out == nil {
+		return nil
+	}
+
+	var last time.Time
+	return func(done, total int) {
+		now := time.Now()
+		if done < total && now.Sub(last) < p.interval {
+			return
+		}
+		last = now
+
+		percent := 100.0
+		if total > 0 {
+			percent = float64(done) / float64(total) * 100
+		}
+		_, _ = fmt.Fprintf(p.out, "[progress] %s: %d/%d (%.1f%%)\n", label, done, total, percent)
+	}
+}
+
+// runSequentialBenchmark runs the sequential algorithm and returns the time taken by each repetition
+func runSequentialBenchmark(query, reference string, repetitions int, progress progressConfig) []time.Duration {
+	durations := make([]time.Duration, 0, repetitions)
 
 	for i := 0; i < repetitions; i++ {
+		report := progress.reporter(fmt.Sprintf("sequential rep %d/%d rows", i+1, repetitions))
 		start := time.Now()
-		result := align.SmithWaterman(query, reference)
-		totalTime += time.Since(start)
+		result := align.SmithWatermanWithProgress(query, reference, report)
+		durations = append(durations, time.Since(start))
 
 		// Report score from first run
 		if i == 0 {
@@ -202,17 +309,17 @@ func runSequentialBenchmark(query, reference string, repetitions int) time.Durat
 		}
 	}
 
-	return totalTime / time.Duration(repetitions)
+	return durations
 }
 
-// runParallelBenchmark runs the parallel algorithm and returns execution time
-func runParallelBenchmark(query, reference string, workers, repetitions int) time.Duration {
-	totalTime := time.Duration(0)
+// runParallelBenchmark runs the parallel algorithm and returns the time taken by each repetition
+func runParallelBenchmark(query, reference string, workers, repetitions int) []time.Duration {
+	durations := make([]time.Duration, 0, repetitions)
 
 	for i := 0; i < repetitions; i++ {
 		start := time.Now()
 		result := align.ParallelSmithWaterman(query, reference, workers)
-		totalTime += time.Since(start)
+		durations = append(durations, time.Since(start))
 
 		// Report score from first run
 		if i == 0 {
@@ -220,23 +327,27 @@ func runParallelBenchmark(query, reference string, workers, repetitions int) tim
 		}
 	}
 
-	return totalTime / time.Duration(repetitions)
+	return durations
 }
 
-// runBatchSequentialBenchmark runs sequential batch processing and returns execution time
-func runBatchSequentialBenchmark(query string, references []string, repetitions int) time.Duration {
-	totalTime := time.Duration(0)
+// runBatchSequentialBenchmark runs sequential batch processing and returns the time taken by each repetition
+func runBatchSequentialBenchmark(query string, references []string, repetitions int, progress progressConfig) []time.Duration {
+	durations := make([]time.Duration, 0, repetitions)
 
 	for i := 0; i < repetitions; i++ {
+		report := progress.reporter(fmt.Sprintf("batch-seq rep %d/%d references", i+1, repetitions))
 		start := time.Now()
 
 		// Process each reference sequentially
 		results := make([]align.AlignmentResult, len(references))
 		for j, ref := range references {
 			results[j] = align.SmithWaterman(query, ref)
+			if report != nil {
+				report(j+1, len(references))
+			}
 		}
 
-		totalTime += time.Since(start)
+		durations = append(durations, time.Since(start))
 
 		// Report average score from first run
 		if i == 0 {
@@ -248,17 +359,18 @@ func runBatchSequentialBenchmark(query string, references []string, repetitions
 		}
 	}
 
-	return totalTime / time.Duration(repetitions)
+	return durations
 }
 
-// runBatchParallelBenchmark runs parallel batch processing and returns execution time
-func runBatchParallelBenchmark(query string, references []string, workers, repetitions int) time.Duration {
-	totalTime := time.Duration(0)
+// runBatchParallelBenchmark runs parallel batch processing and returns the time taken by each repetition
+func runBatchParallelBenchmark(query string, references []string, workers, repetitions int, progress progressConfig) []time.Duration {
+	durations := make([]time.Duration, 0, repetitions)
 
 	for i := 0; i < repetitions; i++ {
+		report := progress.reporter(fmt.Sprintf("batch-par rep %d/%d references", i+1, repetitions))
 		start := time.Now()
-		results := align.ConcurrentSmithWatermanBatch(query, references, workers)
-		totalTime += time.Since(start)
+		results := align.ConcurrentSmithWatermanBatchWithProgress(query, references, workers, report)
+		durations = append(durations, time.Since(start))
 
 		// Report average score from first run
 		if i == 0 {
@@ -270,7 +382,99 @@ func runBatchParallelBenchmark(query string, references []string, workers, repet
 		}
 	}
 
-	return totalTime / time.Duration(repetitions)
+	return durations
+}
+
+// timingStats summarizes the durations of a benchmark's repetitions
+type timingStats struct {
+	Mean, Min, Median, P95, Max time.Duration
+}
+
+// summarizeTimings computes the mean and distribution of a set of durations. The
+// median averages the two middle values for an even count, and p95 is the nearest
+// rank, so with fewer than 20 repetitions it equals the maximum.
+func summarizeTimings(durations []time.Duration) timingStats {
+	if len(durations) == 0 {
+		return timingStats{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+
+	total := time.Duration(0)
+	for _, d := range sorted {
+		total += d
+	}
+
+	n := len(sorted)
+	return timingStats{
+		Mean:   total / time.Duration(n),
+		Min:    sorted[0],
+		Median: (sorted[(n-1)/2] + sorted[n/2]) / 2,
+		P95:    sorted[(95*n+99)/100-1],
+		Max:    sorted[n-1],
+	}
+}
+
+// printTimingStats writes the spread of a benchmark's repetitions on one line
+func printTimingStats(w io.Writer, durations []time.Duration) {
+	stats := summarizeTimings(durations)
+	_, _ = fmt.Fprintf(w, "  min %v, median %v, p95 %v, max %v over %d repetitions\n",
+		stats.Min, stats.Median, stats.P95, stats.Max, len(durations))
+}
+
+// modeTimings holds every repetition's duration for one benchmarked mode
+type modeTimings struct {
+	mode      ExecutionMode
+	workers   int // Workers used; 1 for the sequential modes
+	durations []time.Duration
+}
+
+// writeTimingsCSV writes one row per repetition, with a header, so scaling curves
+// can be plotted from several runs at different lengths or worker counts.
+func writeTimingsCSV(w io.Writer, length, batchSize int, runs []modeTimings) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"mode", "length", "batch", "workers", "repetition", "nanoseconds"}); err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		// Batch size only applies to the batch modes
+		batch := 0
+		if run.mode == BatchSequential || run.mode == BatchParallel {
+			batch = batchSize
+		}
+
+		for i, d := range run.durations {
+			record := []string{
+				run.mode.String(),
+				strconv.Itoa(length),
+				strconv.Itoa(batch),
+				strconv.Itoa(run.workers),
+				strconv.Itoa(i + 1),
+				strconv.FormatInt(d.Nanoseconds(), 10),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTimingsCSVFile creates path and writes the timings CSV to it
+func writeTimingsCSVFile(path string, length, batchSize int, runs []modeTimings) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeTimingsCSV(f, length, batchSize, runs); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
 }
 
 // bToMb converts bytes to megabytes
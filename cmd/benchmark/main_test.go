@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"pgfp/data"
+)
+
+// TestProgressReporting verifies that progress lines are emitted for long-running modes
+func TestProgressReporting(t *testing.T) {
+	query := data.GenerateDNASequence(300)
+	references := []string{
+		data.GenerateDNASequence(300),
+		data.GenerateDNASequence(300),
+		data.GenerateDNASequence(300),
+	}
+
+	var buf bytes.Buffer
+	progress := progressConfig{out: &buf, interval: 0}
+
+	runSequentialBenchmark(query, references[0], 1, progress)
+	if !strings.Contains(buf.String(), "[progress] sequential rep 1/1 rows: 300/300") {
+		t.Errorf("Expected final sequential progress line, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	runBatchParallelBenchmark(query, references, 2, 1, progress)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(references) {
+		t.Errorf("Expected %d batch progress lines, got %d:\n%s", len(references), len(lines), buf.String())
+	}
+	if !strings.HasSuffix(lines[len(lines)-1], "3/3 (100.0%)") {
+		t.Errorf("Expected last batch progress line to report completion, got %q", lines[len(lines)-1])
+	}
+}
+
+// TestProgressReportingDisabled verifies that no callback is created without an output
+func TestProgressReportingDisabled(t *testing.T) {
+	if report := (progressConfig{}).reporter("sequential"); report != nil {
+		t.Error("Expected nil reporter when progress output is disabled")
+	}
+}
+
+// TestParallelEfficiency validates the efficiency calculation and the low-efficiency hint
+func TestParallelEfficiency(t *testing.T) {
+	tests := []struct {
+		speedup float64
+		workers int
+		want    float64
+	}{
+		{8, 8, 100},
+		{3, 8, 37.5},
+		{1, 1, 100},
+		{2, 4, 50},
+		{2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := parallelEfficiency(tt.speedup, tt.workers); got != tt.want {
+			t.Errorf("parallelEfficiency(%v, %d) = %v, want %v", tt.speedup, tt.workers, got, tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	reportEfficiency(&buf, 3, 8, "sequence may be too short for the worker count")
+	if !strings.Contains(buf.String(), "Parallel efficiency: 37.5% (8 workers)") {
+		t.Errorf("Expected the efficiency line, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Hint: low parallel efficiency; sequence may be too short") {
+		t.Errorf("Expected a hint for low efficiency, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	reportEfficiency(&buf, 7, 8, "unused")
+	if strings.Contains(buf.String(), "Hint") {
+		t.Errorf("Expected no hint for high efficiency, got:\n%s", buf.String())
+	}
+}
+
+// TestSummarizeTimings checks the mean, median, and nearest-rank p95 of repetition durations
+func TestSummarizeTimings(t *testing.T) {
+	durations := make([]time.Duration, 20)
+	for i := range durations {
+		// 20ms down to 1ms, out of order on purpose
+		durations[i] = time.Duration(20-i) * time.Millisecond
+	}
+
+	want := timingStats{
+		Mean:   10500 * time.Microsecond,
+		Min:    time.Millisecond,
+		Median: 10500 * time.Microsecond,
+		P95:    19 * time.Millisecond,
+		Max:    20 * time.Millisecond,
+	}
+	if got := summarizeTimings(durations); got != want {
+		t.Errorf("summarizeTimings = %+v, want %+v", got, want)
+	}
+
+	// A single repetition is every statistic at once
+	one := summarizeTimings([]time.Duration{5 * time.Millisecond})
+	if one.Min != one.Max || one.Median != one.Max || one.P95 != one.Max || one.Mean != one.Max {
+		t.Errorf("Expected all statistics equal for one repetition, got %+v", one)
+	}
+
+	if (summarizeTimings(nil) != timingStats{}) {
+		t.Error("Expected zero statistics without repetitions")
+	}
+}
+
+// TestWriteTimingsCSV checks the header and that each repetition gets its own row
+func TestWriteTimingsCSV(t *testing.T) {
+	runs := []modeTimings{
+		{mode: Sequential, workers: 1, durations: []time.Duration{1500, 1700}},
+		{mode: BatchParallel, workers: 4, durations: []time.Duration{900}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTimingsCSV(&buf, 1000, 10, runs); err != nil {
+		t.Fatalf("writeTimingsCSV failed: %v", err)
+	}
+
+	want := "mode,length,batch,workers,repetition,nanoseconds\n" +
+		"Sequential,1000,0,1,1,1500\n" +
+		"Sequential,1000,0,1,2,1700\n" +
+		"BatchParallel,1000,10,4,1,900\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
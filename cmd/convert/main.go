@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"pgfp/data"
+)
+
+// PairAlignment is a pairwise alignment with the names of its two sequences
+type PairAlignment struct {
+	QueryName    string `json:"queryName,omitempty"`
+	RefName      string `json:"refName,omitempty"`
+	AlignedQuery string `json:"alignedQuery"`
+	AlignedRef   string `json:"alignedRef"`
+}
+
+// Supported alignment formats
+const (
+	formatFASTA   = "fasta"   // Two gapped FASTA records, query first
+	formatJSON    = "json"    // The JSON alignment object used by the web UI
+	formatClustal = "clustal" // Clustal ALN with a conservation line
+)
+
+// clustalWidth is the number of alignment columns per Clustal block
+const clustalWidth = 60
+
+func main() {
+	// Define command-line flags
+	from := flag.String("from", formatJSON, "input format: fasta, json, or clustal")
+	to := flag.String("to", formatFASTA, "output format: fasta, json, or clustal")
+	inPath := flag.String("in", "", "input file (default stdin)")
+	outPath := flag.String("out", "", "output file (default stdout)")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if *inPath != "" {
+		f, err := os.Open(*inPath)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not open input: %v\n", err)
+			os.Exit(1)
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+		in = f
+	}
+
+	alignment, err := readAlignment(in, *from)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Could not read %s alignment: %v\n", *from, err)
+		os.Exit(1)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not create output: %v\n", err)
+			os.Exit(1)
+		}
+		defer func(f *os.File) {
+			if err := f.Close(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Could not close output: %v\n", err)
+				os.Exit(1)
+			}
+		}(f)
+		out = f
+	}
+
+	if err := writeAlignment(out, alignment, *to); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Could not write %s alignment: %v\n", *to, err)
+		os.Exit(1)
+	}
+}
+
+// readAlignment parses a pairwise alignment in the given format and validates it
+func readAlignment(r io.Reader, format string) (PairAlignment, error) {
+	var alignment PairAlignment
+	var err error
+
+	switch format {
+	case formatFASTA:
+		alignment, err = readFASTAPair(r)
+	case formatJSON:
+		err = json.NewDecoder(r).Decode(&alignment)
+	case formatClustal:
+		alignment, err = readClustal(r)
+	default:
+		return PairAlignment{}, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return PairAlignment{}, err
+	}
+
+	// Fill in default names so every output format can label its rows
+	if alignment.QueryName == "" {
+		alignment.QueryName = "query"
+	}
+	if alignment.RefName == "" {
+		alignment.RefName = "reference"
+	}
+
+	if len(alignment.AlignedQuery) != len(alignment.AlignedRef) {
+		return PairAlignment{}, fmt.Errorf("aligned sequences have different lengths (%d and %d)",
+			len(alignment.AlignedQuery), len(alignment.AlignedRef))
+	}
+
+	return alignment, nil
+}
+
+// writeAlignment writes a pairwise alignment in the given format
+func writeAlignment(w io.Writer, alignment PairAlignment, format string) error {
+	switch format {
+	case formatFASTA:
+		_, err := fmt.Fprintf(w, ">%s\n%s\n>%s\n%s\n",
+			alignment.QueryName, alignment.AlignedQuery, alignment.RefName, alignment.AlignedRef)
+		return err
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(alignment)
+	case formatClustal:
+		return writeClustal(w, alignment)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// readFASTAPair reads exactly two FASTA records: the aligned query, then the aligned reference
+func readFASTAPair(r io.Reader) (PairAlignment, error) {
+	records, err := data.ReadFASTA(r)
+	if err != nil {
+		return PairAlignment{}, err
+	}
+	if len(records) != 2 {
+		return PairAlignment{}, fmt.Errorf("expected 2 FASTA records, found %d", len(records))
+	}
+
+	return PairAlignment{
+		QueryName:    fastaHeader(records[0]),
+		RefName:      fastaHeader(records[1]),
+		AlignedQuery: records[0].Sequence,
+		AlignedRef:   records[1].Sequence,
+	}, nil
+}
+
+// fastaHeader rebuilds the header text of a record so names survive a round trip
+func fastaHeader(record data.Record) string {
+	if record.Description == "" {
+		return record.ID
+	}
+	return record.ID + " " + record.Description
+}
+
+// readClustal reads a two-sequence Clustal ALN file. Header, blank, and
+// conservation lines are skipped; sequence lines are "name  residues [count]".
+func readClustal(r io.Reader) (PairAlignment, error) {
+	var names []string
+	rows := make(map[string]*strings.Builder)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+
+		// Skip the header, blank lines, and conservation lines (which start with spaces)
+		if strings.HasPrefix(line, "CLUSTAL") || strings.TrimSpace(line) == "" ||
+			line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return PairAlignment{}, fmt.Errorf("line %d: expected a name and a sequence", lineNum)
+		}
+
+		name := fields[0]
+		if rows[name] == nil {
+			names = append(names, name)
+			rows[name] = &strings.Builder{}
+		}
+		rows[name].WriteString(fields[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return PairAlignment{}, err
+	}
+
+	if len(names) != 2 {
+		return PairAlignment{}, fmt.Errorf("expected 2 sequences, found %d", len(names))
+	}
+
+	return PairAlignment{
+		QueryName:    names[0],
+		RefName:      names[1],
+		AlignedQuery: rows[names[0]].String(),
+		AlignedRef:   rows[names[1]].String(),
+	}, nil
+}
+
+// writeClustal writes the alignment in Clustal ALN format, in blocks of clustalWidth
+// columns with a conservation line marking identical columns with '*'
+func writeClustal(w io.Writer, alignment PairAlignment) error {
+	if strings.ContainsAny(alignment.QueryName+alignment.RefName, " \t") {
+		return errors.New("clustal sequence names cannot contain whitespace")
+	}
+
+	nameWidth := max(len(alignment.QueryName), len(alignment.RefName)) + 4
+	if _, err := fmt.Fprint(w, "CLUSTAL W multiple sequence alignment\n\n"); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(alignment.AlignedQuery); start += clustalWidth {
+		end := min(start+clustalWidth, len(alignment.AlignedQuery))
+		query := alignment.AlignedQuery[start:end]
+		ref := alignment.AlignedRef[start:end]
+
+		conservation := make([]byte, len(query))
+		for i := range conservation {
+			conservation[i] = ' '
+			if query[i] == ref[i] && query[i] != '-' {
+				conservation[i] = '*'
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%-*s%s\n%-*s%s\n%-*s%s\n\n",
+			nameWidth, alignment.QueryName, query,
+			nameWidth, alignment.RefName, ref,
+			nameWidth, "", conservation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
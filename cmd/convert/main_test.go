@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestJSONClustalRoundTrip checks that a JSON alignment survives conversion to Clustal and back
+func TestJSONClustalRoundTrip(t *testing.T) {
+	original := PairAlignment{
+		QueryName:    "sample",
+		RefName:      "chr1",
+		AlignedQuery: strings.Repeat("GATT-ACAGA", 15),
+		AlignedRef:   strings.Repeat("GATTTACA-A", 15),
+	}
+
+	var input bytes.Buffer
+	if err := writeAlignment(&input, original, formatJSON); err != nil {
+		t.Fatalf("Could not write JSON: %v", err)
+	}
+
+	alignment, err := readAlignment(&input, formatJSON)
+	if err != nil {
+		t.Fatalf("Could not read JSON: %v", err)
+	}
+
+	var clustal bytes.Buffer
+	if err := writeAlignment(&clustal, alignment, formatClustal); err != nil {
+		t.Fatalf("Could not write Clustal: %v", err)
+	}
+	if !strings.HasPrefix(clustal.String(), "CLUSTAL") {
+		t.Errorf("Clustal output is missing its header:\n%s", clustal.String())
+	}
+
+	roundTripped, err := readAlignment(&clustal, formatClustal)
+	if err != nil {
+		t.Fatalf("Could not read Clustal: %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := writeAlignment(&output, roundTripped, formatJSON); err != nil {
+		t.Fatalf("Could not write JSON: %v", err)
+	}
+	final, err := readAlignment(&output, formatJSON)
+	if err != nil {
+		t.Fatalf("Could not read JSON: %v", err)
+	}
+
+	if final != original {
+		t.Errorf("Round trip changed the alignment:\ngot      %+v\nexpected %+v", final, original)
+	}
+}
+
+// TestReadAlignmentValidation checks that inconsistent or malformed inputs are rejected
+func TestReadAlignmentValidation(t *testing.T) {
+	testCases := []struct {
+		format string
+		input  string
+	}{
+		{formatJSON, `{"alignedQuery": "GATTACA", "alignedRef": "GATTA"}`},
+		{formatFASTA, ">q\nGATTACA\n>r\nGAT\n"},
+		{formatFASTA, ">q\nGATTACA\n"},
+		{formatClustal, "CLUSTAL W\n\nq  GATTACA\nr  GATT\n"},
+		{"xml", "<alignment/>"},
+	}
+
+	for _, tc := range testCases {
+		if _, err := readAlignment(strings.NewReader(tc.input), tc.format); err == nil {
+			t.Errorf("Expected an error reading %s input %q", tc.format, tc.input)
+		}
+	}
+
+	// A FASTA pair without names gets the default labels
+	alignment, err := readAlignment(strings.NewReader(">\nGATT-ACA\n>\nGATTTACA\n"), formatFASTA)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if alignment.QueryName != "query" || alignment.RefName != "reference" {
+		t.Errorf("Expected default names, got %q and %q", alignment.QueryName, alignment.RefName)
+	}
+}
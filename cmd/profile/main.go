@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"runtime/pprof"
@@ -21,6 +23,19 @@ type ProfileConfig struct {
 	NumWorkers  int
 	BatchSize   int
 	Repetitions int
+	Format      string
+	FoldedOut   string
+}
+
+// info receives the human-readable report. It is redirected to stderr when
+// stdout carries structured output, so the two never mix.
+var info io.Writer = os.Stdout
+
+// batchRecord is one line of JSON-lines batch output
+type batchRecord struct {
+	Index    int     `json:"index"`
+	Score    int     `json:"score"`
+	Identity float64 `json:"identity"`
 }
 
 func main() {
@@ -34,8 +49,34 @@ func main() {
 	flag.IntVar(&config.NumWorkers, "workers", 0, "number of workers (0 = auto)")
 	flag.IntVar(&config.BatchSize, "batch", 10, "batch size for batch mode")
 	flag.IntVar(&config.Repetitions, "reps", 1, "number of repetitions")
+	flag.StringVar(&config.Format, "format", "text", "output format: text, or jsonl (batch mode only; one JSON result per line on stdout)")
+	flag.StringVar(&config.FoldedOut, "folded-out", "", "write alignment phase timings as folded stacks to file (sequential mode only)")
 	flag.Parse()
 
+	if config.SequenceLen <= 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid length: %d (must be positive)\n", config.SequenceLen)
+		os.Exit(1)
+	}
+
+	if config.FoldedOut != "" && config.Mode != "sequential" {
+		_, _ = fmt.Fprintln(os.Stderr, "The folded-out flag is only supported in sequential mode")
+		os.Exit(1)
+	}
+
+	// Validate the output format
+	switch config.Format {
+	case "text":
+	case "jsonl":
+		if config.Mode != "batch" {
+			_, _ = fmt.Fprintln(os.Stderr, "The jsonl format is only supported in batch mode")
+			os.Exit(1)
+		}
+		info = os.Stderr
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid format: %s\n", config.Format)
+		os.Exit(1)
+	}
+
 	// Start CPU profiling if requested
 	if config.CPUProfile != "" {
 		f, err := os.Create(config.CPUProfile)
@@ -58,14 +99,14 @@ func main() {
 	}
 
 	// Generate test data
-	fmt.Printf("Generating test sequences (length: %d)...\n", config.SequenceLen)
+	_, _ = fmt.Fprintf(info, "Generating test sequences (length: %d)...\n", config.SequenceLen)
 	query := data.GenerateDNASequence(config.SequenceLen)
 	reference := data.GenerateDNASequence(config.SequenceLen)
 
 	// Prepare batch data if needed
 	var references []string
 	if config.Mode == "batch" {
-		fmt.Printf("Generating %d reference sequences for batch processing...\n", config.BatchSize)
+		_, _ = fmt.Fprintf(info, "Generating %d reference sequences for batch processing...\n", config.BatchSize)
 		references = make([]string, config.BatchSize)
 		for i := range references {
 			references[i] = data.GenerateDNASequence(config.SequenceLen)
@@ -75,15 +116,16 @@ func main() {
 	// Set number of workers
 	if config.NumWorkers <= 0 {
 		config.NumWorkers = runtime.GOMAXPROCS(0)
-		fmt.Printf("Using auto worker count: %d\n", config.NumWorkers)
+		_, _ = fmt.Fprintf(info, "Using auto worker count: %d\n", config.NumWorkers)
 	}
 
 	// Variables for tracking results and performance
 	var result interface{}
+	var timings align.PhaseTimings
 	totalTime := time.Duration(0)
 
 	// Run the selected alignment mode
-	fmt.Printf("Running %s Smith-Waterman alignment (%d repetitions)...\n",
+	_, _ = fmt.Fprintf(info, "Running %s Smith-Waterman alignment (%d repetitions)...\n",
 		config.Mode, config.Repetitions)
 
 	for i := 0; i < config.Repetitions; i++ {
@@ -95,13 +137,25 @@ func main() {
 
 		switch config.Mode {
 		case "sequential":
-			result = align.SmithWaterman(query, reference)
+			res, phases := align.SmithWatermanTimed(query, reference)
+			result = res
+			timings = timings.Add(phases)
 
 		case "parallel":
 			result = align.ParallelSmithWaterman(query, reference, config.NumWorkers)
 
 		case "batch":
-			result = align.ConcurrentSmithWatermanBatch(query, references, config.NumWorkers)
+			// Stream the first repetition's results as they complete in jsonl mode
+			if config.Format == "jsonl" && i == 0 {
+				results, err := streamBatchJSONL(os.Stdout, query, references, config.NumWorkers)
+				if err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "Could not write results: %v\n", err)
+					os.Exit(1)
+				}
+				result = results
+			} else {
+				result = align.ConcurrentSmithWatermanBatch(query, references, config.NumWorkers)
+			}
 
 		default:
 			_, _ = fmt.Fprintf(os.Stderr, "Invalid mode: %s\n", config.Mode)
@@ -113,36 +167,40 @@ func main() {
 		totalTime += elapsed
 
 		// Report progress
-		fmt.Printf("Run %d/%d: %v\n", i+1, config.Repetitions, elapsed)
+		_, _ = fmt.Fprintf(info, "Run %d/%d: %v\n", i+1, config.Repetitions, elapsed)
 	}
 
 	// Report execution statistics
 	avgTime := totalTime / time.Duration(config.Repetitions)
-	fmt.Printf("\nExecution statistics:\n")
-	fmt.Printf("- Total time: %v\n", totalTime)
-	fmt.Printf("- Average time: %v per run\n", avgTime)
+	_, _ = fmt.Fprintf(info, "\nExecution statistics:\n")
+	_, _ = fmt.Fprintf(info, "- Total time: %v\n", totalTime)
+	_, _ = fmt.Fprintf(info, "- Average time: %v per run\n", avgTime)
+
+	// Write the phase timings for flame graph tools
+	if config.FoldedOut != "" {
+		if err := writeFoldedFile(config.FoldedOut, timings); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not write folded stacks: %v\n", err)
+			os.Exit(1)
+		}
+		_, _ = fmt.Fprintf(info, "Folded phase timings written to %s\n", config.FoldedOut)
+	}
 
 	// Print alignment results based on mode
 	switch config.Mode {
-	case "sequential":
+	case "sequential", "parallel":
 		res := result.(align.AlignmentResult)
-		fmt.Printf("Alignment score: %d\n", res.MaxScore)
-		printShortAlignment(res.AlignedQuery, res.AlignedRef)
-
-	case "parallel":
-		res := result.(align.ParallelAlignmentResult)
-		fmt.Printf("Alignment score: %d (at position [%d,%d])\n", res.MaxScore, res.MaxRow, res.MaxCol)
+		_, _ = fmt.Fprintf(info, "Alignment score: %d (at position [%d,%d])\n", res.MaxScore, res.MaxRow, res.MaxCol)
 		printShortAlignment(res.AlignedQuery, res.AlignedRef)
 
 	case "batch":
 		results := result.([]align.AlignmentResult)
-		fmt.Printf("Completed %d alignments\n", len(results))
+		_, _ = fmt.Fprintf(info, "Completed %d alignments\n", len(results))
 		totalScore := 0
 		for _, res := range results {
 			totalScore += res.MaxScore
 		}
-		fmt.Printf("Average alignment score: %.1f\n", float64(totalScore)/float64(len(results)))
-		fmt.Printf("First alignment score: %d\n", results[0].MaxScore)
+		_, _ = fmt.Fprintf(info, "Average alignment score: %.1f\n", float64(totalScore)/float64(len(results)))
+		_, _ = fmt.Fprintf(info, "First alignment score: %d\n", results[0].MaxScore)
 		printShortAlignment(results[0].AlignedQuery, results[0].AlignedRef)
 	}
 
@@ -164,64 +222,111 @@ func main() {
 			_, _ = fmt.Fprintf(os.Stderr, "Could not write memory profile: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Memory profile written to %s\n", config.MemProfile)
+		_, _ = fmt.Fprintf(info, "Memory profile written to %s\n", config.MemProfile)
 	}
 
 	// Report memory usage
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
-	fmt.Printf("\nMemory usage:\n")
-	fmt.Printf("- Allocated: %v MiB\n", bToMb(m.Alloc))
-	fmt.Printf("- Total allocated: %v MiB\n", bToMb(m.TotalAlloc))
-	fmt.Printf("- System memory: %v MiB\n", bToMb(m.Sys))
-	fmt.Printf("- Garbage collections: %v\n", m.NumGC)
+	_, _ = fmt.Fprintf(info, "\nMemory usage:\n")
+	_, _ = fmt.Fprintf(info, "- Allocated: %v MiB\n", bToMb(m.Alloc))
+	_, _ = fmt.Fprintf(info, "- Total allocated: %v MiB\n", bToMb(m.TotalAlloc))
+	_, _ = fmt.Fprintf(info, "- System memory: %v MiB\n", bToMb(m.Sys))
+	_, _ = fmt.Fprintf(info, "- Garbage collections: %v\n", m.NumGC)
 
 	// Additional profiling insights
-	fmt.Printf("\nProfiling insights:\n")
-	fmt.Printf("- CPU cores available: %d\n", runtime.NumCPU())
-	fmt.Printf("- Goroutines used: %d\n", runtime.NumGoroutine())
+	_, _ = fmt.Fprintf(info, "\nProfiling insights:\n")
+	_, _ = fmt.Fprintf(info, "- CPU cores available: %d\n", runtime.NumCPU())
+	_, _ = fmt.Fprintf(info, "- Goroutines used: %d\n", runtime.NumGoroutine())
 
 	// Calculate memory per base pair
-	bytesPerBase := float64(m.TotalAlloc) / float64(config.SequenceLen)
-	fmt.Printf("- Memory efficiency: %.2f bytes/base\n", bytesPerBase)
+	bytesPerBase := data.BytesPerBase(m.TotalAlloc, config.SequenceLen)
+	_, _ = fmt.Fprintf(info, "- Memory efficiency: %.2f bytes/base\n", bytesPerBase)
 
 	// Print recommended best practices
-	fmt.Printf("\nRecommendations:\n")
+	_, _ = fmt.Fprintf(info, "\nRecommendations:\n")
 	if config.SequenceLen < 500 && config.Mode == "parallel" {
-		fmt.Println("- For short sequences (<500 bp), sequential algorithm may be more efficient")
+		_, _ = fmt.Fprintln(info, "- For short sequences (<500 bp), sequential algorithm may be more efficient")
 	}
 	if config.NumWorkers > runtime.NumCPU() {
-		fmt.Println("- Worker count exceeds available CPU cores, which may reduce performance")
+		_, _ = fmt.Fprintln(info, "- Worker count exceeds available CPU cores, which may reduce performance")
 	}
-	fmt.Println("- For maximum performance, tune worker count based on your specific hardware")
-	fmt.Println("- Batch processing is recommended for aligning many sequences against a single query")
+	_, _ = fmt.Fprintln(info, "- For maximum performance, tune worker count based on your specific hardware")
+	_, _ = fmt.Fprintln(info, "- Batch processing is recommended for aligning many sequences against a single query")
+}
+
+// streamBatchJSONL aligns the query against every reference and writes one JSON
+// object per line to w as each alignment completes. Lines arrive in completion
+// order; the index field identifies the reference.
+func streamBatchJSONL(w io.Writer, query string, references []string, workers int) ([]align.AlignmentResult, error) {
+	encoder := json.NewEncoder(w)
+	results := make([]align.AlignmentResult, len(references))
+
+	var writeErr error
+	align.ConcurrentSmithWatermanBatchFunc(query, references, workers, func(index int, result align.AlignmentResult) {
+		results[index] = result
+
+		// Keep aligning after a write error, but report the first one
+		if writeErr != nil {
+			return
+		}
+		writeErr = encoder.Encode(batchRecord{
+			Index:    index,
+			Score:    result.MaxScore,
+			Identity: result.Identity(),
+		})
+	})
+
+	return results, writeErr
+}
+
+// writeFoldedFile writes the phase timings as folded stacks to the named file.
+func writeFoldedFile(path string, timings align.PhaseTimings) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFolded(f, timings); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeFolded writes the phase timings in the folded stack format read by
+// flamegraph.pl and speedscope: one "frame;frame value" line per phase, where
+// the value is the total time in microseconds.
+func writeFolded(w io.Writer, timings align.PhaseTimings) error {
+	phases := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"matrix_fill", timings.Fill},
+		{"traceback", timings.Traceback},
+	}
+
+	for _, phase := range phases {
+		if _, err := fmt.Fprintf(w, "smith_waterman;%s %d\n", phase.name, phase.duration.Microseconds()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // printShortAlignment displays the first part of an alignment
 func printShortAlignment(query, reference string) {
 	maxLen := 50
-	if len(query) > maxLen {
-		query = query[:maxLen] + "..."
-		reference = reference[:maxLen] + "..."
-	}
-
-	fmt.Println("\nAlignment (truncated):")
-	fmt.Printf("Query:     %s\n", query)
-
-	// Generate match line
-	matchLine := make([]rune, len(query))
-	for i := 0; i < len(query) && i < len(reference); i++ {
-		if query[i] == reference[i] && query[i] != '-' && reference[i] != '-' {
-			matchLine[i] = '|' // Match
-		} else if query[i] != '-' && reference[i] != '-' {
-			matchLine[i] = '.' // Mismatch
-		} else {
-			matchLine[i] = ' ' // Gap
-		}
+	truncated := len(query) > maxLen
+	if truncated {
+		query, reference = query[:maxLen], reference[:maxLen]
 	}
 
-	fmt.Printf("           %s\n", string(matchLine))
-	fmt.Printf("Reference: %s\n", reference)
+	_, _ = fmt.Fprintln(info, "\nAlignment (truncated):")
+	_, _ = fmt.Fprint(info, align.FormatPairwise(align.AlignmentResult{AlignedQuery: query, AlignedRef: reference}, 0))
+	if truncated {
+		_, _ = fmt.Fprintln(info, "...")
+	}
 }
 
 // bToMb converts bytes to megabytes
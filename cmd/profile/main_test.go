@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"pgfp/align"
+	"pgfp/data"
+)
+
+// TestStreamBatchJSONL checks that batch output is valid line-delimited JSON
+func TestStreamBatchJSONL(t *testing.T) {
+	query := data.GenerateDNASequence(100)
+	references := make([]string, 5)
+	for i := range references {
+		references[i] = data.GenerateDNASequence(100)
+	}
+
+	var buf bytes.Buffer
+	results, err := streamBatchJSONL(&buf, query, references, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(references) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(references), len(lines), buf.String())
+	}
+
+	seen := make(map[int]bool)
+	for _, line := range lines {
+		var record batchRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Errorf("Line is not valid JSON: %q (%v)", line, err)
+			continue
+		}
+		if record.Index < 0 || record.Index >= len(references) || seen[record.Index] {
+			t.Errorf("Unexpected or duplicate index in line %q", line)
+			continue
+		}
+		seen[record.Index] = true
+
+		if record.Score != results[record.Index].MaxScore {
+			t.Errorf("Line %q has score %d, expected %d", line, record.Score, results[record.Index].MaxScore)
+		}
+	}
+}
+
+// TestWriteFolded checks that every line is a semicolon-separated stack followed by a count
+func TestWriteFolded(t *testing.T) {
+	timings := align.PhaseTimings{Fill: 1500 * time.Microsecond, Traceback: 20 * time.Microsecond}
+
+	var buf bytes.Buffer
+	if err := writeFolded(&buf, timings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "smith_waterman;matrix_fill 1500\nsmith_waterman;traceback 20\n"
+	if buf.String() != expected {
+		t.Errorf("Folded output was:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	// Real timings must also produce well-formed lines
+	_, timings = align.SmithWatermanTimed(data.GenerateDNASequence(200), data.GenerateDNASequence(200))
+	buf.Reset()
+	if err := writeFolded(&buf, timings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	line := regexp.MustCompile(`^[^; ]+(;[^; ]+)* [0-9]+$`)
+	for _, l := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if !line.MatchString(l) {
+			t.Errorf("Line %q is not in folded stack format", l)
+		}
+	}
+}
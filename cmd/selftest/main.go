@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"pgfp/align"
+	"pgfp/data"
+)
+
+// consistencyLength is the sequence length for the sequential/parallel agreement check
+const consistencyLength = 500
+
+func main() {
+	if !runSelfTest(os.Stdout) {
+		os.Exit(1)
+	}
+}
+
+// runSelfTest prints the environment, runs the known alignment cases through the
+// sequential and parallel aligners, and checks that the two agree on a longer input.
+// It reports whether every check passed.
+func runSelfTest(w io.Writer) bool {
+	_, _ = fmt.Fprintf(w, "pgfp self-test (align %s)\n", align.Version)
+	_, _ = fmt.Fprintf(w, "Go version: %s\n", runtime.Version())
+	_, _ = fmt.Fprintf(w, "Platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	_, _ = fmt.Fprintf(w, "CPUs:       %d (GOMAXPROCS %d)\n\n", runtime.NumCPU(), runtime.GOMAXPROCS(0))
+
+	passed, failed := 0, 0
+	report := func(name string, err error) {
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(w, "FAIL  %s: %v\n", name, err)
+			return
+		}
+		passed++
+		_, _ = fmt.Fprintf(w, "PASS  %s\n", name)
+	}
+
+	for _, tc := range align.KnownCases() {
+		report("sequential "+tc.Name, tc.Check(align.SmithWaterman(tc.Query, tc.Reference)))
+
+		report("parallel "+tc.Name, tc.Check(align.ParallelSmithWaterman(tc.Query, tc.Reference, 0)))
+	}
+
+	report(fmt.Sprintf("sequential and parallel agree on %dbp", consistencyLength), checkConsistency())
+
+	_, _ = fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, failed)
+	return failed == 0
+}
+
+// checkConsistency aligns two related sequences long enough to use the parallel
+// wave-front fill and checks that it matches the sequential result.
+func checkConsistency() error {
+	query := data.GenerateDNASequence(consistencyLength)
+	reference := data.CreateMutatedSequence(query, 0.05)
+
+	sequential := align.SmithWaterman(query, reference)
+	parallel := align.ParallelSmithWaterman(query, reference, 0)
+
+	if sequential.MaxScore != parallel.MaxScore {
+		return fmt.Errorf("sequential score %d, parallel score %d", sequential.MaxScore, parallel.MaxScore)
+	}
+	if err := align.VerifyAlignment(sequential, query, reference); err != nil {
+		return fmt.Errorf("sequential alignment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"pgfp/align"
+)
+
+// TestRunSelfTest checks that every built-in case passes and is reported
+func TestRunSelfTest(t *testing.T) {
+	var buf bytes.Buffer
+	if !runSelfTest(&buf) {
+		t.Fatalf("Expected the self-test to pass, got:\n%s", buf.String())
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Go version:") {
+		t.Errorf("Expected environment information, got:\n%s", output)
+	}
+	if want := 2*len(align.KnownCases()) + 1; strings.Count(output, "PASS  ") != want {
+		t.Errorf("Expected %d PASS lines, got:\n%s", want, output)
+	}
+	if strings.Contains(output, "FAIL") {
+		t.Errorf("Expected no failures, got:\n%s", output)
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -18,21 +22,18 @@ import (
 	"pgfp/data"
 )
 
-// VisualizationData represents alignment data for visualization
-type VisualizationData struct {
-	AlignedQuery string     `json:"alignedQuery"`
-	AlignedRef   string     `json:"alignedRef"`
-	Score        int        `json:"score"`
-	Mutations    []Mutation `json:"mutations"`
-}
+// normalizeIndels enables left-alignment of indels before mutation detection
+var normalizeIndels bool
+
+// similarityWindow is the window used to colour columns by local identity. Colouring is
+// opt-in: the default 0 keeps the plain match/mismatch view
+var similarityWindow int
 
-// Mutation represents a detected mutation
-type Mutation struct {
-	Type     string `json:"type"`     // "snp", "insertion", "deletion"
-	Position int    `json:"position"` // Position in the original sequence
-	Length   int    `json:"length"`   // Length of the mutation (for insertions/deletions)
-	Original string `json:"original"` // Original bases
-	Mutated  string `json:"mutated"`  // Mutated bases
+// ColumnView is one alignment column coloured by the local identity around it
+type ColumnView struct {
+	Query string
+	Ref   string
+	Color string
 }
 
 func main() {
@@ -40,12 +41,18 @@ func main() {
 	outputPath := flag.String("output", "", "Path to output HTML file")
 	querySeq := flag.String("query", "", "Query DNA sequence")
 	refSeq := flag.String("reference", "", "Reference DNA sequence")
+	queryFile := flag.String("query-file", "", "FASTA file whose first record is the query")
+	refFile := flag.String("reference-file", "", "FASTA file whose first record is the reference")
 	generateRandom := flag.Bool("random", false, "Generate random sequences")
 	seqLength := flag.Int("length", 1000, "Length for random sequences")
 	useParallel := flag.Bool("parallel", false, "Use parallel Smith-Waterman")
 	workers := flag.Int("workers", 0, "Number of workers for parallel execution (0 = auto)")
 	runServer := flag.Bool("server", false, "Run as web server")
 	serverPort := flag.Int("port", 8081, "Port for web server")
+	flag.IntVar(&similarityWindow, "similarity-window", 0, "Window for colouring columns by local identity; opt-in, try 21 (default 0 keeps the plain match/mismatch view)")
+	flag.BoolVar(&normalizeIndels, "normalize-indels", false, "Left-align indels before detecting mutations so positions are canonical")
+	compress := flag.Bool("gzip", false, "Write the HTML file gzip-compressed (.html.gz)")
+	minMatchRun := flag.Int("min-match-run", 0, "Require a run of at least this many consecutive matches (0 = no requirement)")
 
 	flag.Parse()
 
@@ -55,6 +62,10 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+	if *generateRandom && *seqLength <= 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: invalid length %d (must be positive)\n", *seqLength)
+		os.Exit(1)
+	}
 
 	// Get sequences
 	var query, reference string
@@ -63,16 +74,46 @@ func main() {
 		query = data.GenerateDNASequence(*seqLength)
 		reference = data.GenerateDNASequence(*seqLength)
 	} else {
-		query = *querySeq
-		reference = *refSeq
+		var err error
+		if query, err = sequenceFromFlags("query", *querySeq, *queryFile); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		if reference, err = sequenceFromFlags("reference", *refSeq, *refFile); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
 
 		if query == "" || reference == "" {
-			_, _ = fmt.Fprintln(os.Stderr, "Error: must provide both query and reference sequences, or use -random flag")
+			_, _ = fmt.Fprintln(os.Stderr, "Error: must provide both query and reference sequences (inline or as FASTA files), or use -random flag")
 			flag.Usage()
 			os.Exit(1)
 		}
 	}
 
+	// Reject non-ASCII input, which the byte-indexed aligner cannot handle
+	if err := align.ValidateASCII("query", query); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if err := align.ValidateASCII("reference", reference); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	// Reject anything that is not a DNA base, which would otherwise score as a mismatch
+	if err := align.ValidateDNA("query", query); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if err := align.ValidateDNA("reference", reference); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	// ValidateDNA accepts lowercase bases, but the aligners compare bases case-sensitively
+	query, reference = strings.ToUpper(query), strings.ToUpper(reference)
+
 	// Perform alignment
 	var alignResult align.AlignmentResult
 	startTime := time.Now()
@@ -85,13 +126,7 @@ func main() {
 		} else {
 			log.Printf("Using %d workers", *workers)
 		}
-		parallelResult := align.ParallelSmithWaterman(query, reference, *workers)
-		alignResult = align.AlignmentResult{
-			ScoreMatrix:  parallelResult.ScoreMatrix,
-			MaxScore:     parallelResult.MaxScore,
-			AlignedQuery: parallelResult.AlignedQuery,
-			AlignedRef:   parallelResult.AlignedRef,
-		}
+		alignResult = align.ParallelSmithWaterman(query, reference, *workers)
 	} else {
 		log.Println("Running sequential Smith-Waterman alignment...")
 		alignResult = align.SmithWaterman(query, reference)
@@ -100,6 +135,15 @@ func main() {
 	elapsedTime := time.Since(startTime)
 	log.Printf("Alignment completed in %v", elapsedTime)
 	log.Printf("Alignment score: %d", alignResult.MaxScore)
+	if alignResult.NoAlignment {
+		log.Println("No alignment: the sequences share no positive-scoring region")
+	}
+
+	// Skip visualizing alignments built only from scattered matches
+	if !alignResult.HasMatchRun(*minMatchRun) {
+		log.Printf("No significant alignment: no run of %d consecutive matches", *minMatchRun)
+		return
+	}
 
 	// Handle the result based on mode
 	if *runServer {
@@ -111,10 +155,13 @@ func main() {
 		}
 	} else {
 		// Generate HTML file
-		outPath := *outputPath
+		outPath := strings.TrimSuffix(*outputPath, ".gz")
 		if !strings.HasSuffix(outPath, ".html") {
 			outPath += ".html"
 		}
+		if *compress {
+			outPath += ".gz"
+		}
 
 		// Ensure the output directory exists
 		dir := filepath.Dir(outPath)
@@ -126,7 +173,7 @@ func main() {
 		}
 
 		log.Printf("Generating visualization to %s...", outPath)
-		err := generateVisualization(alignResult, outPath)
+		err := generateVisualization(alignResult, outPath, *compress)
 		if err != nil {
 			log.Fatalf("Error generating visualization: %v", err)
 		}
@@ -135,14 +182,70 @@ func main() {
 	}
 }
 
+// sequenceFromFlags returns a sequence given inline or as a FASTA file. The file's
+// first record is used; giving both forms is an error.
+func sequenceFromFlags(name, inline, path string) (string, error) {
+	if path == "" {
+		return inline, nil
+	}
+	if inline != "" {
+		return "", fmt.Errorf("use either -%s or -%s-file, not both", name, name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := data.ReadFASTA(f)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return "", fmt.Errorf("%s contains no FASTA records", path)
+	}
+
+	return records[0].Sequence, nil
+}
+
 // generateVisualization creates an HTML visualization of an alignment and saves it to a file
-func generateVisualization(alignResult align.AlignmentResult, outputPath string) error {
-	// Create a visualization d object
-	visualData := VisualizationData{
-		AlignedQuery: alignResult.AlignedQuery,
-		AlignedRef:   alignResult.AlignedRef,
-		Score:        alignResult.MaxScore,
-		Mutations:    detectMutations(alignResult.AlignedQuery, alignResult.AlignedRef),
+func generateVisualization(alignResult align.AlignmentResult, outputPath string, compress bool) error {
+	// Create the output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			log.Printf("Error closing output file: %v", err)
+		}
+	}(file)
+
+	if !compress {
+		return renderVisualization(file, alignResult)
+	}
+
+	// Compress the HTML as it is written
+	gz := gzip.NewWriter(file)
+	if err := renderVisualization(gz, alignResult); err != nil {
+		_ = gz.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error compressing output: %v", err)
+	}
+
+	return nil
+}
+
+// renderVisualization writes the HTML visualization of an alignment to w
+func renderVisualization(w io.Writer, alignResult align.AlignmentResult) error {
+	// Export the alignment in the same format results are saved in
+	visualData := align.NewAlignmentExport(alignResult)
+	if normalizeIndels {
+		visualData.Mutations = align.DetectMutationsNormalized(alignResult.AlignedQuery, alignResult.AlignedRef)
 	}
 
 	// Convert to JSON for use in the template
@@ -158,6 +261,7 @@ func generateVisualization(alignResult align.AlignmentResult, outputPath string)
 		Score        int
 		Timestamp    string
 		MatchLine    string
+		Columns      []ColumnView
 		JSONData     template.JS
 	}{
 		AlignedQuery: alignResult.AlignedQuery,
@@ -165,6 +269,7 @@ func generateVisualization(alignResult align.AlignmentResult, outputPath string)
 		Score:        alignResult.MaxScore,
 		Timestamp:    time.Now().Format("2006-01-02 15:04:05"),
 		MatchLine:    generateMatchLine(alignResult.AlignedQuery, alignResult.AlignedRef),
+		Columns:      similarityColumns(alignResult, similarityWindow),
 		JSONData:     template.JS(jsonData),
 	}
 
@@ -174,20 +279,8 @@ func generateVisualization(alignResult align.AlignmentResult, outputPath string)
 		return fmt.Errorf("error parsing template: %v", err)
 	}
 
-	// Create the output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("error creating output file: %v", err)
-	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			log.Printf("Error closing output file: %v", err)
-		}
-	}(file)
-
 	// Execute the template
-	err = tmpl.Execute(file, d)
+	err = tmpl.Execute(w, d)
 	if err != nil {
 		return fmt.Errorf("error executing template: %v", err)
 	}
@@ -197,137 +290,103 @@ func generateVisualization(alignResult align.AlignmentResult, outputPath string)
 
 // serveVisualization starts a web server to visualize alignments
 func serveVisualization(alignResult align.AlignmentResult, port int) error {
-	// Create a visualization data object
-	visualData := VisualizationData{
-		AlignedQuery: alignResult.AlignedQuery,
-		AlignedRef:   alignResult.AlignedRef,
-		Score:        alignResult.MaxScore,
-		Mutations:    detectMutations(alignResult.AlignedQuery, alignResult.AlignedRef),
-	}
-
 	// Create a handler for serving the visualization
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// Convert to JSON for use in the template
-		jsonData, err := json.Marshal(visualData)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error marshaling d: %v", err), http.StatusInternalServerError)
+	http.HandleFunc("/", visualizationHandler(alignResult))
+
+	// Start the server
+	addr := ":" + strconv.Itoa(port)
+	log.Printf("Starting visualization server at http://localhost%s", addr)
+	return http.ListenAndServe(addr, nil)
+}
+
+// visualizationHandler serves the HTML visualization of an alignment, gzip-compressed
+// when the client accepts it
+func visualizationHandler(alignResult align.AlignmentResult) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Render fully before writing so errors can still be reported
+		var page bytes.Buffer
+		if err := renderVisualization(&page, alignResult); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		// Create template d
-		d := struct {
-			AlignedQuery string
-			AlignedRef   string
-			Score        int
-			Timestamp    string
-			MatchLine    string
-			JSONData     template.JS
-		}{
-			AlignedQuery: alignResult.AlignedQuery,
-			AlignedRef:   alignResult.AlignedRef,
-			Score:        alignResult.MaxScore,
-			Timestamp:    time.Now().Format("2006-01-02 15:04:05"),
-			MatchLine:    generateMatchLine(alignResult.AlignedQuery, alignResult.AlignedRef),
-			JSONData:     template.JS(jsonData),
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			_, _ = w.Write(page.Bytes())
+			return
 		}
 
-		// Parse and execute the template
-		tmpl, err := template.New("visualization").Parse(visualizationTemplate)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error parsing template: %v", err), http.StatusInternalServerError)
-			return
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write(page.Bytes())
+		if err := gz.Close(); err != nil {
+			log.Printf("Error compressing response: %v", err)
 		}
+	}
+}
 
-		err = tmpl.Execute(w, d)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error executing template: %v", err), http.StatusInternalServerError)
-			return
+// acceptsGzip reports whether the request's Accept-Encoding header allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		// Ignore parameters such as ";q=0.8", but honor an explicit refusal
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			return qualityOf(params) != 0
 		}
-	})
+	}
+	return false
+}
 
-	// Start the server
-	addr := ":" + strconv.Itoa(port)
-	log.Printf("Starting visualization server at http://localhost%s", addr)
-	return http.ListenAndServe(addr, nil)
+// qualityOf returns the q value in an Accept-Encoding entry's parameters, or 1 when
+// it is missing or malformed. Any spelling of zero ("q=0", "q=0.0", "q=0.000") is 0.
+func qualityOf(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+		if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return q
+		}
+	}
+	return 1
 }
 
-// detectMutations analyzes aligned sequences to find mutations
-func detectMutations(alignedQuery, alignedRef string) []Mutation {
-	mutations := []Mutation{}
-
-	// Keep track of positions in the original sequences
-	queryPos, refPos := 0, 0
-
-	// Variables to track potential insertions/deletions
-	var currentMutation *Mutation
-
-	for i := 0; i < len(alignedQuery) && i < len(alignedRef); i++ {
-		if alignedQuery[i] == '-' {
-			// Gap in query = deletion
-			if currentMutation == nil || currentMutation.Type != "deletion" {
-				// Start a new deletion
-				currentMutation = &Mutation{
-					Type:     "deletion",
-					Position: refPos,
-					Original: string(alignedRef[i]),
-					Mutated:  "-",
-					Length:   1,
-				}
-				mutations = append(mutations, *currentMutation)
-			} else {
-				// Continue the current deletion
-				lastIdx := len(mutations) - 1
-				mutations[lastIdx].Original += string(alignedRef[i])
-				mutations[lastIdx].Length++
-			}
-			refPos++
-		} else if alignedRef[i] == '-' {
-			// Gap in reference = insertion
-			if currentMutation == nil || currentMutation.Type != "insertion" {
-				// Start a new insertion
-				currentMutation = &Mutation{
-					Type:     "insertion",
-					Position: queryPos,
-					Original: "-",
-					Mutated:  string(alignedQuery[i]),
-					Length:   1,
-				}
-				mutations = append(mutations, *currentMutation)
-			} else {
-				// Continue the current insertion
-				lastIdx := len(mutations) - 1
-				mutations[lastIdx].Mutated += string(alignedQuery[i])
-				mutations[lastIdx].Length++
-			}
-			queryPos++
-		} else if alignedQuery[i] != alignedRef[i] {
-			// Mismatch = SNP
-			mutations = append(mutations, Mutation{
-				Type:     "snp",
-				Position: queryPos,
-				Original: string(alignedRef[i]),
-				Mutated:  string(alignedQuery[i]),
-				Length:   1,
-			})
-			queryPos++
-			refPos++
-			currentMutation = nil
-		} else {
-			// Match = no mutation
-			queryPos++
-			refPos++
-			currentMutation = nil
+// similarityColumns colours every alignment column on a red-to-green gradient by
+// the identity of the window around it. It returns nil when window is not positive.
+func similarityColumns(alignResult align.AlignmentResult, window int) []ColumnView {
+	if window <= 0 {
+		return nil
+	}
+
+	profile := alignResult.SimilarityProfile(window)
+	columns := make([]ColumnView, len(profile))
+	for i, identity := range profile {
+		columns[i] = ColumnView{
+			Query: alignResult.AlignedQuery[i : i+1],
+			Ref:   alignResult.AlignedRef[i : i+1],
+			Color: identityColor(identity),
 		}
 	}
 
-	return mutations
+	return columns
+}
+
+// identityColor maps an identity between 0 and 1 to a hex colour from red (0) to green (1)
+func identityColor(identity float64) string {
+	identity = min(max(identity, 0), 1)
+	red := int(math.Round(200 * (1 - identity)))
+	green := int(math.Round(160 * identity))
+	return fmt.Sprintf("#%02x%02x00", red, green)
 }
 
 // generateMatchLine creates a string representing matches/mismatches/gaps
 func generateMatchLine(seq1, seq2 string) string {
-	matchLine := make([]byte, len(seq1))
+	// Size to the shorter row so a malformed alignment never leaves NUL bytes in the output
+	matchLine := make([]byte, min(len(seq1), len(seq2)))
 
-	for i := 0; i < len(seq1) && i < len(seq2); i++ {
+	for i := range matchLine {
 		if seq1[i] == '-' || seq2[i] == '-' {
 			matchLine[i] = ' ' // Gap
 		} else if seq1[i] == seq2[i] {
@@ -390,10 +449,18 @@ const visualizationTemplate = `<!DOCTYPE html>
     
     <h2>Alignment</h2>
     <div class="alignment-container">
+{{- if .Columns}}
+        <pre class="alignment-row">Query:  {{range .Columns}}<span style="color: {{.Color}}">{{.Query}}</span>{{end}}</pre>
+        <pre class="alignment-row">Match:  {{.MatchLine}}</pre>
+        <pre class="alignment-row">Ref:    {{range .Columns}}<span style="color: {{.Color}}">{{.Ref}}</span>{{end}}</pre>
+    </div>
+    <div class="info">Bases are coloured by local identity, from red (divergent) to green (conserved).</div>
+{{- else}}
         <pre class="alignment-row">Query:  {{.AlignedQuery}}</pre>
         <pre class="alignment-row">Match:  {{.MatchLine}}</pre>
         <pre class="alignment-row">Ref:    {{.AlignedRef}}</pre>
     </div>
+{{- end}}
     
     <h2>Detected Mutations</h2>
     <div id="mutations-container">
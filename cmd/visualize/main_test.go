@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"pgfp/align"
+)
+
+// TestGenerateVisualizationGzip checks that the gzipped file decompresses to the plain HTML
+func TestGenerateVisualizationGzip(t *testing.T) {
+	result := align.SmithWaterman("GATTACAGATTACA", "GATTTACAGATACA")
+	dir := t.TempDir()
+
+	plainPath := filepath.Join(dir, "report.html")
+	gzipPath := filepath.Join(dir, "report.html.gz")
+	if err := generateVisualization(result, plainPath, false); err != nil {
+		t.Fatalf("Could not write plain HTML: %v", err)
+	}
+	if err := generateVisualization(result, gzipPath, true); err != nil {
+		t.Fatalf("Could not write gzipped HTML: %v", err)
+	}
+
+	plain, err := os.ReadFile(plainPath)
+	if err != nil {
+		t.Fatalf("Could not read plain HTML: %v", err)
+	}
+	compressed, err := os.ReadFile(gzipPath)
+	if err != nil {
+		t.Fatalf("Could not read gzipped HTML: %v", err)
+	}
+
+	decompressed := gunzip(t, compressed)
+
+	// The timestamp can tick over between the two renders, so compare everything else
+	timestamp := regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}`)
+	if !bytes.Equal(timestamp.ReplaceAll(decompressed, nil), timestamp.ReplaceAll(plain, nil)) {
+		t.Error("Decompressed output does not match the plain HTML")
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(decompressed), []byte("<!DOCTYPE html>")) {
+		t.Error("Decompressed output is not an HTML document")
+	}
+}
+
+// TestVisualizationHandlerGzip checks that the server compresses only for clients that accept gzip
+func TestVisualizationHandlerGzip(t *testing.T) {
+	handler := visualizationHandler(align.SmithWaterman("GATTACA", "GATTACA"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.9")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected a gzip response, got Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+	if page := gunzip(t, rec.Body.Bytes()); !bytes.Contains(page, []byte("GATTACA")) {
+		t.Error("Decompressed response does not contain the alignment")
+	}
+
+	// Clients that do not accept gzip get plain HTML
+	for _, encoding := range []string{"", "deflate", "gzip;q=0", "gzip;q=0.0", "gzip; q=0.00", "br, GZIP;q=0.000"} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		rec = httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Accept-Encoding %q: expected an uncompressed response", encoding)
+		}
+		if !bytes.Contains(rec.Body.Bytes(), []byte("GATTACA")) {
+			t.Errorf("Accept-Encoding %q: response does not contain the alignment", encoding)
+		}
+	}
+}
+
+// gunzip decompresses data, failing the test on error
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Output is not gzip data: %v", err)
+	}
+	decompressed, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Could not decompress output: %v", err)
+	}
+	return decompressed
+}
+
+// TestGenerateMatchLineUnequal checks that rows of different lengths produce no NUL bytes
+func TestGenerateMatchLineUnequal(t *testing.T) {
+	if line := generateMatchLine("GATTACA", "GAT-AC"); line != "||| ||" {
+		t.Errorf("Match line was %q, expected %q", line, "||| ||")
+	}
+	if line := generateMatchLine("GA", "GATTACA"); line != "||" {
+		t.Errorf("Match line was %q, expected %q", line, "||")
+	}
+}
+
+// TestSimilarityColumns checks the gradient endpoints and that coloured columns reach the HTML
+func TestSimilarityColumns(t *testing.T) {
+	if identityColor(1) != "#00a000" || identityColor(0) != "#c80000" {
+		t.Errorf("Unexpected gradient endpoints %s and %s", identityColor(1), identityColor(0))
+	}
+
+	result := align.AlignmentResult{
+		AlignedQuery: "GATTACAGAT" + "AAAAAAAAAA",
+		AlignedRef:   "GATTACAGAT" + "CCCCCCCCCC",
+	}
+
+	columns := similarityColumns(result, 4)
+	if len(columns) != 20 {
+		t.Fatalf("Expected 20 columns, got %d", len(columns))
+	}
+	if columns[0].Color != identityColor(1) || columns[19].Color != identityColor(0) {
+		t.Errorf("Expected the conserved end green and the divergent end red, got %s and %s",
+			columns[0].Color, columns[19].Color)
+	}
+	if similarityColumns(result, 0) != nil {
+		t.Error("Expected no columns when the gradient is disabled")
+	}
+
+	saved := similarityWindow
+	defer func() { similarityWindow = saved }()
+
+	// Without -similarity-window the report keeps the plain match/mismatch view
+	similarityWindow = 0
+	var plain bytes.Buffer
+	if err := renderVisualization(&plain, result); err != nil {
+		t.Fatalf("Could not render: %v", err)
+	}
+	if bytes.Contains(plain.Bytes(), []byte(`<span style="color:`)) {
+		t.Error("Expected no gradient when the window is 0")
+	}
+
+	similarityWindow = 4
+	var page bytes.Buffer
+	if err := renderVisualization(&page, result); err != nil {
+		t.Fatalf("Could not render: %v", err)
+	}
+	if !bytes.Contains(page.Bytes(), []byte(`<span style="color: #c80000">C</span>`)) {
+		t.Error("Rendered HTML does not contain the coloured divergent columns")
+	}
+}
+
+// TestSequenceFromFlags checks reading the query from a FASTA file and rejecting conflicting flags
+func TestSequenceFromFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "query.fa")
+	if err := os.WriteFile(path, []byte(">q1 sample\nGATT\nACA\n>q2\nTTTT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	seq, err := sequenceFromFlags("query", "", path)
+	if err != nil || seq != "GATTACA" {
+		t.Errorf("Expected the first record GATTACA, got %q (err %v)", seq, err)
+	}
+
+	if seq, err := sequenceFromFlags("query", "ACGT", ""); err != nil || seq != "ACGT" {
+		t.Errorf("Expected the inline sequence, got %q (err %v)", seq, err)
+	}
+	if _, err := sequenceFromFlags("query", "ACGT", path); err == nil {
+		t.Error("Expected an error when both an inline sequence and a file are given")
+	}
+	if _, err := sequenceFromFlags("query", "", filepath.Join(t.TempDir(), "missing.fa")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
+	"mime"
 	"net/http"
 	"runtime"
-	_ "strconv"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,22 +32,41 @@ type AlignmentRequest struct {
 	RandomLength   int    `json:"randomLength"`
 	BatchSize      int    `json:"batchSize"`
 	UseBatch       bool   `json:"useBatch"`
+	MinMatchRun    int    `json:"minMatchRun"`
+	WrapBlocks     bool   `json:"wrapBlocks"`
+	BlockWidth     int    `json:"blockWidth"`
+	// References are aligned against the query as a batch. When empty, a batch
+	// request must set GenerateReferences to align against synthetic variants
+	// of Reference instead.
+	References         []string `json:"references"`
+	GenerateReferences bool     `json:"generateReferences"`
+	IncludeProvenance  bool     `json:"includeProvenance"`
+	// NormalizeIndels left-aligns indels before listing mutations, so an indel
+	// in a repeat is reported at the same position however its gap was placed.
+	NormalizeIndels bool `json:"normalizeIndels"`
 }
 
 // AlignmentResponse represents the response to an alignment request
 type AlignmentResponse struct {
-	QuerySequence   string          `json:"querySequence"`
-	RefSequence     string          `json:"refSequence"`
-	AlignedQuery    string          `json:"alignedQuery"`
-	AlignedRef      string          `json:"alignedRef"`
-	Score           int             `json:"score"`
-	ExecutionTime   string          `json:"executionTime"`
-	ExecutionTimeMs float64         `json:"executionTimeMs"`
-	MemoryUsageMB   uint64          `json:"memoryUsageMB"`
-	IsParallel      bool            `json:"isParallel"`
-	Workers         int             `json:"workers"`
-	BatchResults    []BatchResult   `json:"batchResults,omitempty"`
-	PerformanceData PerformanceData `json:"performanceData"`
+	QuerySequence   string                 `json:"querySequence"`
+	RefSequence     string                 `json:"refSequence"`
+	AlignedQuery    string                 `json:"alignedQuery"`
+	AlignedRef      string                 `json:"alignedRef"`
+	Score           int                    `json:"score"`
+	ExecutionTime   string                 `json:"executionTime"`
+	ExecutionTimeMs float64                `json:"executionTimeMs"`
+	MemoryUsageMB   uint64                 `json:"memoryUsageMB"`
+	IsParallel      bool                   `json:"isParallel"`
+	Workers         int                    `json:"workers"`
+	BatchResults    []BatchResult          `json:"batchResults,omitempty"`
+	PerformanceData PerformanceData        `json:"performanceData"`
+	NoAlignment     bool                   `json:"noAlignment,omitempty"`
+	NoSignificant   bool                   `json:"noSignificantAlignment,omitempty"`
+	Message         string                 `json:"message,omitempty"`
+	Blocks          []align.AlignmentBlock `json:"blocks,omitempty"`
+	Mutations       []align.Mutation       `json:"mutations,omitempty"`
+	CIGAR           string                 `json:"cigar,omitempty"`
+	Provenance      *align.Provenance      `json:"provenance,omitempty"`
 }
 
 // BatchResult represents the result of a batch alignment
@@ -49,6 +75,8 @@ type BatchResult struct {
 	Score        int    `json:"score"`
 	AlignedQuery string `json:"alignedQuery"`
 	AlignedRef   string `json:"alignedRef"`
+	// NoSignificant is set when the alignment lacks the requested run of matches
+	NoSignificant bool `json:"noSignificantAlignment,omitempty"`
 }
 
 // PerformanceData represents performance metrics
@@ -61,17 +89,122 @@ type PerformanceData struct {
 	GcRuns         uint32  `json:"gcRuns"`
 }
 
+// AlignedPair is one alignment supplied for comparison
+type AlignedPair struct {
+	AlignedQuery string `json:"alignedQuery"`
+	AlignedRef   string `json:"alignedRef"`
+}
+
+// CompareRequest represents a request to compare two alignments of the same sequences
+type CompareRequest struct {
+	A AlignedPair `json:"a"`
+	B AlignedPair `json:"b"`
+}
+
+// CompareResponse reports whether two alignments are concordant and where they differ
+type CompareResponse struct {
+	Concordant       bool  `json:"concordant"`       // Same length and same score
+	Identical        bool  `json:"identical"`        // Exactly the same aligned strings
+	ScoreA           int   `json:"scoreA"`           // Rescored score of the first alignment
+	ScoreB           int   `json:"scoreB"`           // Rescored score of the second alignment
+	DifferingColumns []int `json:"differingColumns"` // Columns where the two alignments disagree
+}
+
+// StreamRequest is one line of an NDJSON request to /api/v1/stream
+type StreamRequest struct {
+	ID        string `json:"id,omitempty"` // Echoed in the response so clients can match them up
+	Query     string `json:"query"`
+	Reference string `json:"reference"`
+}
+
+// StreamResponse is one line of the NDJSON response from /api/v1/stream
+type StreamResponse struct {
+	ID           string `json:"id,omitempty"`
+	AlignedQuery string `json:"alignedQuery,omitempty"`
+	AlignedRef   string `json:"alignedRef,omitempty"`
+	Score        int    `json:"score"`
+	QueryStart   int    `json:"queryStart"`
+	QueryEnd     int    `json:"queryEnd"`
+	RefStart     int    `json:"refStart"`
+	RefEnd       int    `json:"refEnd"`
+	NoAlignment  bool   `json:"noAlignment,omitempty"`
+	Error        string `json:"error,omitempty"` // Set instead of the result when the request fails
+}
+
+// BatchProgressEvent is the data of one "result" event sent by /align/stream
+type BatchProgressEvent struct {
+	Index int `json:"index"` // Position of the reference in the batch
+	Score int `json:"score"`
+	Done  int `json:"done"`  // Number of references aligned so far
+	Total int `json:"total"` // Number of references in the batch
+}
+
 // ServerConfig holds the server configuration
 type ServerConfig struct {
-	Port int
+	Port                    int
+	MaxAlignmentLength      int // Maximum number of alignment columns returned (0 = unlimited)
+	MaxStreamSequenceLength int // Maximum length of each streamed sequence (0 = unlimited)
+	MaxAPISequenceLength    int // Maximum length of each sequence sent to /api/v1/align (0 = unlimited)
 }
 
+// maxAlignmentLength caps the length of alignments produced by the handlers,
+// protecting the server against inputs that produce huge tracebacks. Sequential
+// alignments abort their traceback at the cap. The parallel aligners cannot, so
+// parallel requests are rejected up front when the query and reference together
+// are longer than the cap, even if their actual alignment would have fit.
+// Set with -max-alignment-length.
+var maxAlignmentLength = 100000
+
+// maxStreamSequenceLength is the longest query or reference accepted by the
+// streaming endpoints, which align many pairs per request. Set with
+// -max-stream-sequence-length.
+var maxStreamSequenceLength = 10000
+
+// maxStreamBatchSize is the most references /align/stream aligns in one request,
+// whether sent as reference parameters or generated from batchSize.
+const maxStreamBatchSize = 1000
+
+// maxStreamLineBytes is the longest line read from an NDJSON request stream.
+const maxStreamLineBytes = 1 << 20
+
+// maxAPISequenceLength is the longest query or reference accepted by /api/v1/align,
+// which any origin may call. Set with -max-api-sequence-length.
+var maxAPISequenceLength = 10000
+
+// maxAPIBodyBytes is the largest request body read by /api/v1/align and /api/v1/compare.
+const maxAPIBodyBytes = 4 << 20
+
+// defaultBlockWidth is the number of columns per block when a request asks for
+// wrapped output without choosing a width.
+const defaultBlockWidth = 60
+
 func main() {
 	// Set up server config
 	config := ServerConfig{
 		Port: 8080,
 	}
 
+	flag.IntVar(&config.MaxAlignmentLength, "max-alignment-length", maxAlignmentLength,
+		"maximum number of alignment columns returned (0 = unlimited)")
+	flag.IntVar(&config.MaxStreamSequenceLength, "max-stream-sequence-length", maxStreamSequenceLength,
+		"maximum length of each sequence sent to the streaming endpoints (0 = unlimited)")
+	flag.IntVar(&config.MaxAPISequenceLength, "max-api-sequence-length", maxAPISequenceLength,
+		"maximum length of each sequence sent to /api/v1/align (0 = unlimited)")
+	flag.Parse()
+
+	if config.MaxAlignmentLength < 0 {
+		log.Fatalf("Invalid max-alignment-length: %d (must be 0 or positive)", config.MaxAlignmentLength)
+	}
+	if config.MaxStreamSequenceLength < 0 {
+		log.Fatalf("Invalid max-stream-sequence-length: %d (must be 0 or positive)", config.MaxStreamSequenceLength)
+	}
+	if config.MaxAPISequenceLength < 0 {
+		log.Fatalf("Invalid max-api-sequence-length: %d (must be 0 or positive)", config.MaxAPISequenceLength)
+	}
+	maxAlignmentLength = config.MaxAlignmentLength
+	maxStreamSequenceLength = config.MaxStreamSequenceLength
+	maxAPISequenceLength = config.MaxAPISequenceLength
+
 	// Set up the HTTP server
 	mux := http.NewServeMux()
 
@@ -82,7 +215,11 @@ func main() {
 	// Set up routes
 	mux.HandleFunc("/", handleIndex)
 	mux.HandleFunc("/align", handleAlign)
+	mux.HandleFunc("/align/stream", handleAlignStream)
 	mux.HandleFunc("/system-info", handleSystemInfo)
+	mux.HandleFunc("/api/v1/compare", handleCompare)
+	mux.HandleFunc("/api/v1/stream", handleStream)
+	mux.HandleFunc("/api/v1/align", withCORS(handleAPIAlign))
 
 	// Start the server
 	addr := fmt.Sprintf(":%d", config.Port)
@@ -134,6 +271,12 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	serveAlignment(w, r, req)
+}
+
+// serveAlignment runs a parsed alignment request and writes the JSON response, or
+// an error with the matching status code. It is shared by /align and /api/v1/align.
+func serveAlignment(w http.ResponseWriter, r *http.Request, req AlignmentRequest) {
 	// Prepare sequences
 	query := req.Query
 	reference := req.Reference
@@ -149,12 +292,39 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 		reference = data.GenerateDNASequence(length)
 	}
 
+	// Display the first explicit reference when no separate reference is given
+	if reference == "" && len(req.References) > 0 {
+		reference = req.References[0]
+	}
+
+	// Reject non-ASCII input before anything indexes the sequences by byte
+	sequences := []struct{ name, value string }{{"query", query}, {"reference", reference}}
+	for i, ref := range req.References {
+		sequences = append(sequences, struct{ name, value string }{fmt.Sprintf("references[%d]", i), ref})
+	}
+	for _, seq := range sequences {
+		if err := align.ValidateASCII(seq.name, seq.value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Validate sequences
-	if !isValidDNA(query) || !isValidDNA(reference) {
-		http.Error(w, "Invalid DNA sequence. Use only A, C, G, T characters.", http.StatusBadRequest)
-		return
+	for _, seq := range sequences {
+		if err := align.ValidateDNA(seq.name, seq.value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	}
 
+	// ValidateDNA accepts lowercase bases, but the aligners compare bases case-sensitively
+	query, reference = strings.ToUpper(query), strings.ToUpper(reference)
+	references := make([]string, len(req.References))
+	for i, ref := range req.References {
+		references[i] = strings.ToUpper(ref)
+	}
+	req.References = references
+
 	// Set default worker count if needed
 	if req.Workers <= 0 {
 		req.Workers = runtime.GOMAXPROCS(0)
@@ -178,32 +348,38 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 	// Start timing
 	startTime := time.Now()
 
-	// Perform the alignment
-	if req.UseBatch {
-		// Create batch of references
-		batchSize := req.BatchSize
-		if batchSize <= 0 {
-			batchSize = 10 // Default batch size
-		}
-
-		references := make([]string, batchSize)
-		for i := range references {
-			if i == 0 {
-				references[i] = reference // Use the original reference as first
-			} else {
-				// Create slightly modified references
-				references[i] = data.CreateMultipleMutations(reference, 3)
-			}
+	// Perform the alignment; displayed is the result shown as the main alignment
+	var displayed align.AlignmentResult
+	if req.UseBatch || len(req.References) > 0 {
+		references, err := buildReferences(req, reference)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
 		// Process batch
 		var results []align.AlignmentResult
 		if req.UseParallel {
+			// Reject the batch before aligning if any alignment could be longer than allowed
+			for i, ref := range references {
+				if err := checkParallelLength(query, ref); err != nil {
+					http.Error(w, fmt.Sprintf("Alignment %d: %v", i, err), http.StatusRequestEntityTooLarge)
+					return
+				}
+			}
 			results = align.ConcurrentSmithWatermanBatch(query, references, req.Workers)
 		} else {
 			results = make([]align.AlignmentResult, len(references))
 			for i, ref := range references {
-				results[i] = align.SmithWaterman(query, ref)
+				results[i], err = alignLimited(r.Context(), query, ref)
+				if errors.Is(err, align.ErrAlignmentTooLong) {
+					http.Error(w, fmt.Sprintf("Alignment %d: %v", i, err), http.StatusRequestEntityTooLarge)
+					return
+				}
+				if err != nil {
+					log.Printf("Alignment cancelled: %v", err)
+					return
+				}
 			}
 		}
 
@@ -213,33 +389,77 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 		for i, result := range results {
 			totalScore += result.MaxScore
 			resp.BatchResults[i] = BatchResult{
-				Index:        i,
-				Score:        result.MaxScore,
-				AlignedQuery: result.AlignedQuery,
-				AlignedRef:   result.AlignedRef,
+				Index:         i,
+				Score:         result.MaxScore,
+				AlignedQuery:  result.AlignedQuery,
+				AlignedRef:    result.AlignedRef,
+				NoSignificant: !result.HasMatchRun(req.MinMatchRun),
 			}
 		}
 
 		// Use the first result for the main display
-		resp.AlignedQuery = results[0].AlignedQuery
-		resp.AlignedRef = results[0].AlignedRef
-		resp.Score = results[0].MaxScore
+		displayed = results[0]
 	} else {
 		// Single alignment
-		var result interface{}
 		if req.UseParallel {
-			result = align.ParallelSmithWaterman(query, reference, req.Workers)
-			parallelResult := result.(align.ParallelAlignmentResult)
-			resp.AlignedQuery = parallelResult.AlignedQuery
-			resp.AlignedRef = parallelResult.AlignedRef
-			resp.Score = parallelResult.MaxScore
+			if err := checkParallelLength(query, reference); err != nil {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			displayed = align.ParallelSmithWaterman(query, reference, req.Workers)
 		} else {
-			result = align.SmithWaterman(query, reference)
-			seqResult := result.(align.AlignmentResult)
-			resp.AlignedQuery = seqResult.AlignedQuery
-			resp.AlignedRef = seqResult.AlignedRef
-			resp.Score = seqResult.MaxScore
+			// Stop filling the matrix if the client goes away
+			sequential, err := alignLimited(r.Context(), query, reference)
+			if errors.Is(err, align.ErrAlignmentTooLong) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				log.Printf("Alignment cancelled: %v", err)
+				return
+			}
+			displayed = sequential
+		}
+	}
+
+	resp.AlignedQuery = displayed.AlignedQuery
+	resp.AlignedRef = displayed.AlignedRef
+	resp.Score = displayed.MaxScore
+	if req.NormalizeIndels {
+		resp.Mutations = align.DetectMutationsNormalized(displayed.AlignedQuery, displayed.AlignedRef)
+	} else {
+		resp.Mutations = align.DetectMutations(displayed.AlignedQuery, displayed.AlignedRef)
+	}
+	if !displayed.NoAlignment {
+		resp.CIGAR = displayed.CIGAR()
+	}
+
+	// Record how the alignment was produced
+	if req.IncludeProvenance {
+		algorithm := align.AlgorithmSmithWaterman
+		if req.UseParallel && !req.UseBatch && len(req.References) == 0 {
+			algorithm = align.AlgorithmParallelSmithWaterman
+		}
+		resp.Provenance = align.NewProvenance(algorithm, align.DefaultScoring)
+	}
+
+	// Pre-wrap the alignment so the frontend can render it block by block
+	if req.WrapBlocks {
+		width := req.BlockWidth
+		if width <= 0 {
+			width = defaultBlockWidth
 		}
+		resp.Blocks = align.WrapAlignment(displayed, width)
+	}
+
+	// Explain an empty alignment rather than returning blank strings
+	if displayed.NoAlignment {
+		resp.NoAlignment = true
+		resp.Message = "No alignment: the sequences share no positive-scoring region"
+	} else if !displayed.HasMatchRun(req.MinMatchRun) {
+		// Report alignments built only from scattered matches as no significant hit
+		resp.NoSignificant = true
+		resp.Message = fmt.Sprintf("No significant alignment: no run of %d consecutive matches", req.MinMatchRun)
 	}
 
 	// Stop timing
@@ -252,7 +472,7 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 	resp.MemoryUsageMB = m.Alloc / (1024 * 1024)
 
 	// Add performance data
-	bytesPerBase := float64(m.TotalAlloc) / float64(len(query)+len(reference))
+	bytesPerBase := data.BytesPerBase(m.TotalAlloc, len(query)+len(reference))
 	resp.PerformanceData = PerformanceData{
 		CpuCores:       runtime.NumCPU(),
 		Goroutines:     runtime.NumGoroutine(),
@@ -263,16 +483,348 @@ func handleAlign(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Return the response
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(resp)
+	writeJSON(w, r, resp)
+}
+
+// handleAPIAlign is the programmatic counterpart of /align. It accepts either a JSON
+// AlignmentRequest or a multi-FASTA body, whose first record is the query, second the
+// reference, and any further records extra references for a batch alignment. Bodies
+// over maxAPIBodyBytes and sequences over maxAPISequenceLength are rejected with 413.
+func handleAPIAlign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error encoding response: %v", err), http.StatusInternalServerError)
+		http.Error(w, "Content-Type must be application/json or text/x-fasta", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAPIBodyBytes)
+
+	var req AlignmentRequest
+	switch mediaType {
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Error parsing request: %v", err), bodyErrorStatus(err))
+			return
+		}
+	case "text/x-fasta", "application/x-fasta":
+		records, err := data.ReadFASTA(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error parsing FASTA: %v", err), bodyErrorStatus(err))
+			return
+		}
+		if len(records) < 2 {
+			http.Error(w, fmt.Sprintf("FASTA input needs a query and a reference record, got %d", len(records)),
+				http.StatusBadRequest)
+			return
+		}
+		req.Query, req.Reference = records[0].Sequence, records[1].Sequence
+		if len(records) > 2 {
+			for _, record := range records[1:] {
+				req.References = append(req.References, record.Sequence)
+			}
+		}
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported Content-Type %q (use application/json or text/x-fasta)", mediaType),
+			http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// The score matrix grows with the product of the lengths, so check them before aligning
+	if err := checkAPISequenceLengths(req); err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	serveAlignment(w, r, req)
+}
+
+// checkAPISequenceLengths returns an error when a /api/v1/align request sends or asks
+// for a sequence longer than maxAPISequenceLength.
+func checkAPISequenceLengths(req AlignmentRequest) error {
+	if maxAPISequenceLength <= 0 {
+		return nil
+	}
+	if req.GenerateRandom && req.RandomLength > maxAPISequenceLength {
+		return fmt.Errorf("randomLength %d is over the limit of %d bases", req.RandomLength, maxAPISequenceLength)
+	}
+
+	sequences := []struct{ name, value string }{{"query", req.Query}, {"reference", req.Reference}}
+	for i, ref := range req.References {
+		sequences = append(sequences, struct{ name, value string }{fmt.Sprintf("references[%d]", i), ref})
+	}
+	for _, seq := range sequences {
+		if len(seq.value) > maxAPISequenceLength {
+			return fmt.Errorf("%s sequence is %d bases long (limit %d)", seq.name, len(seq.value), maxAPISequenceLength)
+		}
+	}
+	return nil
+}
+
+// bodyErrorStatus returns the status for a request body that could not be parsed:
+// 413 when the body passed its http.MaxBytesReader limit, 400 otherwise.
+func bodyErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// withCORS allows browsers on other origins to call h, answering preflight
+// OPTIONS requests itself.
+func withCORS(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// handleAlignStream aligns a query against a batch of references and reports each
+// completed reference as a Server-Sent Event, so the browser can show live progress.
+// The query string carries the query, one reference parameter per reference, and
+// optionally batchSize to align against synthetic variants of a single reference.
+// At most maxStreamBatchSize references are aligned; larger batch sizes are clamped.
+// A "done" event closes the stream once every reference is aligned.
+func handleAlignStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query := params.Get("query")
+	references := params["reference"]
+	if len(references) == 0 {
+		http.Error(w, "At least one reference is required", http.StatusBadRequest)
+		return
+	}
+	if len(references) > maxStreamBatchSize {
+		http.Error(w, fmt.Sprintf("Too many references: %d (limit %d)", len(references), maxStreamBatchSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Check the lengths before anything allocates per base
+	if err := checkStreamSequence("query", query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	upper := make([]string, len(references))
+	for i, ref := range references {
+		if err := checkStreamSequence(fmt.Sprintf("reference[%d]", i), ref); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		upper[i] = strings.ToUpper(ref)
+	}
+
+	// Lowercase bases pass validation, but the aligner compares bases case-sensitively
+	query, references = strings.ToUpper(query), upper
+
+	// A single reference with a batch size expands into synthetic variants, as in /align
+	if param := params.Get("batchSize"); param != "" && len(references) == 1 {
+		batchSize, err := strconv.Atoi(param)
+		if err != nil || batchSize <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid batchSize %q (must be a positive integer)", param), http.StatusBadRequest)
+			return
+		}
+
+		references, err = buildReferences(AlignmentRequest{
+			BatchSize:          min(batchSize, maxStreamBatchSize),
+			GenerateReferences: true,
+		}, references[0])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	rc := http.NewResponseController(w)
+
+	// Feed the references until the client goes away
+	refs := make(chan string)
+	go func() {
+		defer close(refs)
+		for _, ref := range references {
+			select {
+			case refs <- ref:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	// The stream stops aligning and closes if the client goes away
+	done := 0
+	for result := range align.SmithWatermanBatchStreamContext(r.Context(), query, refs, runtime.GOMAXPROCS(0)) {
+		done++
+		writeEvent(w, "result", BatchProgressEvent{Index: result.Index, Score: result.MaxScore, Done: done, Total: len(references)})
+		_ = rc.Flush()
+	}
+
+	if r.Context().Err() == nil {
+		writeEvent(w, "done", struct {
+			Total int `json:"total"`
+		}{len(references)})
+		_ = rc.Flush()
+	}
+}
+
+// writeEvent writes one Server-Sent Event with v encoded as JSON data
+func writeEvent(w io.Writer, event string, v interface{}) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// handleCompare reports the concordance of two alignments of the same sequences
+func handleCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse the request
+	var req CompareRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBodyBytes)).Decode(&req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing request: %v", err), bodyErrorStatus(err))
+		return
+	}
+
+	// Each alignment must have equal-length rows
+	for name, pair := range map[string]AlignedPair{"a": req.A, "b": req.B} {
+		if len(pair.AlignedQuery) != len(pair.AlignedRef) {
+			http.Error(w, fmt.Sprintf("Alignment %s has rows of different lengths (%d and %d)",
+				name, len(pair.AlignedQuery), len(pair.AlignedRef)), http.StatusBadRequest)
+			return
+		}
+	}
+
+	resp := CompareResponse{
+		Concordant: align.AlignmentsEquivalent(req.A.AlignedQuery, req.A.AlignedRef,
+			req.B.AlignedQuery, req.B.AlignedRef),
+		Identical:        req.A == req.B,
+		ScoreA:           align.AlignmentScore(req.A.AlignedQuery, req.A.AlignedRef),
+		ScoreB:           align.AlignmentScore(req.B.AlignedQuery, req.B.AlignedRef),
+		DifferingColumns: differingColumns(req.A, req.B),
+	}
+
+	// Return the response
+	writeJSON(w, r, resp)
+}
+
+// handleStream aligns a stream of newline-delimited JSON requests over a single
+// connection, writing one NDJSON response per request as soon as it completes.
+// A request that fails validation gets a response with Error set and the stream
+// continues; a malformed or over-long line ends the stream. The body may be as long
+// as the client likes, but each line is read into a buffer capped at
+// maxStreamLineBytes, so one request cannot exhaust the server's memory.
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+
+	// Keep reading the request body after the first response is written (HTTP/1.x)
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req StreamRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			_ = encoder.Encode(StreamResponse{Error: fmt.Sprintf("Error parsing request: %v", err)})
+			return
+		}
+
+		if err := encoder.Encode(alignStreamRequest(r.Context(), req)); err != nil {
+			return
+		}
+		_ = rc.Flush()
+	}
+
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		_ = encoder.Encode(StreamResponse{Error: fmt.Sprintf("Request line longer than %d bytes", maxStreamLineBytes)})
+	} else if err != nil {
+		log.Printf("Error reading stream: %v", err)
+	}
+}
+
+// alignStreamRequest runs the alignment for one streamed request
+func alignStreamRequest(ctx context.Context, req StreamRequest) StreamResponse {
+	for _, seq := range []struct{ name, value string }{{"query", req.Query}, {"reference", req.Reference}} {
+		if err := checkStreamSequence(seq.name, seq.value); err != nil {
+			return StreamResponse{ID: req.ID, Error: err.Error()}
+		}
+	}
+
+	result, err := alignLimited(ctx, strings.ToUpper(req.Query), strings.ToUpper(req.Reference))
+	if err != nil {
+		return StreamResponse{ID: req.ID, Error: err.Error()}
+	}
+
+	return StreamResponse{
+		ID:           req.ID,
+		AlignedQuery: result.AlignedQuery,
+		AlignedRef:   result.AlignedRef,
+		Score:        result.MaxScore,
+		QueryStart:   result.QueryStart,
+		QueryEnd:     result.QueryEnd,
+		RefStart:     result.RefStart,
+		RefEnd:       result.RefEnd,
+		NoAlignment:  result.NoAlignment,
+	}
+}
+
+// differingColumns lists the alignment columns where two alignments disagree.
+// Columns past the end of the shorter alignment count as differences.
+func differingColumns(a, b AlignedPair) []int {
+	length := len(a.AlignedQuery)
+	if len(b.AlignedQuery) > length {
+		length = len(b.AlignedQuery)
+	}
+
+	columns := []int{}
+	for i := 0; i < length; i++ {
+		if i >= len(a.AlignedQuery) || i >= len(b.AlignedQuery) ||
+			a.AlignedQuery[i] != b.AlignedQuery[i] || a.AlignedRef[i] != b.AlignedRef[i] {
+			columns = append(columns, i)
+		}
+	}
+
+	return columns
 }
 
 // handleSystemInfo returns information about the system
-func handleSystemInfo(w http.ResponseWriter, _ *http.Request) {
+func handleSystemInfo(w http.ResponseWriter, r *http.Request) {
 	// Gather system information
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
@@ -293,26 +845,79 @@ func handleSystemInfo(w http.ResponseWriter, _ *http.Request) {
 	}
 
 	// Return the response
-	w.Header().Set("Content-Type", "application/json")
-	err := json.NewEncoder(w).Encode(info)
-	if err != nil {
+	writeJSON(w, r, info)
+}
+
+// checkStreamSequence validates one sequence sent to a streaming endpoint, which
+// must be DNA no longer than maxStreamSequenceLength. The length is checked before
+// aligning, since the score matrix grows with the product of the two lengths.
+func checkStreamSequence(name, seq string) error {
+	if maxStreamSequenceLength > 0 && len(seq) > maxStreamSequenceLength {
+		return fmt.Errorf("%s sequence is %d bases long (limit %d)", name, len(seq), maxStreamSequenceLength)
+	}
+	return align.ValidateDNA(name, seq)
+}
+
+// alignLimited runs a sequential alignment that stops when ctx is cancelled and
+// aborts its traceback once the alignment passes maxAlignmentLength columns
+func alignLimited(ctx context.Context, query, reference string) (align.AlignmentResult, error) {
+	return align.SmithWatermanContextMaxLength(ctx, query, reference, maxAlignmentLength)
+}
+
+// checkParallelLength returns an error wrapping align.ErrAlignmentTooLong when the
+// longest possible alignment of query and reference exceeds the cap. The parallel
+// aligners cannot abort their traceback, so they are checked before aligning.
+func checkParallelLength(query, reference string) error {
+	if worst := align.MaxAlignmentLength(len(query), len(reference)); maxAlignmentLength > 0 && worst > maxAlignmentLength {
+		return fmt.Errorf("%w (parallel alignment of up to %d columns, limit %d columns)",
+			align.ErrAlignmentTooLong, worst, maxAlignmentLength)
+	}
+	return nil
+}
+
+// writeJSON encodes v as the JSON response body. Responses are compact unless
+// the request asks for indented output with ?pretty=true.
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	// Encode fully before writing so an error can still change the status
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(v); err != nil {
 		http.Error(w, fmt.Sprintf("Error encoding response: %v", err), http.StatusInternalServerError)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body.Bytes())
 }
 
-// isValidDNA checks if a string is a valid DNA sequence
-func isValidDNA(s string) bool {
-	if s == "" {
-		return false
+// buildReferences returns the references for a batch alignment: the explicit
+// references from the request, or synthetic variants of reference when the
+// request asks for generated references.
+func buildReferences(req AlignmentRequest, reference string) ([]string, error) {
+	if len(req.References) > 0 {
+		return req.References, nil
+	}
+	if !req.GenerateReferences {
+		return nil, errors.New("batch alignment requires references or generateReferences")
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10 // Default batch size
 	}
 
-	s = strings.ToUpper(s)
-	for _, c := range s {
-		if c != 'A' && c != 'C' && c != 'G' && c != 'T' {
-			return false
+	references := make([]string, batchSize)
+	for i := range references {
+		if i == 0 {
+			references[i] = reference // Use the original reference as first
+		} else {
+			// Create slightly modified references
+			references[i] = data.CreateMultipleMutations(reference, 3)
 		}
 	}
 
-	return true
+	return references, nil
 }
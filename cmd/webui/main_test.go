@@ -0,0 +1,623 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestHandleCompareConcordant checks that two equally-scoring gap placements are concordant
+func TestHandleCompareConcordant(t *testing.T) {
+	body := `{
+		"a": {"alignedQuery": "GATT-ACA", "alignedRef": "GATTTACA"},
+		"b": {"alignedQuery": "GA-TTACA", "alignedRef": "GATTTACA"}
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/compare", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handleCompare(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CompareResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+
+	if !resp.Concordant {
+		t.Error("Expected the two gap placements to be reported as concordant")
+	}
+	if resp.Identical {
+		t.Error("Expected the two gap placements not to be identical")
+	}
+	if resp.ScoreA != resp.ScoreB {
+		t.Errorf("Expected equal scores, got %d and %d", resp.ScoreA, resp.ScoreB)
+	}
+	if !reflect.DeepEqual(resp.DifferingColumns, []int{2, 4}) {
+		t.Errorf("Expected differing columns [2 4], got %v", resp.DifferingColumns)
+	}
+}
+
+// TestHandleCompareInvalid checks that malformed alignments are rejected
+func TestHandleCompareInvalid(t *testing.T) {
+	body := `{
+		"a": {"alignedQuery": "GATTACA", "alignedRef": "GATT"},
+		"b": {"alignedQuery": "GATTACA", "alignedRef": "GATTACA"}
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/compare", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handleCompare(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for rows of different lengths, got %d", rec.Code)
+	}
+
+	// Bodies over the limit are not read in full
+	body = `{"a": {"alignedQuery": "` + strings.Repeat("A", maxAPIBodyBytes) + `"}}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/compare", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handleCompare(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for an oversized body, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/compare", nil)
+	rec = httptest.NewRecorder()
+	handleCompare(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for GET, got %d", rec.Code)
+	}
+}
+
+// TestHandleAlignTooLong checks that alignments over the cap are rejected with 413
+func TestHandleAlignTooLong(t *testing.T) {
+	saved := maxAlignmentLength
+	maxAlignmentLength = 10
+	defer func() { maxAlignmentLength = saved }()
+
+	long := strings.Repeat("GATTACA", 10)
+	for _, useParallel := range []bool{false, true} {
+		for _, batch := range []bool{false, true} {
+			body := fmt.Sprintf(`{"query": "%s", "reference": "%s", "useParallel": %t}`, long, long, useParallel)
+			if batch {
+				body = fmt.Sprintf(`{"query": "%s", "references": ["GATTACA", "%s"], "useParallel": %t}`, long, long, useParallel)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			handleAlign(rec, req)
+
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("useParallel=%t batch=%t: expected status 413, got %d: %s", useParallel, batch, rec.Code, rec.Body.String())
+			}
+		}
+	}
+}
+
+// TestHandleAlignParallelLengthCheck checks that parallel requests are rejected before
+// aligning when the inputs could exceed the cap, while sequential ones are only
+// rejected if the actual alignment does
+func TestHandleAlignParallelLengthCheck(t *testing.T) {
+	saved := maxAlignmentLength
+	maxAlignmentLength = 10
+	defer func() { maxAlignmentLength = saved }()
+
+	// 16 bases in total, but the alignment is a single column
+	for useParallel, want := range map[bool]int{false: http.StatusOK, true: http.StatusRequestEntityTooLarge} {
+		body := fmt.Sprintf(`{"query": "AAAAAAAG", "reference": "CCCCCCCG", "useParallel": %t}`, useParallel)
+
+		req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handleAlign(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("useParallel=%t: expected status %d, got %d: %s", useParallel, want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestHandleAlignMinMatchRun checks that a short alignment is reported as no significant hit
+func TestHandleAlignMinMatchRun(t *testing.T) {
+	for _, tc := range []struct {
+		minRun      int
+		significant bool
+	}{{7, true}, {8, false}} {
+		body := fmt.Sprintf(`{"query": "GATTACA", "reference": "GATTACA", "minMatchRun": %d}`, tc.minRun)
+		req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handleAlign(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp AlignmentResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Could not decode response: %v", err)
+		}
+		if resp.NoSignificant == tc.significant {
+			t.Errorf("minMatchRun=%d: noSignificantAlignment=%t, expected %t", tc.minRun, resp.NoSignificant, !tc.significant)
+		}
+	}
+}
+
+// TestHandleAlignNormalizeIndels checks that normalizeIndels reports a deletion in a repeat at its leftmost position
+func TestHandleAlignNormalizeIndels(t *testing.T) {
+	body := `{"query": "GATTACA", "reference": "GATTTACA", "normalizeIndels": true}`
+	req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handleAlign(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp AlignmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if len(resp.Mutations) != 1 || resp.Mutations[0].Type != "deletion" || resp.Mutations[0].Position != 2 {
+		t.Errorf("Expected one deletion at position 2, got %+v", resp.Mutations)
+	}
+}
+
+// TestHandleAlignBlocks checks that the returned blocks reassemble into the full alignment
+func TestHandleAlignBlocks(t *testing.T) {
+	query := strings.Repeat("GATTACA", 20)
+	body := fmt.Sprintf(`{"query": "%s", "reference": "%s", "wrapBlocks": true, "blockWidth": 25}`, query, query)
+	req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handleAlign(rec, req)
+
+	var resp AlignmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+
+	// 140 columns in blocks of 25
+	if len(resp.Blocks) != 6 {
+		t.Fatalf("Expected 6 blocks, got %d", len(resp.Blocks))
+	}
+
+	var alignedQuery, alignedRef string
+	for _, block := range resp.Blocks {
+		alignedQuery += block.AlignedQuery
+		alignedRef += block.AlignedRef
+	}
+	if alignedQuery != resp.AlignedQuery || alignedRef != resp.AlignedRef {
+		t.Error("Blocks do not reassemble into the full alignment")
+	}
+	if last := resp.Blocks[len(resp.Blocks)-1]; last.QueryEnd != len(query) || last.RefEnd != len(query) {
+		t.Errorf("Last block ends at (%d,%d), expected (%d,%d)", last.QueryEnd, last.RefEnd, len(query), len(query))
+	}
+}
+
+// TestHandleAlignExplicitReferences checks that a batch aligns against the submitted references
+func TestHandleAlignExplicitReferences(t *testing.T) {
+	references := []string{"GATTACAGATTACA", "CCCCGGGG", "TTGATTACATT"}
+	payload, err := json.Marshal(AlignmentRequest{Query: "GATTACA", References: references})
+	if err != nil {
+		t.Fatalf("Could not encode request: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(string(payload)))
+	rec := httptest.NewRecorder()
+	handleAlign(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp AlignmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+
+	if len(resp.BatchResults) != len(references) {
+		t.Fatalf("Expected %d batch results, got %d", len(references), len(resp.BatchResults))
+	}
+	expectedScores := []int{14, 2, 14}
+	for i, result := range resp.BatchResults {
+		if result.Score != expectedScores[i] {
+			t.Errorf("Reference %d scored %d, expected %d", i, result.Score, expectedScores[i])
+		}
+	}
+	if resp.RefSequence != references[0] {
+		t.Errorf("Expected the first reference to be displayed, got %s", resp.RefSequence)
+	}
+
+	// A batch without references must ask for synthetic ones explicitly
+	req = httptest.NewRequest(http.MethodPost, "/align",
+		strings.NewReader(`{"query": "GATTACA", "reference": "GATTACA", "useBatch": true}`))
+	rec = httptest.NewRecorder()
+	handleAlign(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a batch without references, got %d", rec.Code)
+	}
+}
+
+// TestHandleAlignLowercase checks that lowercase bases align like uppercase ones on
+// every path, rather than scoring 0 against an uppercase reference
+func TestHandleAlignLowercase(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		score       int
+		batch       []int
+	}{
+		{"single", "application/json", `{"query": "gattacagattaca", "reference": "GATTACAGATTACA"}`, 28, nil},
+		{"parallel", "application/json", `{"query": "gattacagattaca", "reference": "GATTACAGATTACA", "useParallel": true}`, 28, nil},
+		{"batch", "application/json", `{"query": "gattaca", "references": ["GATTACA", "gattaca"]}`, 14, []int{14, 14}},
+		{"parallel batch", "application/json", `{"query": "GATTACA", "references": ["gattaca", "GATTACA"], "useParallel": true}`, 14, []int{14, 14}},
+		{"FASTA", "text/x-fasta", ">q\ngattaca\n>r\nGATTACA\n", 14, nil},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/align", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		rec := httptest.NewRecorder()
+		handleAPIAlign(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", tc.name, rec.Code, rec.Body.String())
+		}
+		var resp AlignmentResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: could not decode response: %v", tc.name, err)
+		}
+		if resp.Score != tc.score || resp.NoAlignment {
+			t.Errorf("%s: expected score %d, got %d (noAlignment %t)", tc.name, tc.score, resp.Score, resp.NoAlignment)
+		}
+		for i, want := range tc.batch {
+			if resp.BatchResults[i].Score != want {
+				t.Errorf("%s: reference %d scored %d, expected %d", tc.name, i, resp.BatchResults[i].Score, want)
+			}
+		}
+	}
+}
+
+// TestPrettyJSON checks that ?pretty=true indents responses and the default stays compact
+func TestPrettyJSON(t *testing.T) {
+	for _, tc := range []struct {
+		url    string
+		pretty bool
+	}{
+		{"/system-info", false},
+		{"/system-info?pretty=true", true},
+		{"/system-info?pretty=false", false},
+	} {
+		rec := httptest.NewRecorder()
+		handleSystemInfo(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+		body := rec.Body.String()
+		if indented := strings.Contains(body, "\n  \""); indented != tc.pretty {
+			t.Errorf("%s: indented=%t, expected %t:\n%s", tc.url, indented, tc.pretty, body)
+		}
+		if !json.Valid(rec.Body.Bytes()) {
+			t.Errorf("%s: response is not valid JSON", tc.url)
+		}
+	}
+
+	// The same option applies to the alignment endpoint
+	req := httptest.NewRequest(http.MethodPost, "/align?pretty=true",
+		strings.NewReader(`{"query": "GATTACA", "reference": "GATTACA"}`))
+	rec := httptest.NewRecorder()
+	handleAlign(rec, req)
+	if !strings.Contains(rec.Body.String(), "\n  \"alignedQuery\": \"GATTACA\"") {
+		t.Errorf("Expected an indented alignment response, got:\n%s", rec.Body.String())
+	}
+}
+
+// TestHandleAlignNoAlignment checks that dissimilar sequences are explained rather than left blank
+func TestHandleAlignNoAlignment(t *testing.T) {
+	for _, useParallel := range []bool{false, true} {
+		body := fmt.Sprintf(`{"query": "%s", "reference": "%s", "useParallel": %t}`,
+			strings.Repeat("A", 60), strings.Repeat("C", 60), useParallel)
+		req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handleAlign(rec, req)
+
+		var resp AlignmentResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Could not decode response: %v", err)
+		}
+		if !resp.NoAlignment || resp.Message == "" {
+			t.Errorf("useParallel=%t: expected noAlignment with a message, got %+v", useParallel, resp)
+		}
+	}
+}
+
+// TestHandleAlignCancelled checks that a request whose client has gone away gets no response
+func TestHandleAlignCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	body := `{"query": "GATTACA", "reference": "GATTACA"}`
+	req := httptest.NewRequest(http.MethodPost, "/align", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handleAlign(rec, req)
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected no response body for a cancelled request, got %q", rec.Body.String())
+	}
+}
+
+// TestHandleAPIAlign checks JSON and FASTA input, CORS, and the status codes of /api/v1/align
+func TestHandleAPIAlign(t *testing.T) {
+	handler := withCORS(handleAPIAlign)
+
+	post := func(contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/align", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := post("application/json; charset=utf-8", `{"query": "GATTACA", "reference": "GATCACA"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("JSON request: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("Expected a CORS header on the response")
+	}
+	var resp AlignmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if resp.CIGAR != "7M" || len(resp.Mutations) != 1 || resp.Mutations[0].Type != "snp" {
+		t.Errorf("Expected CIGAR 7M and one SNP, got %q and %+v", resp.CIGAR, resp.Mutations)
+	}
+
+	// Multi-FASTA: query, reference, and one more reference for a batch
+	rec = post("text/x-fasta", ">q\nGATTACA\n>r1\nGATTACA\n>r2\nGATCACA\n")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("FASTA request: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	resp = AlignmentResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if resp.Score != 14 || len(resp.BatchResults) != 2 {
+		t.Errorf("Expected score 14 and 2 batch results, got %d and %d", resp.Score, len(resp.BatchResults))
+	}
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"missing Content-Type", "", `{}`, http.StatusUnsupportedMediaType},
+		{"unsupported Content-Type", "text/plain", "GATTACA", http.StatusUnsupportedMediaType},
+		{"one FASTA record", "text/x-fasta", ">q\nGATTACA\n", http.StatusBadRequest},
+		{"invalid DNA", "application/json", `{"query": "GATTXCA", "reference": "GATTACA"}`, http.StatusBadRequest},
+	} {
+		if rec := post(tc.contentType, tc.body); rec.Code != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, rec.Code, tc.status)
+		}
+	}
+
+	// Preflight requests are answered without running the handler
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/align", nil)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("Preflight: status %d, headers %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/align", nil)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+// TestHandleAPIAlignLimits checks that oversized bodies and sequences get 413 before aligning
+func TestHandleAPIAlignLimits(t *testing.T) {
+	saved := maxAPISequenceLength
+	maxAPISequenceLength = 20
+	defer func() { maxAPISequenceLength = saved }()
+
+	long := strings.Repeat("A", 21)
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"within the limit", "application/json", `{"query": "GATTACA", "reference": "GATTACA"}`, http.StatusOK},
+		{"long query", "application/json", fmt.Sprintf(`{"query": "%s", "reference": "GATTACA"}`, long), http.StatusRequestEntityTooLarge},
+		{"long batch reference", "application/json", fmt.Sprintf(`{"query": "GATTACA", "references": ["GATTACA", "%s"]}`, long), http.StatusRequestEntityTooLarge},
+		{"long random length", "application/json", `{"generateRandom": true, "randomLength": 21}`, http.StatusRequestEntityTooLarge},
+		{"long FASTA reference", "text/x-fasta", ">q\nGATTACA\n>r\n" + long + "\n", http.StatusRequestEntityTooLarge},
+		{"JSON body over the limit", "application/json", `{"query": "` + strings.Repeat("A", maxAPIBodyBytes) + `"}`, http.StatusRequestEntityTooLarge},
+		{"FASTA body over the limit", "text/x-fasta", ">q\n" + strings.Repeat("A", maxAPIBodyBytes), http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/align", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		rec := httptest.NewRecorder()
+		handleAPIAlign(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: status %d, want %d: %s", tc.name, rec.Code, tc.status, rec.Body.String())
+		}
+	}
+}
+
+// TestHandleAlignStream checks that every reference gets a progress event before the final done event
+func TestHandleAlignStream(t *testing.T) {
+	// Lowercase bases score as their uppercase forms
+	target := "/align/stream?query=gattaca&reference=GATTACA&reference=gatcaca&reference=TTTTTTT"
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	handleAlignStream(rec, req)
+
+	if rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got Content-Type %q (body %s)", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	events := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+	if len(events) != 4 {
+		t.Fatalf("Expected 3 result events and a done event, got %d:\n%s", len(events), rec.Body.String())
+	}
+
+	scores := map[int]int{}
+	for i, event := range events[:3] {
+		name, payload, _ := strings.Cut(event, "\n")
+		if name != "event: result" {
+			t.Fatalf("Event %d is %q, expected a result", i, name)
+		}
+		var progress BatchProgressEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(payload, "data: ")), &progress); err != nil {
+			t.Fatalf("Could not decode event %d: %v", i, err)
+		}
+		if progress.Done != i+1 || progress.Total != 3 {
+			t.Errorf("Event %d reports %d of %d done", i, progress.Done, progress.Total)
+		}
+		scores[progress.Index] = progress.Score
+	}
+	if !reflect.DeepEqual(scores, map[int]int{0: 14, 1: 11, 2: 4}) {
+		t.Errorf("Unexpected scores by index: %v", scores)
+	}
+	if !strings.HasPrefix(events[3], "event: done\ndata: {\"total\":3}") {
+		t.Errorf("Expected a final done event, got %q", events[3])
+	}
+
+	// Invalid input is rejected before the stream starts
+	req = httptest.NewRequest(http.MethodGet, "/align/stream?query=GATTACA", nil)
+	rec = httptest.NewRecorder()
+	handleAlignStream(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without references, got %d", rec.Code)
+	}
+}
+
+// TestHandleAlignStreamLimits checks that oversized batches are clamped or rejected
+// before any alignment starts
+func TestHandleAlignStreamLimits(t *testing.T) {
+	// A huge batchSize is clamped rather than allocated
+	req := httptest.NewRequest(http.MethodGet, "/align/stream?query=GATTACA&reference=GATTACA&batchSize=100000000", nil)
+	rec := httptest.NewRecorder()
+	handleAlignStream(rec, req)
+	if want := fmt.Sprintf("event: done\ndata: {\"total\":%d}", maxStreamBatchSize); !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("Expected the batch to be clamped to %d references", maxStreamBatchSize)
+	}
+
+	targets := map[string]int{
+		"/align/stream?query=GATTACA&reference=GATTACA&batchSize=ten":                                  http.StatusBadRequest,
+		"/align/stream?query=GATTACA" + strings.Repeat("&reference=ACGT", maxStreamBatchSize+1):        http.StatusRequestEntityTooLarge,
+		"/align/stream?query=" + strings.Repeat("A", maxStreamSequenceLength+1) + "&reference=GATTACA": http.StatusBadRequest,
+	}
+	for target, want := range targets {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		handleAlignStream(rec, req)
+		if rec.Code != want {
+			t.Errorf("Expected status %d for %.80s..., got %d: %s", want, target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestHandleStream checks that each NDJSON request line gets its own response line
+func TestHandleStream(t *testing.T) {
+	body := `{"id": "1", "query": "GATTACA", "reference": "GATTACA"}
+{"id": "2", "query": "GATTACA", "reference": "GAT-ACA"}
+{"id": "3", "query": "ACGTACGT", "reference": "TTACGTAA"}
+`
+
+	// Use a real server so the response is streamed over a single connection
+	server := httptest.NewServer(http.HandlerFunc(handleStream))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Error posting stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON content type, got %q", ct)
+	}
+
+	var responses []StreamResponse
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		var r StreamResponse
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("Error decoding response line: %v", err)
+		}
+		responses = append(responses, r)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d: %+v", len(responses), responses)
+	}
+	for i, r := range responses {
+		if want := fmt.Sprint(i + 1); r.ID != want {
+			t.Errorf("Response %d has ID %q, want %q", i, r.ID, want)
+		}
+	}
+
+	if responses[0].Score != 14 || responses[0].AlignedQuery != "GATTACA" || responses[0].Error != "" {
+		t.Errorf("Unexpected first response: %+v", responses[0])
+	}
+	if !strings.Contains(responses[1].Error, "reference sequence contains '-'") {
+		t.Errorf("Expected a validation error for the second request, got %+v", responses[1])
+	}
+	if responses[2].AlignedQuery != "ACGTA" || responses[2].QueryStart != 0 || responses[2].RefStart != 2 {
+		t.Errorf("Unexpected third response: %+v", responses[2])
+	}
+}
+
+// TestHandleStreamLimits checks that over-long sequences are rejected per request and
+// an over-long line ends the stream
+func TestHandleStreamLimits(t *testing.T) {
+	saved := maxStreamSequenceLength
+	maxStreamSequenceLength = 20
+	defer func() { maxStreamSequenceLength = saved }()
+
+	body := fmt.Sprintf(`{"id": "1", "query": "%s", "reference": "GATTACA"}
+{"id": "2", "query": "GATTACA", "reference": "GATTACA"}
+{"id": "3", "query": "%s", "reference": "GATTACA"}
+`, strings.Repeat("A", 21), strings.Repeat("A", maxStreamLineBytes))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stream", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handleStream(rec, req)
+
+	var responses []StreamResponse
+	decoder := json.NewDecoder(rec.Body)
+	for decoder.More() {
+		var r StreamResponse
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("Error decoding response line: %v", err)
+		}
+		responses = append(responses, r)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d: %+v", len(responses), responses)
+	}
+	if !strings.Contains(responses[0].Error, "query sequence is 21 bases long (limit 20)") {
+		t.Errorf("Expected a length error for the first request, got %+v", responses[0])
+	}
+	if responses[1].Error != "" || responses[1].Score != 14 {
+		t.Errorf("Expected the second request to align, got %+v", responses[1])
+	}
+	if !strings.Contains(responses[2].Error, "longer than") || responses[2].ID != "" {
+		t.Errorf("Expected the over-long line to end the stream, got %+v", responses[2])
+	}
+}
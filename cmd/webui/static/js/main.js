@@ -22,6 +22,7 @@ function performAlignment() {
         workers: workers,
         useBatch: useBatch,
         batchSize: batchSize,
+        generateReferences: useBatch,
         generateRandom: false,
         randomLength: 0
     };
@@ -99,8 +100,10 @@ function displayResults(data) {
     document.getElementById('alignedQuery').textContent = alignedQuery;
     document.getElementById('alignedRef').textContent = alignedRef;
 
-    // Generate and display the match line
-    document.getElementById('alignmentMatch').textContent = generateMatchLine(alignedQuery, alignedRef);
+    // Generate and display the match line, or explain why there is no alignment
+    document.getElementById('alignmentMatch').textContent = data.message ?
+        data.message :
+        generateMatchLine(alignedQuery, alignedRef);
 }
 
 // Generate the match line between two aligned sequences
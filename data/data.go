@@ -1,8 +1,13 @@
 package data
 
 import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,18 +27,122 @@ var bases = []rune{'A', 'T', 'C', 'G'}
 //   - length (int): The length of the DNA sequence to generate.
 //
 // Returns:
-//   - (string): A randomly generated DNA sequence of the specified length.
+//   - (string): A randomly generated DNA sequence of the specified length, or an
+//     empty string if length is negative.
 //
 // Example Usage:
 //
 //	seq := GenerateDNASequence(10)  // Returns something like "ATCGGCTTGA"
 func GenerateDNASequence(length int) string {
+	// A negative length cannot be allocated, so treat it like an empty sequence
+	if length < 0 {
+		return ""
+	}
+
+	return generateDNA(length, globalRand)
+}
+
+// GenerateDNASequenceSeeded generates a random DNA sequence of a given length from a
+// random source seeded with seed. The same length and seed always produce the same
+// sequence, so a problematic input can be reproduced by recording its seed.
+//
+// Parameters:
+//   - length (int): The length of the DNA sequence to generate.
+//   - seed (int64): The seed for the random source.
+//
+// Returns:
+//   - (string): A DNA sequence of the specified length, or an empty string if length
+//     is negative.
+//
+// Example Usage:
+//
+//	seq := GenerateDNASequenceSeeded(10, 42)  // Returns the same sequence on every run
+func GenerateDNASequenceSeeded(length int, seed int64) string {
+	if length < 0 {
+		return ""
+	}
+
+	return generateDNA(length, rand.New(rand.NewSource(seed)))
+}
+
+// GenerateDNASequenceWeighted generates a random DNA sequence whose bases are drawn
+// with the given probabilities instead of uniformly.
+//
+// Purpose:
+//   - Simulates AT-rich or GC-rich organisms, whose skewed composition changes how
+//     often unrelated sequences match by chance, for more realistic benchmarks.
+//
+// Parameters:
+//   - length (int): The length of the DNA sequence to generate.
+//   - weights (map[byte]float64): The relative weight of each of 'A', 'C', 'G', and
+//     'T'. Weights are normalized, so they need not sum to 1; missing bases have
+//     weight 0. An empty map, or one whose weights are all zero, gives uniform bases.
+//
+// Returns:
+//   - (string): A DNA sequence of the specified length, or an empty string if length
+//     is negative.
+//   - (error): An error if weights has a key other than 'A', 'C', 'G', or 'T', or a
+//     negative weight.
+//
+// Example Usage:
+//
+//	seq, err := GenerateDNASequenceWeighted(1000, map[byte]float64{'A': 0.35, 'T': 0.35, 'G': 0.15, 'C': 0.15})
+func GenerateDNASequenceWeighted(length int, weights map[byte]float64) (string, error) {
+	total := 0.0
+	for base, weight := range weights {
+		switch base {
+		case 'A', 'C', 'G', 'T':
+		default:
+			return "", fmt.Errorf("invalid base %q in weights (must be A, C, G, or T)", base)
+		}
+		if weight < 0 {
+			return "", fmt.Errorf("negative weight %g for base %c", weight, base)
+		}
+		total += weight
+	}
+
+	if length < 0 {
+		return "", nil
+	}
+	if total == 0 {
+		return generateDNA(length, globalRand), nil
+	}
+
+	// Cumulative probabilities in a fixed base order
+	order := []byte{'A', 'C', 'G', 'T'}
+	cumulative := make([]float64, len(order))
+	sum, last := 0.0, 0
+	for i, base := range order {
+		sum += weights[base] / total
+		cumulative[i] = sum
+		if weights[base] > 0 {
+			last = i
+		}
+	}
+
+	seq := make([]byte, length)
+	for i := range seq {
+		x := globalRand.Float64()
+		k := 0
+		// Rounding can leave the cumulative sum just under 1, so never pass the
+		// last base with a positive weight
+		for k < last && x >= cumulative[k] {
+			k++
+		}
+		seq[i] = order[k]
+	}
+
+	return string(seq), nil
+}
+
+// generateDNA draws length random bases from r.
+func generateDNA(length int, r *rand.Rand) string {
 	// Create a sequence slice of the specified length
 	seq := make([]rune, length)
 
 	// Populate the sequence with random DNA bases
 	for i := range seq {
-		seq[i] = bases[globalRand.Intn(len(bases))]
+		seq[i] = bases[r.Intn(len(bases))]
 	}
 
 	// Convert the slice to a string and return it
@@ -49,29 +158,74 @@ func GenerateDNASequence(length int) string {
 // Returns:
 //   - (string): A new DNA sequence with a single base changed at the specified position.
 func CreateSNP(original string, position int) string {
-	if position < 0 || position >= len(original) {
-		return original // Return original if position is invalid
-	}
-
-	// Create a different base than the original at the specified position
-	var newBase rune
-	originalBase := rune(original[position])
+	return CreateSNPWithAlphabet(original, position, bases)
+}
 
-	// Keep generating a random base until it's different from the original
-	for {
-		newBase = bases[globalRand.Intn(len(bases))]
-		if newBase != originalBase {
-			break
-		}
+// CreateSNPWithAlphabet creates a sequence with a single substitution at the
+// specified position, drawing the new symbol from the given alphabet.
+//
+// Purpose:
+//   - Simulating substitutions over alphabets other than ACGT, such as IUPAC codes,
+//     RNA, or a reduced alphabet for testing.
+//
+// Parameters:
+//   - original (string): The original sequence.
+//   - position (int): The position where the substitution should be introduced (0-based).
+//   - alphabet ([]rune): The symbols to choose from. The new symbol is picked uniformly
+//     among those that differ from the original one, so it is chosen in a single step.
+//
+// Returns:
+//   - (string): A new sequence with the symbol at position changed, or the original if
+//     the position is invalid, the alphabet has fewer than two symbols, or no symbol
+//     in it differs from the original one.
+//
+// Example Usage:
+//
+//	rna := CreateSNPWithAlphabet("GAUUACA", 2, []rune("ACGU"))
+func CreateSNPWithAlphabet(original string, position int, alphabet []rune) string {
+	if position < 0 || position >= len(original) || len(alphabet) < 2 {
+		return original // Return original if position or alphabet is invalid
 	}
 
 	// Convert original to rune slice for manipulation
 	seq := []rune(original)
+	newBase, ok := substituteBase(seq[position], alphabet, globalRand)
+	if !ok {
+		return original
+	}
 	seq[position] = newBase
 
 	return string(seq)
 }
 
+// substituteBase picks a symbol from alphabet uniformly among those different from
+// base, without retrying. It reports false if every symbol equals base.
+func substituteBase(base rune, alphabet []rune, r *rand.Rand) (rune, bool) {
+	candidates := 0
+	for _, symbol := range alphabet {
+		if symbol != base {
+			candidates++
+		}
+	}
+	if candidates == 0 {
+		return base, false
+	}
+
+	// Walk to the chosen candidate, skipping the original base
+	choice := r.Intn(candidates)
+	for _, symbol := range alphabet {
+		if symbol == base {
+			continue
+		}
+		if choice == 0 {
+			return symbol, true
+		}
+		choice--
+	}
+
+	return base, false
+}
+
 // CreateInsertion inserts a specified sequence at the given position in the original sequence.
 //
 // Parameters:
@@ -80,13 +234,38 @@ func CreateSNP(original string, position int) string {
 //   - inserted (string): The DNA sequence to insert.
 //
 // Returns:
-//   - (string): A new DNA sequence with the insertion.
+//   - (string): A new DNA sequence with the insertion, or the original if the
+//     position is invalid. Use CreateInsertionE to find out whether it was applied.
 func CreateInsertion(original string, position int, inserted string) string {
-	if position < 0 || position > len(original) {
+	mutated, err := CreateInsertionE(original, position, inserted)
+	if err != nil {
 		return original // Return original if position is invalid
 	}
+	return mutated
+}
 
-	return original[:position] + inserted + original[position:]
+// CreateInsertionE behaves like CreateInsertion but reports an insertion that would
+// leave the sequence unchanged instead of silently returning the original.
+//
+// Parameters:
+//   - original (string): The original DNA sequence.
+//   - position (int): The position where the insertion should occur (0-based). Inserting
+//     at len(original) appends to the sequence.
+//   - inserted (string): The DNA sequence to insert.
+//
+// Returns:
+//   - (string): A new DNA sequence with the insertion.
+//   - (error): An error naming the position and valid range if the position is out of
+//     bounds, or if inserted is empty.
+func CreateInsertionE(original string, position int, inserted string) (string, error) {
+	if position < 0 || position > len(original) {
+		return "", fmt.Errorf("insertion position %d out of range [0, %d]", position, len(original))
+	}
+	if inserted == "" {
+		return "", fmt.Errorf("empty insertion at position %d", position)
+	}
+
+	return original[:position] + inserted + original[position:], nil
 }
 
 // CreateDeletion creates a sequence with a deletion of specified length starting at the given position.
@@ -97,18 +276,91 @@ func CreateInsertion(original string, position int, inserted string) string {
 //   - length (int): The number of bases to delete.
 //
 // Returns:
-//   - (string): A new DNA sequence with the specified deletion.
+//   - (string): A new DNA sequence with the specified deletion, or the original if the
+//     position or length is invalid. Use CreateDeletionE to find out whether it was applied.
 func CreateDeletion(original string, position int, length int) string {
-	if position < 0 || position >= len(original) {
+	mutated, err := CreateDeletionE(original, position, length)
+	if err != nil {
 		return original // Return original if position is invalid
 	}
+	return mutated
+}
+
+// CreateDeletionE behaves like CreateDeletion but reports a deletion that would
+// leave the sequence unchanged instead of silently returning the original.
+//
+// Parameters:
+//   - original (string): The original DNA sequence.
+//   - position (int): The start position of the deletion (0-based).
+//   - length (int): The number of bases to delete. A deletion running past the end
+//     of the sequence stops there.
+//
+// Returns:
+//   - (string): A new DNA sequence with the specified deletion.
+//   - (error): An error naming the position and valid range if the position is out of
+//     bounds, or if length is not positive.
+func CreateDeletionE(original string, position int, length int) (string, error) {
+	if position < 0 || position >= len(original) {
+		return "", fmt.Errorf("deletion position %d out of range [0, %d)", position, len(original))
+	}
+	if length <= 0 {
+		return "", fmt.Errorf("deletion length %d at position %d must be positive", length, position)
+	}
 
 	// Ensure we don't try to delete past the end of the sequence
 	if position+length > len(original) {
 		length = len(original) - position
 	}
 
-	return original[:position] + original[position+length:]
+	return original[:position] + original[position+length:], nil
+}
+
+// CreateInversion creates a sequence with a region replaced by its reverse
+// complement, as happens when a segment of a chromosome is flipped.
+//
+// Parameters:
+//   - seq (string): The original DNA sequence.
+//   - start (int): The start position of the inverted region (0-based).
+//   - length (int): The number of bases to invert. A region running past the end of
+//     the sequence stops there.
+//
+// Returns:
+//   - (string): A new DNA sequence with the region inverted, or the original if the
+//     start is out of bounds or the length is not positive.
+func CreateInversion(seq string, start, length int) string {
+	if start < 0 || start >= len(seq) || length <= 0 {
+		return seq // Return original if the region is invalid
+	}
+
+	end := min(start+length, len(seq))
+	return seq[:start] + ReverseComplement(seq[start:end]) + seq[end:]
+}
+
+// CreateTandemDuplication creates a sequence with a region repeated immediately
+// after itself, as produced by replication slippage or unequal crossing over.
+//
+// Parameters:
+//   - seq (string): The original DNA sequence.
+//   - start (int): The start position of the duplicated region (0-based).
+//   - length (int): The number of bases to duplicate. A region running past the end
+//     of the sequence stops there.
+//   - copies (int): The number of extra copies inserted after the region; 1 leaves
+//     the region twice in a row.
+//
+// Returns:
+//   - (string): A new DNA sequence with the duplication, or the original if the start
+//     is out of bounds or the length or copies is not positive.
+//
+// Example Usage:
+//
+//	dup := CreateTandemDuplication("GATTACA", 1, 3, 2) // "GATTATTATTACA"
+func CreateTandemDuplication(seq string, start, length, copies int) string {
+	if start < 0 || start >= len(seq) || length <= 0 || copies <= 0 {
+		return seq // Return original if the region is invalid
+	}
+
+	end := min(start+length, len(seq))
+	return seq[:end] + strings.Repeat(seq[start:end], copies) + seq[end:]
 }
 
 // CreateMutatedSequence creates a sequence with random mutations at the specified rate.
@@ -120,18 +372,36 @@ func CreateDeletion(original string, position int, length int) string {
 // Returns:
 //   - (string): A new DNA sequence with random mutations.
 func CreateMutatedSequence(original string, mutationRate float64) string {
+	// Seed a local random source from the shared, concurrency-safe generator so
+	// that calls made in the same instant still get different seeds
+	return CreateMutatedSequenceSeeded(original, mutationRate, rand.Int63())
+}
+
+// CreateMutatedSequenceSeeded creates a sequence with random mutations at the specified
+// rate, using a random source seeded with seed. The same inputs and seed always
+// produce the same mutated sequence, which makes simulations reproducible.
+//
+// Parameters:
+//   - original (string): The original DNA sequence.
+//   - mutationRate (float64): The probability (0.0-1.0) of each base being mutated.
+//   - seed (int64): The seed for the random source.
+//
+// Returns:
+//   - (string): A new DNA sequence with random mutations.
+func CreateMutatedSequenceSeeded(original string, mutationRate float64, seed int64) string {
 	if mutationRate <= 0 || mutationRate > 1 {
 		return original // Return original if mutation rate is invalid
 	}
 
-	// Create a local random source with a unique seed
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := rand.New(rand.NewSource(seed))
 	seq := []rune(original)
 
 	for i := range seq {
 		// Determine if this position should be mutated
 		if r.Float64() < mutationRate {
-			// Select a different base
+			// Select a different base by redrawing, not with substituteBase: the draws
+			// must stay the same for a given seed to keep reproducing old simulations.
+			// bases has four symbols, so at most the original one is ever rejected.
 			originalBase := seq[i]
 			for {
 				newBase := bases[r.Intn(len(bases))]
@@ -178,20 +448,24 @@ func CreateMultipleMutations(original string, numMutations int) string {
 		mutatedPositions[position] = true
 
 		// Change the base
-		originalBase := seq[position]
-		for {
-			newBase := bases[globalRand.Intn(len(bases))]
-			if newBase != originalBase {
-				seq[position] = newBase
-				break
-			}
-		}
+		seq[position], _ = substituteBase(seq[position], bases, globalRand)
 	}
 
 	return string(seq)
 }
 
+// GapSymbol marks a gap in aligned sequences, such as the rows of a multiple alignment.
+const GapSymbol = '-'
+
+// majorityGapThreshold makes a consensus position a gap only when gaps are the strict majority.
+const majorityGapThreshold = 0.5
+
 // GenerateConsensusSequence creates a consensus sequence from multiple DNA sequences.
+// The consensus is as long as the shortest sequence; bases past that length in
+// longer sequences are ignored. When bases tie for the most votes, the one that
+// sorts first alphabetically wins, so the result never depends on input order.
+// Gaps in aligned input are handled as in GenerateGappedConsensus with a threshold
+// of 0.5: a position is a gap only if most sequences have a gap there.
 //
 // Parameters:
 //   - sequences ([]string): The DNA sequences to create a consensus from.
@@ -199,41 +473,570 @@ func CreateMultipleMutations(original string, numMutations int) string {
 // Returns:
 //   - (string): A consensus sequence where each position contains the most common base.
 func GenerateConsensusSequence(sequences []string) string {
+	return GenerateConsensusSequenceWeighted(sequences, nil)
+}
+
+// GenerateGappedConsensus creates a consensus from aligned sequences, such as the
+// rows of a multiple alignment, treating gaps separately from bases. A gap never
+// wins just by outnumbering each individual base: the consensus has a gap only
+// where the share of sequences with a gap exceeds gapThreshold, and otherwise the
+// most common actual base. Length and tie-breaking follow GenerateConsensusSequence.
+//
+// Parameters:
+//   - sequences ([]string): The aligned sequences, with gaps written as GapSymbol.
+//   - gapThreshold (float64): The fraction of gaps a position must exceed to be a gap
+//     in the consensus: 0.5 requires a strict majority, 0 makes any gap win, and 1
+//     or more never emits a gap.
+//
+// Returns:
+//   - (string): The consensus, with GapSymbol at gap positions and 'N' at positions
+//     where every sequence has a gap but gaps may not win.
+//
+// Example Usage:
+//
+//	consensus := GenerateGappedConsensus([]string{"GA-TACA", "GATTACA", "GA-TACA"}, 0.5) // "GA-TACA"
+func GenerateGappedConsensus(sequences []string, gapThreshold float64) string {
+	if len(sequences) == 0 {
+		return ""
+	}
+
+	consensus := make([]rune, shortestLength(sequences))
+	fillConsensus(consensus, nil, sequences, nil, gapThreshold, 0, len(consensus))
+
+	return string(consensus)
+}
+
+// GenerateConsensusSequenceWeighted creates a consensus in which each sequence's vote
+// counts with its weight, such as a read's mean quality or its coverage. Length and
+// tie-breaking follow GenerateConsensusSequence.
+//
+// Parameters:
+//   - sequences ([]string): The DNA sequences to create a consensus from.
+//   - weights ([]float64): The weight of each sequence's vote. A nil slice weights
+//     every sequence equally; otherwise sequences without a weight, or with a weight
+//     of zero or less, do not vote.
+//
+// Returns:
+//   - (string): A consensus sequence where each position contains the base with the
+//     largest total weight, or 'N' where no sequence votes.
+func GenerateConsensusSequenceWeighted(sequences []string, weights []float64) string {
 	if len(sequences) == 0 {
 		return ""
 	}
 
-	// Find the length of the shortest sequence
+	// Build the consensus sequence
+	consensus := make([]rune, shortestLength(sequences))
+	fillConsensus(consensus, nil, sequences, weights, majorityGapThreshold, 0, len(consensus))
+
+	return string(consensus)
+}
+
+// ConsensusWithConfidence creates the same consensus as GenerateConsensusSequence and
+// reports how strongly each position is supported.
+//
+// Parameters:
+//   - sequences ([]string): The DNA sequences to create a consensus from.
+//
+// Returns:
+//   - consensus (string): The majority consensus.
+//   - confidence ([]float64): For each consensus position, the fraction of sequences
+//     whose base agrees with the consensus base, between 0 and 1.
+//
+// Example Usage:
+//
+//	consensus, confidence := ConsensusWithConfidence(reads)
+//	masked := MaskLowConfidence(consensus, confidence, 0.6)
+func ConsensusWithConfidence(sequences []string) (consensus string, confidence []float64) {
+	if len(sequences) == 0 {
+		return "", []float64{}
+	}
+
+	bases := make([]rune, shortestLength(sequences))
+	confidence = make([]float64, len(bases))
+	fillConsensus(bases, confidence, sequences, nil, majorityGapThreshold, 0, len(bases))
+
+	return string(bases), confidence
+}
+
+// MaskLowConfidence replaces each consensus base whose confidence is below
+// minConfidence with 'N', so that poorly supported positions are not mistaken for
+// real calls downstream.
+//
+// Parameters:
+//   - consensus (string): The consensus sequence.
+//   - confidence ([]float64): The confidence of each position, as returned by
+//     ConsensusWithConfidence. Positions without a confidence are left unchanged.
+//   - minConfidence (float64): The lowest confidence kept, such as 0.6.
+//
+// Returns:
+//   - (string): The consensus with low-confidence positions masked.
+func MaskLowConfidence(consensus string, confidence []float64, minConfidence float64) string {
+	masked := []byte(consensus)
+	for i := range masked {
+		if i < len(confidence) && confidence[i] < minConfidence {
+			masked[i] = 'N'
+		}
+	}
+	return string(masked)
+}
+
+// GenerateConsensusSequenceParallel creates the same consensus as GenerateConsensusSequence,
+// splitting the positions into contiguous ranges that are counted concurrently. Each
+// consensus column depends only on its own position, so the ranges need no coordination.
+//
+// Parameters:
+//   - sequences ([]string): The DNA sequences to create a consensus from.
+//   - numWorkers (int): The number of goroutines to use (0 = use GOMAXPROCS).
+//
+// Returns:
+//   - (string): A consensus sequence where each position contains the most common base.
+func GenerateConsensusSequenceParallel(sequences []string, numWorkers int) string {
+	if len(sequences) == 0 {
+		return ""
+	}
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	consensus := make([]rune, shortestLength(sequences))
+	if len(consensus) == 0 {
+		return ""
+	}
+
+	workers := min(numWorkers, len(consensus))
+	chunkSize := (len(consensus) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(consensus); start += chunkSize {
+		end := min(start+chunkSize, len(consensus))
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fillConsensus(consensus, nil, sequences, nil, majorityGapThreshold, start, end)
+		}(start, end)
+	}
+	wg.Wait()
+
+	return string(consensus)
+}
+
+// shortestLength returns the length of the shortest sequence.
+func shortestLength(sequences []string) int {
 	minLength := len(sequences[0])
 	for _, seq := range sequences {
 		if len(seq) < minLength {
 			minLength = len(seq)
 		}
 	}
+	return minLength
+}
 
-	// Build the consensus sequence
-	consensus := strings.Builder{}
-
-	for i := 0; i < minLength; i++ {
-		// Count occurrences of each base at this position
-		counts := make(map[rune]int)
-		for _, seq := range sequences {
-			base := rune(seq[i])
-			counts[base]++
-		}
-
-		// Find the most common base
-		var mostCommonBase rune
-		maxCount := 0
-		for base, count := range counts {
-			if count > maxCount {
-				maxCount = count
-				mostCommonBase = base
+// fillConsensus writes the base with the most votes at each position in [start, end)
+// to consensus, breaking ties toward the lowest byte value. Gaps ('-') do not compete
+// with the bases: a position is a gap only if gaps hold more than gapThreshold of the
+// votes. A nil weights slice gives every sequence one vote. If confidence is not nil,
+// it receives the share of the votes at each position that went to the chosen symbol.
+func fillConsensus(consensus []rune, confidence []float64, sequences []string, weights []float64, gapThreshold float64, start, end int) {
+	for i := start; i < end; i++ {
+		// Sum the votes for each base at this position
+		var votes [256]float64
+		totalVotes := 0.0
+		for s, seq := range sequences {
+			weight := 1.0
+			if weights != nil {
+				if s >= len(weights) || weights[s] <= 0 {
+					continue
+				}
+				weight = weights[s]
+			}
+			votes[seq[i]] += weight
+			totalVotes += weight
+		}
+
+		// Gaps win only past the threshold; otherwise they are left out of the vote
+		gapVotes := votes[GapSymbol]
+		votes[GapSymbol] = 0
+
+		// Find the most common base, scanning in byte order so ties are deterministic
+		mostCommonBase := 'N'
+		maxVotes := 0.0
+		for base, vote := range votes {
+			if vote > maxVotes {
+				maxVotes = vote
+				mostCommonBase = rune(base)
+			}
+		}
+		if gapVotes > 0 && gapVotes > gapThreshold*totalVotes {
+			mostCommonBase, maxVotes = GapSymbol, gapVotes
+		}
+
+		consensus[i] = mostCommonBase
+		if confidence != nil && totalVotes > 0 {
+			confidence[i] = maxVotes / totalVotes
+		}
+	}
+}
+
+// ShuffleSequence returns a random permutation of a sequence's bases. The shuffle
+// keeps the base composition exactly, which makes shuffled sequences the usual
+// null model for judging whether an alignment score is better than chance.
+//
+// Parameters:
+//   - seq (string): The sequence to shuffle.
+//   - seed (int64): The seed for the random source, so shuffles are reproducible.
+//
+// Returns:
+//   - (string): The shuffled sequence.
+func ShuffleSequence(seq string, seed int64) string {
+	r := rand.New(rand.NewSource(seed))
+	shuffled := []byte(seq)
+	r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return string(shuffled)
+}
+
+// ReadPair is a simulated paired-end read pair drawn from one fragment of a reference.
+type ReadPair struct {
+	Read1    string // The forward read from the start of the fragment
+	Read2    string // The reverse-complemented read from the end of the fragment
+	Position int    // Start of the fragment in the reference (0-based)
+}
+
+// GeneratePairedReads simulates paired-end sequencing of a reference.
+//
+// Purpose:
+//   - Picks random fragments of insertSize bases from the reference.
+//   - Read1 is the first readLen bases of the fragment, read in the forward direction.
+//   - Read2 is the last readLen bases of the fragment, reverse complemented as
+//     sequenced from the opposite strand.
+//   - Each read is then independently mutated at mutationRate to mimic sequencing errors.
+//
+// Parameters:
+//   - reference (string): The DNA sequence to sample fragments from.
+//   - readLen (int): The length of each read.
+//   - insertSize (int): The length of each fragment (must be at least readLen).
+//   - count (int): The number of read pairs to generate.
+//   - mutationRate (float64): The per-base mutation probability (0 disables mutation).
+//
+// Returns:
+//   - ([]ReadPair): The generated read pairs, or nil if the parameters are invalid.
+func GeneratePairedReads(reference string, readLen, insertSize, count int, mutationRate float64) []ReadPair {
+	if readLen <= 0 || insertSize < readLen || insertSize > len(reference) || count <= 0 {
+		return nil
+	}
+
+	pairs := make([]ReadPair, count)
+	for i := range pairs {
+		// Choose a fragment that fits entirely inside the reference
+		position := globalRand.Intn(len(reference) - insertSize + 1)
+		fragment := reference[position : position+insertSize]
+
+		pairs[i] = ReadPair{
+			Read1:    CreateMutatedSequence(fragment[:readLen], mutationRate),
+			Read2:    CreateMutatedSequence(ReverseComplement(fragment[insertSize-readLen:]), mutationRate),
+			Position: position,
+		}
+	}
+
+	return pairs
+}
+
+// ReverseComplement returns the reverse complement of a DNA sequence: the
+// sequence of the opposite strand, read in its own 5' to 3' direction.
+// Case is preserved, N stays N, and any other character is copied unchanged.
+//
+// Parameters:
+//   - seq (string): The DNA sequence.
+//
+// Returns:
+//   - (string): The reverse complement.
+func ReverseComplement(seq string) string {
+	complement := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		var base byte
+		switch seq[i] {
+		case 'A':
+			base = 'T'
+		case 'T':
+			base = 'A'
+		case 'C':
+			base = 'G'
+		case 'G':
+			base = 'C'
+		case 'a':
+			base = 't'
+		case 't':
+			base = 'a'
+		case 'c':
+			base = 'g'
+		case 'g':
+			base = 'c'
+		default:
+			// N and n are their own complement
+			base = seq[i]
+		}
+		complement[len(seq)-1-i] = base
+	}
+
+	return string(complement)
+}
+
+// SequenceFingerprint returns a short, stable fingerprint of a sequence.
+//
+// The fingerprint is the 64-bit FNV-1a hash of the sequence bytes, encoded as
+// unpadded URL-safe base64 (11 characters). It is cheap to compute and suitable
+// for spotting identical sequences; it is not a cryptographic hash.
+//
+// Parameters:
+//   - seq (string): The sequence to fingerprint.
+//
+// Returns:
+//   - (string): The fingerprint of the sequence.
+func SequenceFingerprint(seq string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seq))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// DedupeSequences removes duplicate sequences while remembering where each one went.
+//
+// Sequences are grouped by fingerprint and then compared directly, so a hash
+// collision never merges two different sequences.
+//
+// Parameters:
+//   - seqs ([]string): The sequences to deduplicate.
+//
+// Returns:
+//   - unique ([]string): The distinct sequences, in order of first appearance.
+//   - mapping ([]int): For each input sequence, its index in unique, so that
+//     seqs[i] == unique[mapping[i]].
+func DedupeSequences(seqs []string) (unique []string, mapping []int) {
+	mapping = make([]int, len(seqs))
+	seen := make(map[string][]int) // fingerprint -> indices into unique
+
+	for i, seq := range seqs {
+		fingerprint := SequenceFingerprint(seq)
+
+		index := -1
+		for _, candidate := range seen[fingerprint] {
+			if unique[candidate] == seq {
+				index = candidate
+				break
+			}
+		}
+
+		if index < 0 {
+			index = len(unique)
+			unique = append(unique, seq)
+			seen[fingerprint] = append(seen[fingerprint], index)
+		}
+		mapping[i] = index
+	}
+
+	return unique, mapping
+}
+
+// GCSkew computes the GC skew, (G-C)/(G+C), over consecutive windows of a sequence.
+//
+// Purpose:
+//   - In bacterial genomes the skew changes sign at the origin and terminus of
+//     replication, so plotting it (or its cumulative sum) helps locate them.
+//
+// Parameters:
+//   - seq (string): The DNA sequence. Lowercase bases are counted like uppercase.
+//   - window (int): The size of each non-overlapping window. A trailing partial
+//     window shorter than this is ignored.
+//
+// Returns:
+//   - ([]float64): The skew of each window, between -1 and 1. Windows with no G
+//     or C have a skew of 0. Returns nil if window is not positive.
+func GCSkew(seq string, window int) []float64 {
+	if window <= 0 {
+		return nil
+	}
+
+	skews := make([]float64, 0, len(seq)/window)
+	for start := 0; start+window <= len(seq); start += window {
+		g, c := 0, 0
+		for i := start; i < start+window; i++ {
+			switch seq[i] {
+			case 'G', 'g':
+				g++
+			case 'C', 'c':
+				c++
 			}
 		}
 
-		consensus.WriteRune(mostCommonBase)
+		if g+c == 0 {
+			skews = append(skews, 0)
+		} else {
+			skews = append(skews, float64(g-c)/float64(g+c))
+		}
+	}
+
+	return skews
+}
+
+// OtherBase is the BaseCounts key under which every byte other than A, C, G, and T is counted.
+const OtherBase byte = 'N'
+
+// BaseCounts counts the bases in a sequence.
+//
+// Purpose:
+//   - Quick QC of generated or input sequences, such as checking the composition of
+//     a simulated genome or spotting a read full of ambiguous bases.
+//
+// Parameters:
+//   - seq (string): The DNA sequence. Lowercase bases are counted like uppercase.
+//
+// Returns:
+//   - (map[byte]int): The count of each of 'A', 'C', 'G', and 'T', plus every other
+//     byte (N, IUPAC codes, gaps, ...) counted together under OtherBase. All five
+//     keys are always present.
+func BaseCounts(seq string) map[byte]int {
+	counts := map[byte]int{'A': 0, 'C': 0, 'G': 0, 'T': 0, OtherBase: 0}
+
+	for i := 0; i < len(seq); i++ {
+		switch seq[i] {
+		case 'A', 'a':
+			counts['A']++
+		case 'C', 'c':
+			counts['C']++
+		case 'G', 'g':
+			counts['G']++
+		case 'T', 't':
+			counts['T']++
+		default:
+			counts[OtherBase]++
+		}
+	}
+
+	return counts
+}
+
+// GCContent returns the fraction of a sequence's bases that are G or C.
+//
+// Parameters:
+//   - seq (string): The DNA sequence. Lowercase bases are counted like uppercase.
+//
+// Returns:
+//   - (float64): The number of G and C bases divided by the sequence length, between
+//     0 and 1. Other bytes such as N count toward the length. Returns 0 for an empty sequence.
+func GCContent(seq string) float64 {
+	if len(seq) == 0 {
+		return 0
+	}
+
+	counts := BaseCounts(seq)
+	return float64(counts['G']+counts['C']) / float64(len(seq))
+}
+
+// BytesPerBase returns the memory allocated per sequence base, as reported by the
+// profiling tools.
+//
+// Parameters:
+//   - total (uint64): The number of bytes allocated.
+//   - bases (int): The number of sequence bases processed.
+//
+// Returns:
+//   - (float64): total divided by bases, or 0 when there are no bases, so empty
+//     input never produces NaN or +Inf.
+func BytesPerBase(total uint64, bases int) float64 {
+	if bases <= 0 {
+		return 0
+	}
+	return float64(total) / float64(bases)
+}
+
+// KmerSet returns the distinct substrings of length k in a sequence.
+//
+// Purpose:
+//   - Cheap similarity screening: sequences that share few k-mers are unlikely to
+//     align well, so comparing k-mer sets can rule out most candidates before any
+//     dynamic programming is done.
+//
+// Parameters:
+//   - seq (string): The sequence. K-mers are taken exactly as written, so mix case
+//     consistently when comparing sets.
+//   - k (int): The k-mer length.
+//
+// Returns:
+//   - (map[string]struct{}): The set of k-mers; empty if k is not positive or longer
+//     than the sequence.
+func KmerSet(seq string, k int) map[string]struct{} {
+	kmers := make(map[string]struct{})
+	if k <= 0 {
+		return kmers
+	}
+
+	for i := 0; i+k <= len(seq); i++ {
+		kmers[seq[i:i+k]] = struct{}{}
+	}
+
+	return kmers
+}
+
+// geneticCode lists the amino acid for every codon in TCAG order: the first base
+// selects a block of 16, the second a block of 4, and the third the entry.
+const geneticCode = "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG"
+
+// codonIndex returns a base's position in TCAG order, or -1 for anything else.
+func codonIndex(base byte) int {
+	switch base {
+	case 'T', 't', 'U', 'u':
+		return 0
+	case 'C', 'c':
+		return 1
+	case 'A', 'a':
+		return 2
+	case 'G', 'g':
+		return 3
+	default:
+		return -1
+	}
+}
+
+// Translate translates a DNA sequence into protein using the standard genetic code.
+//
+// Purpose:
+//   - Protein-level comparison of coding sequences, which tolerates synonymous
+//     changes and pairs with substitution-matrix alignment such as BLOSUM62.
+//
+// Parameters:
+//   - seq (string): The DNA sequence. Lowercase bases and U (RNA) are accepted.
+//   - frame (int): The offset of the first codon: 0, 1, or 2. Other values are
+//     reduced modulo 3.
+//   - stopAtStop (bool): If true, translation ends before the first stop codon;
+//     otherwise stop codons are emitted as '*' and translation continues.
+//
+// Returns:
+//   - (string): One amino acid letter per complete codon. Codons containing N or any
+//     other non-ACGT byte become 'X', and trailing bases that do not fill a codon
+//     are ignored.
+//
+// Example Usage:
+//
+//	protein := Translate("ATGGCCTAAGGG", 0, false) // "MA*G"
+//	orf := Translate("ATGGCCTAAGGG", 0, true)      // "MA"
+func Translate(seq string, frame int, stopAtStop bool) string {
+	frame = (frame%3 + 3) % 3
+
+	protein := make([]byte, 0, max(0, (len(seq)-frame)/3))
+	for i := frame; i+3 <= len(seq); i += 3 {
+		first, second, third := codonIndex(seq[i]), codonIndex(seq[i+1]), codonIndex(seq[i+2])
+
+		aminoAcid := byte('X')
+		if first >= 0 && second >= 0 && third >= 0 {
+			aminoAcid = geneticCode[first*16+second*4+third]
+		}
+		if aminoAcid == '*' && stopAtStop {
+			break
+		}
+		protein = append(protein, aminoAcid)
 	}
 
-	return consensus.String()
+	return string(protein)
 }
@@ -27,6 +27,11 @@ func TestGenerateDNASequence(t *testing.T) {
 		}
 	}
 
+	// Negative lengths produce an empty sequence instead of panicking
+	if seq := GenerateDNASequence(-1); seq != "" {
+		t.Errorf("Expected an empty sequence for length -1, got %q", seq)
+	}
+
 	// Test that sequences are random (different sequences for different calls)
 	seq1 := GenerateDNASequence(100)
 	seq2 := GenerateDNASequence(100)
@@ -91,6 +96,36 @@ func TestCreateSNP(t *testing.T) {
 	}
 }
 
+// TestCreateSNPWithAlphabet checks substitutions from a custom alphabet and that
+// degenerate alphabets return the original instead of looping forever
+func TestCreateSNPWithAlphabet(t *testing.T) {
+	original := "GAUUACA"
+	for i := 0; i < 50; i++ {
+		mutated := CreateSNPWithAlphabet(original, 2, []rune("ACGU"))
+		if mutated[:2] != original[:2] || mutated[3:] != original[3:] {
+			t.Fatalf("Expected only position 2 to change, got %s", mutated)
+		}
+		if mutated[2] == 'U' || !strings.ContainsRune("ACG", rune(mutated[2])) {
+			t.Fatalf("Expected a different symbol from the alphabet at position 2, got %c", mutated[2])
+		}
+	}
+
+	// Every alternative is reachable
+	seen := make(map[byte]bool)
+	for i := 0; i < 200; i++ {
+		seen[CreateSNPWithAlphabet("A", 0, []rune("ACGT"))[0]] = true
+	}
+	if len(seen) != 3 || seen['A'] {
+		t.Errorf("Expected C, G, and T as substitutes for A, got %v", seen)
+	}
+
+	for _, alphabet := range [][]rune{nil, []rune("A"), []rune("AA")} {
+		if mutated := CreateSNPWithAlphabet("AAAA", 1, alphabet); mutated != "AAAA" {
+			t.Errorf("Alphabet %q: expected the original, got %s", string(alphabet), mutated)
+		}
+	}
+}
+
 // TestCreateInsertion tests insertion of sequences
 func TestCreateInsertion(t *testing.T) {
 	// Test with a known sequence
@@ -177,6 +212,93 @@ func TestCreateDeletion(t *testing.T) {
 	}
 }
 
+// TestCreateInsertionDeletionE checks that the error-returning variants apply valid
+// mutations and report the ones that would leave the sequence unchanged
+func TestCreateInsertionDeletionE(t *testing.T) {
+	original := "GATTACA"
+
+	if mutated, err := CreateInsertionE(original, 7, "GG"); err != nil || mutated != "GATTACAGG" {
+		t.Errorf("CreateInsertionE at the end = %q, %v; want GATTACAGG", mutated, err)
+	}
+	if mutated, err := CreateDeletionE(original, 5, 10); err != nil || mutated != "GATTA" {
+		t.Errorf("CreateDeletionE past the end = %q, %v; want GATTA", mutated, err)
+	}
+
+	insertions := []struct {
+		position int
+		inserted string
+		message  string
+	}{
+		{8, "A", "insertion position 8 out of range [0, 7]"},
+		{-1, "A", "insertion position -1 out of range [0, 7]"},
+		{3, "", "empty insertion at position 3"},
+	}
+	for _, tc := range insertions {
+		if _, err := CreateInsertionE(original, tc.position, tc.inserted); err == nil || err.Error() != tc.message {
+			t.Errorf("CreateInsertionE(%d, %q) error = %v, want %q", tc.position, tc.inserted, err, tc.message)
+		}
+	}
+
+	deletions := []struct {
+		position, length int
+		message          string
+	}{
+		{7, 1, "deletion position 7 out of range [0, 7)"},
+		{2, 0, "deletion length 0 at position 2 must be positive"},
+		{2, -1, "deletion length -1 at position 2 must be positive"},
+	}
+	for _, tc := range deletions {
+		if _, err := CreateDeletionE(original, tc.position, tc.length); err == nil || err.Error() != tc.message {
+			t.Errorf("CreateDeletionE(%d, %d) error = %v, want %q", tc.position, tc.length, err, tc.message)
+		}
+	}
+
+	// A negative length used to duplicate bases; the plain variant now leaves the sequence alone
+	if mutated := CreateDeletion(original, 2, -1); mutated != original {
+		t.Errorf("CreateDeletion with a negative length = %q, want %q", mutated, original)
+	}
+}
+
+// TestStructuralVariants checks inversions and tandem duplications, including
+// regions clamped at the sequence end and invalid input
+func TestStructuralVariants(t *testing.T) {
+	original := "GATTACA"
+
+	inversions := []struct {
+		start, length int
+		want          string
+	}{
+		{1, 3, "GAATACA"},  // ATT becomes AAT
+		{0, 7, "TGTAATC"},  // The whole sequence
+		{5, 10, "GATTATG"}, // CA becomes TG, clamped at the end
+		{-1, 2, original},
+		{7, 1, original},
+		{2, 0, original},
+	}
+	for _, tc := range inversions {
+		if got := CreateInversion(original, tc.start, tc.length); got != tc.want {
+			t.Errorf("CreateInversion(%d, %d) = %s, want %s", tc.start, tc.length, got, tc.want)
+		}
+	}
+
+	duplications := []struct {
+		start, length, copies int
+		want                  string
+	}{
+		{1, 3, 1, "GATTATTACA"},
+		{1, 3, 2, "GATTATTATTACA"},
+		{5, 10, 1, "GATTACACA"},
+		{7, 1, 1, original},
+		{2, 0, 1, original},
+		{2, 2, 0, original},
+	}
+	for _, tc := range duplications {
+		if got := CreateTandemDuplication(original, tc.start, tc.length, tc.copies); got != tc.want {
+			t.Errorf("CreateTandemDuplication(%d, %d, %d) = %s, want %s", tc.start, tc.length, tc.copies, got, tc.want)
+		}
+	}
+}
+
 // TestCreateMutatedSequence tests random mutations at a given rate
 func TestCreateMutatedSequence(t *testing.T) {
 	// Create a test sequence
@@ -221,6 +343,34 @@ func TestCreateMutatedSequence(t *testing.T) {
 	}
 }
 
+// TestCreateMutatedSequenceSeeded checks that mutations are reproducible for a given seed
+func TestCreateMutatedSequenceSeeded(t *testing.T) {
+	original := strings.Repeat("GATTACA", 100)
+
+	first := CreateMutatedSequenceSeeded(original, 0.1, 42)
+	second := CreateMutatedSequenceSeeded(original, 0.1, 42)
+	if first != second {
+		t.Error("The same seed produced different mutated sequences")
+	}
+	if first == original {
+		t.Error("Expected some mutations at a rate of 0.1")
+	}
+
+	if other := CreateMutatedSequenceSeeded(original, 0.1, 43); other == first {
+		t.Error("Different seeds produced the same mutated sequence")
+	}
+
+	// The output for a seed is pinned, so recorded seeds keep reproducing the same input
+	if got := CreateMutatedSequenceSeeded(strings.Repeat("GATTACA", 4), 0.3, 42); got != "GCATACGAATTACCACGCACACATGACC" {
+		t.Errorf("Seed 42 now produces %s; the draw sequence must not change", got)
+	}
+
+	// Back-to-back unseeded calls must not collide
+	if CreateMutatedSequence(original, 0.1) == CreateMutatedSequence(original, 0.1) {
+		t.Error("Two unseeded calls produced the same mutated sequence")
+	}
+}
+
 // TestCreateMultipleMutations tests adding a specific number of mutations
 func TestCreateMultipleMutations(t *testing.T) {
 	// Create a test sequence
@@ -307,6 +457,379 @@ func TestGenerateConsensusSequence(t *testing.T) {
 	}
 }
 
+// TestGenerateConsensusSequenceTies checks that tied columns resolve alphabetically whatever the input order
+func TestGenerateConsensusSequenceTies(t *testing.T) {
+	// Every column is a two-way tie
+	sequences := []string{"TGCA", "CATG"}
+
+	for run := 0; run < 20; run++ {
+		if consensus := GenerateConsensusSequence(sequences); consensus != "CACA" {
+			t.Fatalf("Run %d: consensus was %s, expected CACA", run, consensus)
+		}
+	}
+	if consensus := GenerateConsensusSequence([]string{"CATG", "TGCA"}); consensus != "CACA" {
+		t.Errorf("Consensus with the inputs swapped was %s, expected CACA", consensus)
+	}
+}
+
+// TestGenerateConsensusSequenceWeighted checks that a heavier sequence outvotes a majority
+func TestGenerateConsensusSequenceWeighted(t *testing.T) {
+	sequences := []string{"GATTACA", "GATCACA", "GATCACA"}
+
+	if consensus := GenerateConsensusSequenceWeighted(sequences, []float64{3, 1, 1}); consensus != "GATTACA" {
+		t.Errorf("Consensus was %s, expected the high-weight GATTACA", consensus)
+	}
+
+	// Equal weights match the unweighted majority
+	if consensus := GenerateConsensusSequenceWeighted(sequences, []float64{1, 1, 1}); consensus != GenerateConsensusSequence(sequences) {
+		t.Errorf("Equal weights gave %s, expected the majority consensus", consensus)
+	}
+
+	// Sequences without a positive weight do not vote; with no votes at all the base is N
+	if consensus := GenerateConsensusSequenceWeighted(sequences, []float64{0, 1}); consensus != "GATCACA" {
+		t.Errorf("Consensus was %s, expected only the second sequence to vote", consensus)
+	}
+	if consensus := GenerateConsensusSequenceWeighted(sequences, []float64{0, 0, 0}); consensus != "NNNNNNN" {
+		t.Errorf("Consensus without votes was %s, expected NNNNNNN", consensus)
+	}
+}
+
+// TestGenerateGappedConsensus checks that gaps only win past the threshold and
+// never by outnumbering individual bases
+func TestGenerateGappedConsensus(t *testing.T) {
+	tests := []struct {
+		sequences []string
+		threshold float64
+		want      string
+	}{
+		{[]string{"GA-TACA", "GATTACA", "GA-TACA"}, 0.5, "GA-TACA"},
+		{[]string{"GA-TACA", "GATTACA", "GA-TACA"}, 0.7, "GATTACA"},
+		// Two gaps outnumber each base but are not a majority of the four sequences
+		{[]string{"GA-TACA", "GATTACA", "GACTACA", "GA-TACA"}, 0.5, "GACTACA"},
+		{[]string{"GA-TACA", "GATTACA", "GACTACA", "GA-TACA"}, 0, "GA-TACA"},
+		{[]string{"G-A", "G-A"}, 1, "GNA"},
+	}
+
+	for _, tt := range tests {
+		if got := GenerateGappedConsensus(tt.sequences, tt.threshold); got != tt.want {
+			t.Errorf("GenerateGappedConsensus(%v, %g) = %s, want %s", tt.sequences, tt.threshold, got, tt.want)
+		}
+	}
+
+	// The plain consensus uses a strict-majority threshold
+	if got := GenerateConsensusSequence([]string{"GA-TACA", "GATTACA", "GACTACA", "GA-TACA"}); got != "GACTACA" {
+		t.Errorf("GenerateConsensusSequence = %s, want GACTACA", got)
+	}
+	if GenerateGappedConsensus(nil, 0.5) != "" {
+		t.Error("Expected an empty consensus for no sequences")
+	}
+}
+
+// TestConsensusWithConfidence checks the per-position agreement and masking of weak positions
+func TestConsensusWithConfidence(t *testing.T) {
+	sequences := []string{
+		"GATTACA",
+		"GATCACA",
+		"GATTACA",
+		"GAGCACA",
+		"GATTACT",
+	}
+
+	consensus, confidence := ConsensusWithConfidence(sequences)
+	if consensus != GenerateConsensusSequence(sequences) {
+		t.Errorf("Consensus %s differs from GenerateConsensusSequence", consensus)
+	}
+
+	expected := []float64{1, 1, 0.8, 0.6, 1, 1, 0.8}
+	if len(confidence) != len(expected) {
+		t.Fatalf("Expected %d confidences, got %d", len(expected), len(confidence))
+	}
+	for i := range expected {
+		if confidence[i] != expected[i] {
+			t.Errorf("Position %d: confidence %f, expected %f", i, confidence[i], expected[i])
+		}
+	}
+
+	if masked := MaskLowConfidence(consensus, confidence, 0.7); masked != "GATNACA" {
+		t.Errorf("Masked consensus was %s, expected GATNACA", masked)
+	}
+	if masked := MaskLowConfidence(consensus, confidence, 0); masked != consensus {
+		t.Errorf("A zero threshold changed the consensus to %s", masked)
+	}
+
+	if consensus, confidence := ConsensusWithConfidence(nil); consensus != "" || len(confidence) != 0 {
+		t.Errorf("Expected an empty result for no sequences, got %q and %v", consensus, confidence)
+	}
+}
+
+// TestGenerateConsensusSequenceParallel checks that the parallel consensus matches the serial one
+func TestGenerateConsensusSequenceParallel(t *testing.T) {
+	// Four copies of one sequence outvote three random ones, so no column is tied
+	original := GenerateDNASequence(503)
+	sequences := []string{original, original, original, original[:400]}
+	for i := 0; i < 3; i++ {
+		sequences = append(sequences, GenerateDNASequence(450))
+	}
+
+	expected := GenerateConsensusSequence(sequences)
+	if expected != original[:400] {
+		t.Fatalf("Serial consensus does not match the majority sequence")
+	}
+	for _, workers := range []int{1, 3, 8, 1000, 0} {
+		if consensus := GenerateConsensusSequenceParallel(sequences, workers); consensus != expected {
+			t.Errorf("%d workers: parallel consensus differs from the serial consensus", workers)
+		}
+	}
+
+	if GenerateConsensusSequenceParallel(nil, 4) != "" {
+		t.Error("Consensus for no sequences was not empty")
+	}
+	if GenerateConsensusSequenceParallel([]string{"GATTACA", ""}, 4) != "" {
+		t.Error("Consensus including an empty sequence was not empty")
+	}
+}
+
+// TestShuffleSequence checks that shuffling keeps the composition and is reproducible
+func TestShuffleSequence(t *testing.T) {
+	seq := strings.Repeat("GATTACA", 20)
+
+	shuffled := ShuffleSequence(seq, 1)
+	if len(shuffled) != len(seq) {
+		t.Fatalf("Shuffle changed the length from %d to %d", len(seq), len(shuffled))
+	}
+	for _, base := range "ACGT" {
+		if strings.Count(shuffled, string(base)) != strings.Count(seq, string(base)) {
+			t.Errorf("Shuffle changed the count of %c", base)
+		}
+	}
+
+	if shuffled == seq {
+		t.Error("Shuffle left the sequence unchanged")
+	}
+	if ShuffleSequence(seq, 1) != shuffled {
+		t.Error("The same seed produced different shuffles")
+	}
+	if ShuffleSequence(seq, 2) == shuffled {
+		t.Error("Different seeds produced the same shuffle")
+	}
+}
+
+// TestGeneratePairedReads checks read positions, lengths, and orientation
+func TestGeneratePairedReads(t *testing.T) {
+	reference := GenerateDNASequence(500)
+	readLen, insertSize := 50, 200
+
+	pairs := GeneratePairedReads(reference, readLen, insertSize, 20, 0)
+	if len(pairs) != 20 {
+		t.Fatalf("Expected 20 read pairs, got %d", len(pairs))
+	}
+
+	for i, pair := range pairs {
+		// The fragment must fit inside the reference
+		if pair.Position < 0 || pair.Position+insertSize > len(reference) {
+			t.Errorf("Pair %d: fragment at %d does not fit in the reference", i, pair.Position)
+			continue
+		}
+
+		if len(pair.Read1) != readLen || len(pair.Read2) != readLen {
+			t.Errorf("Pair %d: read lengths %d and %d, expected %d", i, len(pair.Read1), len(pair.Read2), readLen)
+		}
+
+		// Without mutations, read 1 is the start of the fragment
+		if expected := reference[pair.Position : pair.Position+readLen]; pair.Read1 != expected {
+			t.Errorf("Pair %d: read 1 is %s, expected %s", i, pair.Read1, expected)
+		}
+
+		// Read 2 is the reverse complement of the end of the fragment
+		end := pair.Position + insertSize
+		if expected := reference[end-readLen : end]; ReverseComplement(pair.Read2) != expected {
+			t.Errorf("Pair %d: reverse complement of read 2 is %s, expected %s", i, ReverseComplement(pair.Read2), expected)
+		}
+	}
+
+	// Invalid parameters
+	if GeneratePairedReads(reference, 50, 40, 5, 0) != nil {
+		t.Error("Expected nil when the insert is shorter than the read")
+	}
+	if GeneratePairedReads(reference, 50, 600, 5, 0) != nil {
+		t.Error("Expected nil when the insert is longer than the reference")
+	}
+}
+
+// TestDedupeSequences checks that duplicates collapse and the mapping points back to them
+func TestDedupeSequences(t *testing.T) {
+	seqs := []string{"GATTACA", "ACGT", "GATTACA", "", "ACGT", "GATTACA"}
+
+	unique, mapping := DedupeSequences(seqs)
+
+	expected := []string{"GATTACA", "ACGT", ""}
+	if len(unique) != len(expected) {
+		t.Fatalf("Expected %d unique sequences, got %d: %v", len(expected), len(unique), unique)
+	}
+	for i := range expected {
+		if unique[i] != expected[i] {
+			t.Errorf("Unique sequence %d is %q, expected %q", i, unique[i], expected[i])
+		}
+	}
+
+	if len(mapping) != len(seqs) {
+		t.Fatalf("Expected a mapping of length %d, got %d", len(seqs), len(mapping))
+	}
+	for i, seq := range seqs {
+		if unique[mapping[i]] != seq {
+			t.Errorf("Sequence %d maps to %q, expected %q", i, unique[mapping[i]], seq)
+		}
+	}
+
+	// Fingerprints are stable and distinguish different sequences
+	if SequenceFingerprint("GATTACA") != SequenceFingerprint("GATTACA") {
+		t.Error("Fingerprint of the same sequence changed between calls")
+	}
+	if SequenceFingerprint("GATTACA") == SequenceFingerprint("GATTACC") {
+		t.Error("Different sequences produced the same fingerprint")
+	}
+}
+
+// TestGCSkew checks the skew across a transition from a G-rich to a C-rich region
+func TestGCSkew(t *testing.T) {
+	// Two G-rich windows, an AT-only window, and two C-rich windows
+	seq := "GGGCAT" + "GGgcAA" + "ATATAT" + "CCCGTA" + "CCCCCC" + "GG"
+
+	skews := GCSkew(seq, 6)
+	expected := []float64{0.5, 0.5, 0, -0.5, -1}
+
+	if len(skews) != len(expected) {
+		t.Fatalf("Expected %d windows, got %d: %v", len(expected), len(skews), skews)
+	}
+	for i := range expected {
+		if skews[i] != expected[i] {
+			t.Errorf("Window %d: skew %f, expected %f", i, skews[i], expected[i])
+		}
+	}
+
+	if GCSkew(seq, 0) != nil {
+		t.Error("Expected nil for a non-positive window")
+	}
+}
+
+// TestGenerateDNASequenceSeeded checks that a seed always reproduces the same sequence
+func TestGenerateDNASequenceSeeded(t *testing.T) {
+	first := GenerateDNASequenceSeeded(200, 42)
+	if len(first) != 200 || strings.Trim(first, "ACGT") != "" {
+		t.Fatalf("Expected 200 DNA bases, got %q", first)
+	}
+	if again := GenerateDNASequenceSeeded(200, 42); again != first {
+		t.Error("The same seed produced different sequences")
+	}
+	if other := GenerateDNASequenceSeeded(200, 43); other == first {
+		t.Error("Different seeds produced the same sequence")
+	}
+	if GenerateDNASequenceSeeded(-1, 42) != "" {
+		t.Error("Expected an empty sequence for a negative length")
+	}
+}
+
+// TestGenerateDNASequenceWeighted checks that the composition follows the weights
+func TestGenerateDNASequenceWeighted(t *testing.T) {
+	// Weights need not sum to 1: this is 80% GC
+	seq, err := GenerateDNASequenceWeighted(20000, map[byte]float64{'G': 4, 'C': 4, 'A': 1, 'T': 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(seq) != 20000 {
+		t.Fatalf("Expected 20000 bases, got %d", len(seq))
+	}
+	if gc := GCContent(seq); gc < 0.77 || gc > 0.83 {
+		t.Errorf("Expected a GC content near 0.8, got %f", gc)
+	}
+
+	// A base with no weight never appears
+	seq, _ = GenerateDNASequenceWeighted(1000, map[byte]float64{'A': 1, 'T': 1})
+	if strings.ContainsAny(seq, "CG") {
+		t.Error("Expected only A and T with no weight on C or G")
+	}
+
+	// An empty map falls back to uniform bases
+	if seq, err := GenerateDNASequenceWeighted(100, nil); err != nil || len(seq) != 100 {
+		t.Errorf("Expected 100 uniform bases for empty weights, got %d (err %v)", len(seq), err)
+	}
+
+	for _, weights := range []map[byte]float64{{'N': 1}, {'a': 1}, {'A': -1, 'C': 2}} {
+		if _, err := GenerateDNASequenceWeighted(10, weights); err == nil {
+			t.Errorf("Expected an error for weights %v", weights)
+		}
+	}
+}
+
+// TestBaseCounts checks per-base counts, lowercase handling, and the catch-all for other bytes
+func TestBaseCounts(t *testing.T) {
+	counts := BaseCounts("GATTACAgcN-")
+	expected := map[byte]int{'A': 3, 'C': 2, 'G': 2, 'T': 2, OtherBase: 2}
+	for base, want := range expected {
+		if counts[base] != want {
+			t.Errorf("Count of %c = %d, expected %d", base, counts[base], want)
+		}
+	}
+
+	if counts := BaseCounts("NNNN"); counts[OtherBase] != 4 || counts['A'] != 0 {
+		t.Errorf("Expected four other bases and no A, got %v", counts)
+	}
+	if counts := BaseCounts(""); len(counts) != 5 || counts['G'] != 0 {
+		t.Errorf("Expected five zero counts for an empty sequence, got %v", counts)
+	}
+}
+
+// TestGCContent checks the GC fraction, including sequences with no G or C and empty input
+func TestGCContent(t *testing.T) {
+	testCases := []struct {
+		seq      string
+		expected float64
+	}{
+		{"GATTACA", 2.0 / 7},
+		{"gcGC", 1},
+		{"ATAT", 0},
+		{"NNNN", 0},
+		{"GCNN", 0.5},
+		{"", 0},
+	}
+
+	for _, tc := range testCases {
+		if got := GCContent(tc.seq); got != tc.expected {
+			t.Errorf("GCContent(%q) = %f, expected %f", tc.seq, got, tc.expected)
+		}
+	}
+}
+
+// TestBytesPerBase checks the ratio and that no bases gives 0 rather than NaN or +Inf
+func TestBytesPerBase(t *testing.T) {
+	if got := BytesPerBase(1000, 0); got != 0 {
+		t.Errorf("BytesPerBase(1000, 0) = %f, want 0", got)
+	}
+	if got := BytesPerBase(1000, 8); got != 125 {
+		t.Errorf("BytesPerBase(1000, 8) = %f, want 125", got)
+	}
+}
+
+// TestKmerSet checks that repeated k-mers are counted once and out-of-range k gives an empty set
+func TestKmerSet(t *testing.T) {
+	kmers := KmerSet("GATTACAGAT", 3)
+	expected := []string{"GAT", "ATT", "TTA", "TAC", "ACA", "CAG", "AGA"}
+	if len(kmers) != len(expected) {
+		t.Errorf("Expected %d distinct 3-mers, got %d: %v", len(expected), len(kmers), kmers)
+	}
+	for _, kmer := range expected {
+		if _, ok := kmers[kmer]; !ok {
+			t.Errorf("Missing 3-mer %s", kmer)
+		}
+	}
+
+	for _, k := range []int{0, -1, 11} {
+		if kmers := KmerSet("GATTACAGAT", k); len(kmers) != 0 {
+			t.Errorf("k=%d: expected an empty set, got %v", k, kmers)
+		}
+	}
+}
+
 // BenchmarkGenerateDNASequence benchmarks sequence generation performance
 func BenchmarkGenerateDNASequence(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -324,3 +847,82 @@ func BenchmarkCreateMutatedSequence(b *testing.B) {
 		CreateMutatedSequence(original, 0.05)
 	}
 }
+
+// BenchmarkGenerateConsensusSequence compares the serial and parallel consensus on 1000 sequences of length 1000
+func BenchmarkGenerateConsensusSequence(b *testing.B) {
+	sequences := make([]string, 1000)
+	for i := range sequences {
+		sequences[i] = GenerateDNASequence(1000)
+	}
+
+	b.Run("Serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			GenerateConsensusSequence(sequences)
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			GenerateConsensusSequenceParallel(sequences, 0)
+		}
+	})
+}
+
+// TestReverseComplement checks complementing, reversal, case, and N handling
+func TestReverseComplement(t *testing.T) {
+	tests := []struct{ seq, want string }{
+		{"", ""},
+		{"GATTACA", "TGTAATC"},
+		{"gattaca", "tgtaatc"},
+		{"AcGtN", "NaCgT"},
+		{"NNAA", "TTNN"},
+	}
+
+	for _, tt := range tests {
+		if got := ReverseComplement(tt.seq); got != tt.want {
+			t.Errorf("ReverseComplement(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+		if got := ReverseComplement(ReverseComplement(tt.seq)); got != tt.seq {
+			t.Errorf("Reverse complement of %q is not an involution, got %q", tt.seq, got)
+		}
+	}
+}
+
+// TestTranslate checks the codon table, reading frames, stop handling, ambiguous
+// codons, and trailing partial codons
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		seq        string
+		frame      int
+		stopAtStop bool
+		want       string
+	}{
+		{"ATGGCCTAAGGG", 0, false, "MA*G"},
+		{"ATGGCCTAAGGG", 0, true, "MA"},
+		{"CATGGCCTAA", 1, false, "MA*"},
+		{"GCATGGCC", 2, false, "MA"},
+		{"atgtgg", 0, false, "MW"},
+		{"AUGUAG", 0, false, "M*"},
+		{"ATGNCCTGA", 0, false, "MX*"},
+		{"ATGGC", 0, false, "M"},
+		{"AT", 0, false, ""},
+		{"TTTGGG", 3, false, "FG"},
+		{"TTTGGG", -2, false, "L"},
+	}
+
+	for _, tt := range tests {
+		if got := Translate(tt.seq, tt.frame, tt.stopAtStop); got != tt.want {
+			t.Errorf("Translate(%q, %d, %v) = %q, want %q", tt.seq, tt.frame, tt.stopAtStop, got, tt.want)
+		}
+	}
+
+	// All 64 codons, checked against a few well-known assignments
+	if len(geneticCode) != 64 {
+		t.Fatalf("Expected 64 codons, got %d", len(geneticCode))
+	}
+	for codon, want := range map[string]string{"TGG": "W", "ATG": "M", "TAA": "*", "TAG": "*", "TGA": "*", "GGC": "G", "AAA": "K", "CGA": "R"} {
+		if got := Translate(codon, 0, false); got != want {
+			t.Errorf("Translate(%q) = %q, want %q", codon, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package data
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// fastaLineWidth is the number of sequence characters per line written by WriteFASTA
+const fastaLineWidth = 60
+
+// Record is one sequence from a FASTA file.
+type Record struct {
+	ID          string // The first word of the header line, without the '>'
+	Description string // The rest of the header line after the ID, if any
+	Sequence    string // The sequence with line breaks and surrounding whitespace removed
+}
+
+// ReadFASTA parses FASTA-formatted sequences.
+//
+// Purpose:
+//   - Reads every record from a FASTA file so real sequences can be aligned.
+//   - Each record starts with a '>' header line; the sequence may span any number
+//     of following lines, which are joined. Blank lines are ignored.
+//
+// Parameters:
+//   - r (io.Reader): The FASTA input.
+//
+// Returns:
+//   - ([]Record): The records in file order. A header with no sequence lines yields
+//     a record with an empty sequence, and a bare ">" a record with an empty ID.
+//   - (error): An error naming the line number if sequence data appears before the
+//     first header, or the underlying read error.
+//
+// Example Usage:
+//
+//	records, err := ReadFASTA(strings.NewReader(">seq1 sample\nGATT\nACA\n"))
+//	// records[0] is {ID: "seq1", Description: "sample", Sequence: "GATTACA"}
+func ReadFASTA(r io.Reader) ([]Record, error) {
+	var records []Record
+	var seq strings.Builder
+
+	// Store the sequence collected for the current record
+	flush := func() {
+		if len(records) > 0 {
+			records[len(records)-1].Sequence = seq.String()
+			seq.Reset()
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ">") {
+			flush()
+
+			// The ID runs up to the first space or tab
+			header := strings.TrimSpace(line[1:])
+			record := Record{ID: header}
+			if i := strings.IndexAny(header, " \t"); i >= 0 {
+				record.ID, record.Description = header[:i], strings.TrimSpace(header[i+1:])
+			}
+			records = append(records, record)
+			continue
+		}
+
+		if len(records) == 0 {
+			return nil, fmt.Errorf("line %d: sequence data before the first FASTA header", lineNum)
+		}
+		seq.WriteString(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return records, nil
+}
+
+// WriteFASTA writes records in FASTA format.
+//
+// Purpose:
+//   - Saves sequences, including gapped aligned sequences, in a format other tools read.
+//   - Each record is a '>' header (the ID, then the description if any) followed by
+//     the sequence wrapped at 60 characters per line. Gap characters are written as-is.
+//
+// Parameters:
+//   - w (io.Writer): The destination.
+//   - records ([]Record): The records to write, in order.
+//
+// Returns:
+//   - (error): The first write error, if any.
+//
+// Example Usage:
+//
+//	err := WriteFASTA(os.Stdout, []Record{{ID: "seq1", Sequence: "GATT-ACA"}})
+func WriteFASTA(w io.Writer, records []Record) error {
+	bw := bufio.NewWriter(w)
+
+	for _, record := range records {
+		header := ">" + record.ID
+		if record.Description != "" {
+			header += " " + record.Description
+		}
+		if _, err := fmt.Fprintln(bw, header); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(record.Sequence); start += fastaLineWidth {
+			end := min(start+fastaLineWidth, len(record.Sequence))
+			if _, err := fmt.Fprintln(bw, record.Sequence[start:end]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
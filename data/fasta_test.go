@@ -0,0 +1,89 @@
+package data
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadFASTA checks multi-line sequences, blank lines, and header parsing
+func TestReadFASTA(t *testing.T) {
+	input := `>seq1 first sample, forward strand
+GATT
+ACA
+
+>seq2
+ACGT
+ACGT
+>empty header only
+>
+AC
+>seq3	tabbed
+  TTTT  
+`
+
+	records, err := ReadFASTA(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Record{
+		{ID: "seq1", Description: "first sample, forward strand", Sequence: "GATTACA"},
+		{ID: "seq2", Sequence: "ACGTACGT"},
+		{ID: "empty", Description: "header only"},
+		{Sequence: "AC"},
+		{ID: "seq3", Description: "tabbed", Sequence: "TTTT"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ReadFASTA() = %+v, want %+v", records, want)
+	}
+
+	// Windows line endings are handled like Unix ones
+	records, err = ReadFASTA(strings.NewReader(">seq1\r\nGATT\r\nACA\r\n"))
+	if err != nil || len(records) != 1 || records[0].Sequence != "GATTACA" {
+		t.Errorf("Expected one record GATTACA from CRLF input, got %+v (err %v)", records, err)
+	}
+
+	if records, err := ReadFASTA(strings.NewReader("")); err != nil || len(records) != 0 {
+		t.Errorf("Expected no records and no error for empty input, got %+v (err %v)", records, err)
+	}
+}
+
+// TestReadFASTAMalformed checks that sequence data without a header is rejected with its line
+func TestReadFASTAMalformed(t *testing.T) {
+	_, err := ReadFASTA(strings.NewReader("\nGATTACA\n>seq1\nACGT\n"))
+	if want := "line 2: sequence data before the first FASTA header"; err == nil || err.Error() != want {
+		t.Errorf("ReadFASTA() error = %v, want %q", err, want)
+	}
+}
+
+// TestWriteFASTA checks header formatting, line wrapping, and a round trip through ReadFASTA
+func TestWriteFASTA(t *testing.T) {
+	records := []Record{
+		{ID: "query", Description: "aligned 0-70", Sequence: strings.Repeat("GATT-ACA", 8) + "GATTAC"},
+		{ID: "reference", Sequence: "GATTTACA"},
+		{ID: "empty"},
+	}
+
+	var buf strings.Builder
+	if err := WriteFASTA(&buf, records); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := ">query aligned 0-70\n" +
+		strings.Repeat("GATT-ACA", 7) + "GATT\n" +
+		"-ACAGATTAC\n" +
+		">reference\nGATTTACA\n" +
+		">empty\n"
+	if buf.String() != want {
+		t.Errorf("WriteFASTA() wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	read, err := ReadFASTA(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading back: %v", err)
+	}
+	if !reflect.DeepEqual(read, records) {
+		t.Errorf("Round trip gave %+v, want %+v", read, records)
+	}
+}
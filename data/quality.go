@@ -0,0 +1,66 @@
+package data
+
+// phredOffset is the ASCII offset of Phred+33 (Sanger and Illumina 1.8+) quality strings.
+const phredOffset = 33
+
+// QualityTrim trims low-quality bases from both ends of a read.
+//
+// Purpose:
+//   - Removing the degraded ends of sequencing reads before alignment, so poor base
+//     calls do not turn into spurious mismatches and clipped alignments.
+//
+// Parameters:
+//   - seq (string): The read sequence.
+//   - qual (string): The Phred+33 quality string, one character per base of seq.
+//   - minQ (byte): The minimum mean Phred quality (for example 20) a window must reach.
+//   - window (int): The number of bases averaged at a time. Values below 1 are treated
+//     as 1, and reads shorter than the window are averaged as a whole.
+//
+// Returns:
+//   - (string, string): The trimmed sequence and its quality string. The window slides
+//     in from each end until its mean quality reaches minQ, and the read is cut at the
+//     start of that window from the front and at its end from the back. Both are empty
+//     if no window reaches minQ or if seq and qual differ in length.
+//
+// Example Usage:
+//
+//	seq, qual := QualityTrim("NNGATTACANN", "##IIIIIII##", 30, 2) // "GATTACA", "IIIIIII"
+func QualityTrim(seq, qual string, minQ byte, window int) (string, string) {
+	if len(seq) != len(qual) || len(seq) == 0 {
+		return "", ""
+	}
+	window = min(max(window, 1), len(qual))
+
+	// passes reports whether the window ending just before end has a mean of at least minQ
+	threshold := int(minQ) * window
+	passes := func(end int) bool {
+		sum := 0
+		for i := end - window; i < end; i++ {
+			sum += int(qual[i]) - phredOffset
+		}
+		return sum >= threshold
+	}
+
+	// Slide in from the front until a window passes
+	start := -1
+	for end := window; end <= len(qual); end++ {
+		if passes(end) {
+			start = end - window
+			break
+		}
+	}
+	if start < 0 {
+		return "", ""
+	}
+
+	// Slide in from the back; a window starting at start is known to pass
+	stop := start + window
+	for end := len(qual); end > stop; end-- {
+		if passes(end) {
+			stop = end
+			break
+		}
+	}
+
+	return seq[start:stop], qual[start:stop]
+}
@@ -0,0 +1,33 @@
+package data
+
+import "testing"
+
+// TestQualityTrim checks trimming from each end, whole-read failures, and the
+// handling of windows larger than the read
+func TestQualityTrim(t *testing.T) {
+	tests := []struct {
+		name, seq, qual string
+		minQ            byte
+		window          int
+		wantSeq         string
+		wantQual        string
+	}{
+		{"both ends", "NNGATTACANN", "##IIIIIII##", 30, 2, "GATTACA", "IIIIIII"},
+		{"already good", "GATTACA", "IIIIIII", 20, 3, "GATTACA", "IIIIIII"},
+		{"3' tail only", "GATTACAAA", "IIIIIII#$", 20, 1, "GATTACA", "IIIIIII"},
+		// Q40 and Q2 average to Q21, so the window covering one of each passes
+		{"window mean", "ACGTAC", "#I#I##", 20, 2, "ACGTA", "#I#I#"},
+		{"all low", "GATTACA", "#######", 20, 3, "", ""},
+		{"window larger than read", "GAT", "I5I", 25, 10, "GAT", "I5I"},
+		{"zero window", "AGA", "#I#", 30, 0, "G", "I"},
+		{"length mismatch", "GATTACA", "III", 20, 2, "", ""},
+		{"empty", "", "", 20, 2, "", ""},
+	}
+
+	for _, tt := range tests {
+		seq, qual := QualityTrim(tt.seq, tt.qual, tt.minQ, tt.window)
+		if seq != tt.wantSeq || qual != tt.wantQual {
+			t.Errorf("%s: QualityTrim = %q/%q, want %q/%q", tt.name, seq, qual, tt.wantSeq, tt.wantQual)
+		}
+	}
+}
@@ -12,22 +12,7 @@ import (
 func printAlignment(query, reference string, score int) {
 	fmt.Println("Alignment:")
 	fmt.Printf("Score: %d\n", score)
-	fmt.Printf("Query:     %s\n", query)
-
-	// Generate the match line
-	matchLine := make([]rune, len(query))
-	for i := 0; i < len(query); i++ {
-		if i < len(reference) && query[i] == reference[i] {
-			matchLine[i] = '|' // Match
-		} else if i < len(reference) && query[i] != '-' && reference[i] != '-' {
-			matchLine[i] = '.' // Mismatch
-		} else {
-			matchLine[i] = ' ' // Gap
-		}
-	}
-
-	fmt.Printf("           %s\n", string(matchLine))
-	fmt.Printf("Reference: %s\n", reference)
+	fmt.Print(align.FormatPairwise(align.AlignmentResult{AlignedQuery: query, AlignedRef: reference}, 0))
 	fmt.Println()
 }
 
@@ -220,28 +205,15 @@ func demonstrateRealWorldExample() {
 	// For long sequences, just print the alignment score and statistics
 	fmt.Printf("Alignment Score: %d\n", result.MaxScore)
 
-	// Count matches, mismatches, and gaps
-	matches, mismatches, queryGaps, refGaps := 0, 0, 0, 0
-	for i := 0; i < len(result.AlignedQuery); i++ {
-		if i < len(result.AlignedRef) {
-			if result.AlignedQuery[i] == '-' {
-				queryGaps++
-			} else if result.AlignedRef[i] == '-' {
-				refGaps++
-			} else if result.AlignedQuery[i] == result.AlignedRef[i] {
-				matches++
-			} else {
-				mismatches++
-			}
-		}
-	}
-
+	stats := align.AlignmentStats(result)
 	fmt.Printf("Alignment Statistics:\n")
-	fmt.Printf("  - Matches: %d\n", matches)
-	fmt.Printf("  - Mismatches: %d\n", mismatches)
-	fmt.Printf("  - Gaps in Query: %d\n", queryGaps)
-	fmt.Printf("  - Gaps in Reference: %d\n", refGaps)
-	fmt.Printf("  - Alignment Length: %d\n", len(result.AlignedQuery))
+	fmt.Printf("  - Matches: %d\n", stats.Matches)
+	fmt.Printf("  - Mismatches: %d\n", stats.Mismatches)
+	fmt.Printf("  - Gaps in Query: %d\n", stats.QueryGaps)
+	fmt.Printf("  - Gaps in Reference: %d\n", stats.RefGaps)
+	fmt.Printf("  - Alignment Length: %d\n", stats.Length)
+	fmt.Printf("  - Identity: %.1f%%\n", stats.PercentIdentity)
+	fmt.Printf("  - Query Coverage: %.1f%%\n", 100*stats.Coverage)
 
 	// Print a sample of the alignment (first 50 characters)
 	fmt.Println("\nSample of the alignment (first 50 characters):")